@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -12,24 +13,42 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
-	"github.com/go-chi/cors"
 	"github.com/go-chi/httprate"
 	"github.com/jackc/pgx/v5/pgxpool"
 	goredis "github.com/redis/go-redis/v9"
 
+	"github.com/willianpsouza/StressTestPlatform/internal/adapters/email"
 	"github.com/willianpsouza/StressTestPlatform/internal/adapters/grafana"
 	"github.com/willianpsouza/StressTestPlatform/internal/adapters/http/handlers"
 	"github.com/willianpsouza/StressTestPlatform/internal/adapters/http/middleware"
 	"github.com/willianpsouza/StressTestPlatform/internal/adapters/postgres"
+	"github.com/willianpsouza/StressTestPlatform/internal/adapters/prometheus"
+	redisadapter "github.com/willianpsouza/StressTestPlatform/internal/adapters/redis"
 	"github.com/willianpsouza/StressTestPlatform/internal/app"
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
 	"github.com/willianpsouza/StressTestPlatform/internal/pkg/config"
+	"github.com/willianpsouza/StressTestPlatform/internal/pkg/logger"
 )
 
 func main() {
 	cfg := config.Load()
 
+	// Structured logging; the standard log package is routed through it too
+	appLogger := logger.New(cfg.Log, os.Stdout)
+	slog.SetDefault(appLogger)
+
 	log.Printf("Starting %s (env=%s, project=%s)", cfg.App.Name, cfg.App.Env, cfg.App.ProjectName)
 
+	if fatal, err := config.CheckJWTSecret(cfg.App.Env, cfg.JWT.Secret); fatal {
+		log.Fatalf("Refusing to start in production: %v", err)
+	} else if err != nil {
+		log.Printf("WARNING: %v; tokens can be forged. Set a random JWT_SECRET before running in production", err)
+	}
+
+	if err := app.ValidateK6Config(&cfg.K6); err != nil {
+		log.Fatalf("Invalid k6 configuration: %v", err)
+	}
+
 	// PostgreSQL
 	dbConfig, err := pgxpool.ParseConfig(cfg.Database.URL)
 	if err != nil {
@@ -68,62 +87,110 @@ func main() {
 	// Repositories
 	userRepo := postgres.NewUserRepository(dbPool)
 	sessionRepo := postgres.NewSessionRepository(dbPool)
+	resetRepo := postgres.NewPasswordResetRepository(dbPool)
+	apiKeyRepo := postgres.NewAPIKeyRepository(dbPool)
 	domainRepo := postgres.NewDomainRepository(dbPool)
 	testRepo := postgres.NewTestRepository(dbPool)
 	execRepo := postgres.NewExecutionRepository(dbPool)
 	scheduleRepo := postgres.NewScheduleRepository(dbPool)
 	settingsRepo := postgres.NewSettingsRepository(dbPool)
 	metricRepo := postgres.NewMetricRepository(dbPool)
+	scriptVersionRepo := postgres.NewScriptVersionRepository(dbPool)
+
+	// Notifications: webhooks always, email when SMTP is configured
+	var mailer domain.Mailer
+	notifiers := app.Notifiers{app.NewWebhookNotifier()}
+	if cfg.SMTP.Host != "" {
+		smtpClient, err := email.NewClient(cfg.SMTP)
+		if err != nil {
+			log.Fatalf("Invalid SMTP configuration: %v", err)
+		}
+		mailer = smtpClient
+		notifiers = append(notifiers, app.NewEmailNotifier(smtpClient, userRepo, cfg.SMTP.NotifyOn, cfg.App.PublicURL))
+		log.Printf("Email enabled via %s:%d (notify on %s)", cfg.SMTP.Host, cfg.SMTP.Port, cfg.SMTP.NotifyOn)
+	}
 
 	// K6 Runner
-	k6Runner := app.NewK6Runner(execRepo, testRepo, metricRepo, cfg.K6)
+	k6Runner := app.NewK6Runner(execRepo, testRepo, domainRepo, metricRepo, redisadapter.NewExecutionEvents(redisClient), notifiers, cfg.K6, appLogger)
 	k6Runner.RecoverOrphans()
 
 	// Services
-	authService := app.NewAuthService(cfg.JWT, userRepo, sessionRepo)
+	authService := app.NewAuthService(cfg.JWT, cfg.Password, userRepo, sessionRepo, resetRepo, grafanaClient, mailer)
+	apiKeyService := app.NewAPIKeyService(apiKeyRepo, userRepo)
 	domainService := app.NewDomainService(domainRepo)
-	testService := app.NewTestService(testRepo, domainRepo, cfg.K6)
-	execService := app.NewExecutionService(execRepo, testRepo, metricRepo, k6Runner)
+	testService := app.NewTestService(testRepo, domainRepo, userRepo, scriptVersionRepo, grafanaClient, cfg.K6)
+	startLimiter := app.NewStartLimiter(redisadapter.NewRateLimiter(redisClient, "ratelimit:starts:"), cfg.K6.MaxStartsPerMinute)
+	idempotencyStore := redisadapter.NewIdempotencyStore(redisClient, "idempotency:executions:")
+	execService := app.NewExecutionService(execRepo, testRepo, metricRepo, k6Runner, startLimiter, idempotencyStore, cfg.K6.IdempotencyTTL)
 	scheduleService := app.NewScheduleService(scheduleRepo, testRepo)
 
-	// Scheduler
-	scheduler := app.NewScheduler(scheduleRepo, execRepo, k6Runner)
-	scheduler.Start()
+	// Scheduler (one leader among the instances sharing Redis dispatches)
+	var scheduler *app.Scheduler
+	if cfg.Scheduler.Enabled {
+		leaderLock := redisadapter.NewLeaderLock(redisClient, "scheduler:leader")
+		scheduler = app.NewScheduler(scheduleRepo, execRepo, testRepo, k6Runner, startLimiter, leaderLock, cfg.Scheduler)
+		scheduler.Start()
+	} else {
+		log.Println("[Scheduler] Disabled (SCHEDULER_ENABLED=false)")
+	}
+
+	// Session cleanup
+	sessionCleaner := app.NewSessionCleaner(sessionRepo, cfg.JWT.SessionCleanupInterval)
+	sessionCleaner.Start()
+
+	// Purge of soft-deleted executions
+	execPurger := app.NewExecutionPurger(execRepo, time.Duration(cfg.Retention.DeletedExecutionDays)*24*time.Hour, cfg.Retention.PurgeInterval)
+	execPurger.Start()
+
+	// Retention of aggregated metrics
+	metricsPurger := app.NewMetricsPurger(metricRepo, execRepo, time.Duration(cfg.Retention.MetricsAggDays)*24*time.Hour, cfg.Retention.PurgeInterval)
+	metricsPurger.Start()
 
 	// Handlers
-	healthHandler := handlers.NewHealthHandler(dbPool, redisClient, cfg)
+	healthHandler := handlers.NewHealthHandler(dbPool, redisClient, execService, cfg)
 	authHandler := handlers.NewAuthHandler(authService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
 	domainHandler := handlers.NewDomainHandler(domainService)
 	testHandler := handlers.NewTestHandler(testService)
 	execHandler := handlers.NewExecutionHandler(execService)
 	dashboardHandler := handlers.NewDashboardHandler(execService)
 	scheduleHandler := handlers.NewScheduleHandler(scheduleService)
-	servicesHandler := handlers.NewServicesHandler(dbPool, redisClient, grafanaClient, settingsRepo)
+	servicesHandler := handlers.NewServicesHandler(dbPool, redisClient, grafanaClient, settingsRepo, cfg.K6.BinaryPath)
 	settingsHandler := handlers.NewSettingsHandler(settingsRepo)
+	grafanaHandler := handlers.NewGrafanaHandler(grafanaClient, settingsRepo)
+	metricsHandler := handlers.NewMetricsHandler(metricsPurger)
+
+	// Prometheus metrics
+	promMetrics := prometheus.NewMetrics(k6Runner, execRepo)
 
 	// Router
 	r := chi.NewRouter()
+	docsHandler := handlers.NewDocsHandler(r, cfg)
 
 	// Global middleware
 	r.Use(chimiddleware.RequestID)
+	r.Use(promMetrics.Instrument)
 	r.Use(chimiddleware.RealIP)
-	r.Use(chimiddleware.Logger)
+	r.Use(middleware.RequestLogger(appLogger))
 	r.Use(chimiddleware.Recoverer)
-	r.Use(chimiddleware.Timeout(60 * time.Second))
-
-	// CORS
-	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins: []string{"*"},
-		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders: []string{"Accept", "Authorization", "Content-Type"},
-		ExposedHeaders: []string{"Link"},
-		MaxAge:         300,
-	}))
+	r.Use(middleware.Timeout(60 * time.Second))
+	r.Use(middleware.CORS(cfg.Server.CORSAllowedOrigins))
 
 	// Health endpoints
 	r.Get("/health", healthHandler.Health)
 	r.Get("/ready", healthHandler.Ready)
 
+	// Prometheus scrape endpoint (unauthenticated); METRICS_ADDR moves it to
+	// its own listener so it can stay on an internal port
+	var metricsSrv *http.Server
+	if cfg.Metrics.Addr == "" {
+		r.Handle("/metrics", promMetrics.Handler())
+	} else {
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("/metrics", promMetrics.Handler())
+		metricsSrv = &http.Server{Addr: cfg.Metrics.Addr, Handler: metricsMux}
+	}
+
 	// API v1
 	r.Route("/api/v1", func(r chi.Router) {
 		// Public auth routes (rate limited)
@@ -132,17 +199,36 @@ func main() {
 			r.Post("/auth/register", authHandler.Register)
 			r.Post("/auth/login", authHandler.Login)
 			r.Post("/auth/refresh", authHandler.Refresh)
+			r.Post("/auth/2fa/login", authHandler.LoginTwoFactor)
+			r.Post("/auth/forgot-password", authHandler.ForgotPassword)
+			r.Post("/auth/reset-password", authHandler.ResetPassword)
+
+			// API docs
+			r.Get("/openapi.json", docsHandler.OpenAPI)
+			r.Get("/docs", docsHandler.UI)
 		})
 
 		// Protected routes
 		r.Group(func(r chi.Router) {
-			r.Use(middleware.Auth(authService))
+			r.Use(middleware.Auth(authService, apiKeyService))
 
 			// Auth
 			r.Post("/auth/logout", authHandler.Logout)
 			r.Get("/auth/me", authHandler.Me)
-			r.Put("/auth/me", authHandler.UpdateProfile)
-			r.Post("/auth/change-password", authHandler.ChangePassword)
+			r.Get("/auth/sessions", authHandler.ListSessions)
+			r.Get("/auth/api-keys", apiKeyHandler.List)
+
+			// Credential management, not allowed with an impersonation token
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RejectImpersonation)
+				r.Put("/auth/me", authHandler.UpdateProfile)
+				r.Post("/auth/change-password", authHandler.ChangePassword)
+				r.Post("/auth/2fa/enable", authHandler.EnableTwoFactor)
+				r.Post("/auth/2fa/verify", authHandler.VerifyTwoFactor)
+				r.Delete("/auth/sessions/{id}", authHandler.RevokeSession)
+				r.Post("/auth/api-keys", apiKeyHandler.Create)
+				r.Delete("/auth/api-keys/{id}", apiKeyHandler.Delete)
+			})
 
 			// Domains
 			r.Get("/domains", domainHandler.List)
@@ -156,27 +242,44 @@ func main() {
 			r.Post("/tests", testHandler.Create)
 			r.Get("/tests/{id}", testHandler.Get)
 			r.Put("/tests/{id}", testHandler.Update)
+			r.Post("/tests/{id}/clone", testHandler.Clone)
+			r.Post("/tests/{id}/estimate", testHandler.Estimate)
 			r.Put("/tests/{id}/script", testHandler.UpdateScript)
 			r.Get("/tests/{id}/script/content", testHandler.GetScriptContent)
 			r.Put("/tests/{id}/script/content", testHandler.SaveScriptContent)
+			r.Get("/tests/{id}/script/versions", testHandler.ListScriptVersions)
+			r.Get("/tests/{id}/script/versions/{version}", testHandler.GetScriptVersion)
+			r.Post("/tests/{id}/script/rollback/{version}", testHandler.RollbackScript)
 			r.Delete("/tests/{id}", testHandler.Delete)
 
 			// Executions
 			r.Get("/executions", execHandler.List)
 			r.Post("/executions", execHandler.Create)
+			r.Post("/executions/bulk-delete", execHandler.BulkDelete)
+			r.Get("/executions/events", execHandler.Events)
 			r.Get("/executions/{id}", execHandler.Get)
+			r.Put("/executions/{id}/notes", execHandler.SetNotes)
+			r.Put("/executions/{id}/baseline", execHandler.SetBaseline)
+			r.Delete("/executions/{id}/baseline", execHandler.ClearBaseline)
 			r.Post("/executions/{id}/cancel", execHandler.Cancel)
 			r.Get("/executions/{id}/logs", execHandler.Logs)
+			r.Get("/executions/{id}/logs/stream", execHandler.StreamLogs)
+			r.Get("/executions/{id}/checks", execHandler.Checks)
 			r.Post("/executions/{id}/recalculate-metrics", execHandler.RecalculateMetrics)
+			r.Get("/executions/{id}/metrics/timeseries", execHandler.Timeseries)
+			r.Get("/executions/{id}/error-samples", execHandler.ErrorSamples)
 			r.Delete("/executions/{id}", execHandler.Delete)
 
 			// Delete all finished executions for a test
+			r.Get("/tests/{id}/executions", execHandler.ListByTest)
 			r.Delete("/tests/{id}/executions", execHandler.DeleteByTest)
+			r.Get("/tests/{id}/metrics/storage", execHandler.MetricStorage)
 
 			// Schedules
 			r.Get("/schedules", scheduleHandler.List)
 			r.Post("/schedules", scheduleHandler.Create)
 			r.Get("/schedules/{id}", scheduleHandler.Get)
+			r.Get("/schedules/{id}/runs", scheduleHandler.ListRuns)
 			r.Put("/schedules/{id}", scheduleHandler.Update)
 			r.Delete("/schedules/{id}", scheduleHandler.Delete)
 			r.Post("/schedules/{id}/pause", scheduleHandler.Pause)
@@ -196,9 +299,16 @@ func main() {
 				r.Get("/users/{id}", authHandler.GetUser)
 				r.Put("/users/{id}", authHandler.UpdateUser)
 				r.Delete("/users/{id}", authHandler.DeleteUser)
+				r.Get("/users/{id}/usage", dashboardHandler.UserUsage)
+				r.Post("/users/{id}/impersonate", authHandler.Impersonate)
+				r.Get("/dashboard/usage", dashboardHandler.Usage)
 
 				r.Get("/settings", settingsHandler.GetAll)
 				r.Put("/settings", settingsHandler.Update)
+				r.Post("/grafana/token", grafanaHandler.CreateToken)
+
+				r.Post("/executions/{id}/restore", execHandler.Restore)
+				r.Post("/metrics/purge", metricsHandler.Purge)
 			})
 		})
 	})
@@ -222,11 +332,24 @@ func main() {
 			log.Fatalf("Server error: %v", err)
 		}
 	}()
+	if metricsSrv != nil {
+		go func() {
+			log.Printf("Metrics listening on %s", metricsSrv.Addr)
+			if err := metricsSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("Metrics server error: %v", err)
+			}
+		}()
+	}
 
 	<-done
 	log.Println("Shutting down server...")
 
-	scheduler.Stop()
+	if scheduler != nil {
+		scheduler.Stop()
+	}
+	sessionCleaner.Stop()
+	execPurger.Stop()
+	metricsPurger.Stop()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -234,6 +357,9 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
+	if metricsSrv != nil {
+		metricsSrv.Shutdown(ctx)
+	}
 
 	log.Println("Server stopped")
 }
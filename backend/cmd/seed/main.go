@@ -44,7 +44,7 @@ func main() {
 		return
 	}
 
-	passwordHash, err := app.HashPassword(password)
+	passwordHash, err := app.HashPasswordWithParams(password, app.Argon2ParamsFromConfig(cfg.Password))
 	if err != nil {
 		log.Fatalf("Failed to hash password: %v", err)
 	}
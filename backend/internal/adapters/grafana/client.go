@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"time"
 
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
 	"github.com/willianpsouza/StressTestPlatform/internal/pkg/config"
 )
 
@@ -31,12 +33,9 @@ func NewClient(cfg config.GrafanaConfig) *Client {
 	}
 }
 
-type GrafanaUser struct {
-	ID    int    `json:"id"`
-	Login string `json:"login"`
-}
-
-func (c *Client) CreateUser(email, name, password string) (*GrafanaUser, error) {
+// CreateUser creates a Grafana user logging in with the email. When the user
+// already exists the existing account is returned.
+func (c *Client) CreateUser(email, name, password string) (*domain.GrafanaUser, error) {
 	body := map[string]interface{}{
 		"name":     name,
 		"email":    email,
@@ -63,7 +62,7 @@ func (c *Client) CreateUser(email, name, password string) (*GrafanaUser, error)
 
 	if resp.StatusCode == http.StatusPreconditionFailed {
 		// User already exists
-		return nil, nil
+		return c.lookupUser(email)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -77,7 +76,33 @@ func (c *Client) CreateUser(email, name, password string) (*GrafanaUser, error)
 		return nil, err
 	}
 
-	return &GrafanaUser{ID: result.ID, Login: email}, nil
+	return &domain.GrafanaUser{ID: result.ID, Login: email}, nil
+}
+
+func (c *Client) lookupUser(loginOrEmail string) (*domain.GrafanaUser, error) {
+	req, err := http.NewRequest("GET", c.url+"/api/users/lookup?loginOrEmail="+url.QueryEscape(loginOrEmail), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(c.adminUser, c.adminPass)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("grafana lookup user request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("grafana lookup user failed (status %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var user domain.GrafanaUser
+	if err := json.Unmarshal(bodyBytes, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
 }
 
 func (c *Client) PublicURL() string {
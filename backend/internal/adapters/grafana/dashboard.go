@@ -0,0 +1,198 @@
+package grafana
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+// metricsAPIURL is the metrics-api address as seen from the Grafana container,
+// matching the provisioned "Metrics API" datasource.
+const metricsAPIURL = "http://metrics-api:8081"
+
+var metricsDatasource = map[string]string{
+	"type": "yesoreyeram-infinity-datasource",
+	"uid":  "stresstest-metrics-api",
+}
+
+// CreateDashboard provisions a dashboard for the test with the domain and test
+// variables fixed to it. It is saved in the given folder (the General folder
+// when folderUID is empty) and overwrites a previous dashboard for the test.
+func (c *Client) CreateDashboard(folderUID string, test domain.Test) (*domain.GrafanaDashboard, error) {
+	body := map[string]interface{}{
+		"dashboard": dashboardModel(test),
+		"overwrite": true,
+		"message":   "Provisioned by StressTest Platform",
+	}
+	if folderUID != "" {
+		body["folderUid"] = folderUID
+	}
+
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", c.url+"/api/dashboards/db", bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(c.adminUser, c.adminPass)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("grafana create dashboard request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("grafana create dashboard failed (status %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var result struct {
+		UID string `json:"uid"`
+		URL string `json:"url"`
+	}
+	if err := json.Unmarshal(bodyBytes, &result); err != nil {
+		return nil, err
+	}
+
+	return &domain.GrafanaDashboard{UID: result.UID, URL: result.URL}, nil
+}
+
+// dashboardUID derives a stable dashboard UID (max 40 chars) from the test ID.
+func dashboardUID(test domain.Test) string {
+	return "test-" + strings.ReplaceAll(test.ID.String(), "-", "")
+}
+
+func dashboardModel(test domain.Test) map[string]interface{} {
+	domainName := ""
+	if test.DomainName != nil {
+		domainName = *test.DomainName
+	}
+
+	return map[string]interface{}{
+		"uid":           dashboardUID(test),
+		"title":         fmt.Sprintf("%s / %s", domainName, test.Name),
+		"tags":          []string{"k6", "stress-test", "test"},
+		"editable":      true,
+		"graphTooltip":  1,
+		"refresh":       "10s",
+		"schemaVersion": 41,
+		"time":          map[string]string{"from": "now-1h", "to": "now"},
+		"templating": map[string]interface{}{
+			"list": []interface{}{
+				constantVariable("domain", "Domain", domainName),
+				constantVariable("test", "Test", test.Name),
+				map[string]interface{}{
+					"name":    "interval_value",
+					"label":   "Interval (s)",
+					"type":    "custom",
+					"query":   "3,5,10,30,60",
+					"current": map[string]interface{}{"selected": true, "text": "5", "value": "5"},
+				},
+			},
+		},
+		"panels": []interface{}{
+			statPanel(1, "Requests", "requests", "short", 0),
+			statPanel(2, "HTTP Failures", "failures", "short", 6),
+			statPanel(3, "Error Rate", "error_rate", "percent", 12),
+			statPanel(4, "Average Response Time", "avg_response", "ms", 18),
+			timeseriesPanel(5, "Request Per Second", "rps", "reqps", 5, []column{{"rps", "RPS"}}),
+			timeseriesPanel(6, "Response Time", "percentiles", "ms", 15, []column{{"median", "Median"}, {"p90", "P90"}, {"p95", "P95"}}),
+			timeseriesPanel(7, "Virtual Users", "vus", "short", 25, []column{{"vus", "VUS"}}),
+			timeseriesPanel(8, "Errors", "errors", "short", 35, []column{{"errors", "Errors"}}),
+		},
+	}
+}
+
+func constantVariable(name, label, value string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":    name,
+		"label":   label,
+		"type":    "constant",
+		"hide":    2,
+		"query":   value,
+		"current": map[string]interface{}{"selected": true, "text": value, "value": value},
+	}
+}
+
+type column struct {
+	selector string
+	text     string
+}
+
+func metricsTarget(path, format string, columns []map[string]string) map[string]interface{} {
+	target := map[string]interface{}{
+		"datasource":    metricsDatasource,
+		"type":          "json",
+		"source":        "url",
+		"url":           metricsAPIURL + path + "?domain=${domain}&test=${test}&from=${__from:date:iso}&to=${__to:date:iso}&interval=${interval_value}",
+		"parser":        "backend",
+		"root_selector": "",
+		"columns":       columns,
+		"refId":         "A",
+		"url_options":   map[string]string{"method": "GET"},
+	}
+	if format != "" {
+		target["format"] = format
+	}
+	return target
+}
+
+func statPanel(id int, title, selector, unit string, x int) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         id,
+		"title":      title,
+		"type":       "stat",
+		"gridPos":    map[string]int{"h": 5, "w": 6, "x": x, "y": 0},
+		"datasource": metricsDatasource,
+		"targets": []interface{}{
+			metricsTarget("/grafana/stats", "", []map[string]string{
+				{"selector": selector, "text": title, "type": "number"},
+			}),
+		},
+		"fieldConfig": map[string]interface{}{
+			"defaults":  map[string]string{"unit": unit},
+			"overrides": []interface{}{},
+		},
+		"options": map[string]interface{}{
+			"reduceOptions": map[string]interface{}{"calcs": []string{"lastNotNull"}, "fields": "", "values": false},
+		},
+	}
+}
+
+func timeseriesPanel(id int, title, path, unit string, y int, columns []column) map[string]interface{} {
+	cols := []map[string]string{{"selector": "time", "text": "Time", "type": "timestamp"}}
+	for _, col := range columns {
+		cols = append(cols, map[string]string{"selector": col.selector, "text": col.text, "type": "number"})
+	}
+
+	return map[string]interface{}{
+		"id":         id,
+		"title":      title,
+		"type":       "timeseries",
+		"gridPos":    map[string]int{"h": 10, "w": 24, "x": 0, "y": y},
+		"datasource": metricsDatasource,
+		"targets":    []interface{}{metricsTarget("/grafana/ts/"+path, "timeseries", cols)},
+		"fieldConfig": map[string]interface{}{
+			"defaults": map[string]interface{}{
+				"unit":   unit,
+				"custom": map[string]interface{}{"drawStyle": "line", "fillOpacity": 20, "lineWidth": 2, "showPoints": "never"},
+			},
+			"overrides": []interface{}{},
+		},
+		"options": map[string]interface{}{
+			"legend":  map[string]interface{}{"calcs": []string{"mean", "max"}, "displayMode": "table", "placement": "bottom"},
+			"tooltip": map[string]string{"mode": "single"},
+		},
+	}
+}
@@ -0,0 +1,83 @@
+package grafana
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+// Folder permission levels accepted by the Grafana folder permissions API.
+const (
+	PermissionView  = 1
+	PermissionEdit  = 2
+	PermissionAdmin = 4
+)
+
+// CreateFolder creates a dashboard folder and returns its UID.
+func (c *Client) CreateFolder(title string) (*domain.GrafanaFolder, error) {
+	jsonBody, _ := json.Marshal(map[string]string{"title": title})
+
+	req, err := http.NewRequest("POST", c.url+"/api/folders", bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(c.adminUser, c.adminPass)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("grafana create folder request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("grafana create folder failed (status %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var result struct {
+		UID   string `json:"uid"`
+		Title string `json:"title"`
+	}
+	if err := json.Unmarshal(bodyBytes, &result); err != nil {
+		return nil, err
+	}
+
+	return &domain.GrafanaFolder{UID: result.UID, Title: result.Title}, nil
+}
+
+// RestrictFolderToUser replaces the folder permissions so that only the given
+// Grafana user (plus org and server admins) can see and edit its dashboards.
+// This drops the default Viewer/Editor role grants.
+func (c *Client) RestrictFolderToUser(folderUID string, grafanaUserID int) error {
+	body := map[string]interface{}{
+		"items": []map[string]int{
+			{"userId": grafanaUserID, "permission": PermissionEdit},
+		},
+	}
+	jsonBody, _ := json.Marshal(body)
+
+	req, err := http.NewRequest("POST", c.url+"/api/folders/"+folderUID+"/permissions", bytes.NewReader(jsonBody))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.adminUser, c.adminPass)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("grafana folder permissions request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("grafana folder permissions failed (status %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+	return nil
+}
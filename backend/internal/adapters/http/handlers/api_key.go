@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/adapters/http/middleware"
+	"github.com/willianpsouza/StressTestPlatform/internal/adapters/http/response"
+	"github.com/willianpsouza/StressTestPlatform/internal/app"
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+type APIKeyHandler struct {
+	apiKeyService *app.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService *app.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyService: apiKeyService}
+}
+
+func (h *APIKeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+
+	keys, err := h.apiKeyService.List(claims.UserID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, keys)
+}
+
+func (h *APIKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+
+	var input domain.CreateAPIKeyInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	key, err := h.apiKeyService.Create(claims.UserID, input)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, key)
+}
+
+func (h *APIKeyHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid API key ID")
+		return
+	}
+
+	if err := h.apiKeyService.Revoke(id, claims.UserID); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
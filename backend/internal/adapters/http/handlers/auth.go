@@ -2,7 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
+	"log"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -50,15 +55,80 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 	userAgent := r.UserAgent()
 
-	result, err := h.authService.Login(input, ip, userAgent)
+	result, challenge, err := h.authService.Login(input, ip, userAgent)
 	if err != nil {
 		response.Error(w, err)
 		return
 	}
+	if challenge != nil {
+		response.OK(w, challenge)
+		return
+	}
 
 	response.OK(w, result)
 }
 
+func (h *AuthHandler) LoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var input domain.TwoFactorLoginInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	ip := r.RemoteAddr
+	if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+		ip = fwd
+	}
+
+	result, err := h.authService.LoginTwoFactor(input, ip, r.UserAgent())
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, result)
+}
+
+func (h *AuthHandler) EnableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+	if claims == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	setup, err := h.authService.EnableTwoFactor(claims.UserID)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, setup)
+}
+
+func (h *AuthHandler) VerifyTwoFactor(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+	if claims == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	var body struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	user, err := h.authService.VerifyTwoFactor(claims.UserID, body.Code)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, user)
+}
+
 func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
 	var body struct {
 		RefreshToken string `json:"refresh_token"`
@@ -77,6 +147,36 @@ func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
 	response.OK(w, result)
 }
 
+func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var input domain.ForgotPasswordInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	// Same response whether or not the email exists
+	if err := h.authService.ForgotPassword(input); err != nil {
+		log.Printf("[ERROR] Forgot password: %v", err)
+	}
+
+	response.OK(w, map[string]string{"message": "If the email is registered, a reset link has been sent"})
+}
+
+func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var input domain.ResetPasswordInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := h.authService.ResetPassword(input); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, map[string]string{"message": "Password has been reset"})
+}
+
 func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	var body struct {
 		RefreshToken string `json:"refresh_token"`
@@ -123,7 +223,7 @@ func (h *AuthHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := h.authService.UpdateProfile(claims.UserID, input)
+	user, err := h.authService.UpdateProfile(claims.UserID, input, claims.SessionID)
 	if err != nil {
 		response.Error(w, err)
 		return
@@ -153,17 +253,47 @@ func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 	response.OK(w, map[string]string{"message": "Password changed successfully"})
 }
 
-// Admin: List users
-func (h *AuthHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
-	filter := domain.UserFilter{
-		Pagination: domain.DefaultPagination(),
+func (h *AuthHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+	if claims == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
 	}
 
-	if q := r.URL.Query(); q.Get("page") != "" {
-		filter.Page = queryInt(q, "page", 1)
+	sessions, err := h.authService.ListSessions(claims.UserID, claims.SessionID)
+	if err != nil {
+		response.Error(w, err)
+		return
 	}
-	if q := r.URL.Query(); q.Get("page_size") != "" {
-		filter.PageSize = queryInt(q, "page_size", 20)
+
+	response.OK(w, sessions)
+}
+
+func (h *AuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+	if claims == nil {
+		response.Unauthorized(w, "Authentication required")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid session ID")
+		return
+	}
+
+	if err := h.authService.RevokeSession(claims.UserID, id); err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// Admin: List users
+func (h *AuthHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	filter := domain.UserFilter{
+		Pagination: queryPagination(r.URL.Query()),
 	}
 	if search := r.URL.Query().Get("search"); search != "" {
 		filter.Search = &search
@@ -195,6 +325,23 @@ func (h *AuthHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 	response.OK(w, user)
 }
 
+// Admin: Impersonate user
+func (h *AuthHandler) Impersonate(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	result, err := h.authService.Impersonate(middleware.GetClaims(r.Context()), id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, result)
+}
+
 // Admin: Update user
 func (h *AuthHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(chi.URLParam(r, "id"))
@@ -234,18 +381,54 @@ func (h *AuthHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	response.NoContent(w)
 }
 
+// queryTags reads the tag filter from repeated and/or comma-separated "tag"
+// parameters; tag_match=all requires every tag instead of any.
+func queryTags(q url.Values) ([]string, bool) {
+	var tags []string
+	for _, v := range q["tag"] {
+		for _, tag := range strings.Split(v, ",") {
+			if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags, q.Get("tag_match") == "all"
+}
+
+// queryTime reads an RFC 3339 timestamp or a YYYY-MM-DD date (UTC). A date
+// given for an upper bound covers the whole day.
+func queryTime(q interface{ Get(string) string }, key string, endOfDay bool) (*time.Time, error) {
+	v := q.Get(key)
+	if v == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return nil, err
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return &t, nil
+}
+
 func queryInt(q interface{ Get(string) string }, key string, defaultValue int) int {
-	val := q.(interface{ Get(string) string }).Get(key)
-	if val == "" {
+	v, err := strconv.Atoi(q.Get(key))
+	if err != nil {
 		return defaultValue
 	}
-	var v int
-	for _, c := range val {
-		if c >= '0' && c <= '9' {
-			v = v*10 + int(c-'0')
-		} else {
-			return defaultValue
-		}
-	}
 	return v
 }
+
+// queryPagination reads page and page_size, falling back to the defaults on
+// missing or malformed values and clamping them to page >= 1 and
+// 1 <= page_size <= domain.MaxPageSize.
+func queryPagination(q interface{ Get(string) string }) domain.Pagination {
+	p := domain.DefaultPagination()
+	p.Page = max(queryInt(q, "page", p.Page), 1)
+	p.PageSize = min(max(queryInt(q, "page_size", p.PageSize), 1), domain.MaxPageSize)
+	return p
+}
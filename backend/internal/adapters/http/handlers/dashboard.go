@@ -3,6 +3,9 @@ package handlers
 import (
 	"net/http"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
 	"github.com/willianpsouza/StressTestPlatform/internal/adapters/http/response"
 	"github.com/willianpsouza/StressTestPlatform/internal/app"
 	"github.com/willianpsouza/StressTestPlatform/internal/domain"
@@ -20,10 +23,8 @@ func NewDashboardHandler(execService *app.ExecutionService) *DashboardHandler {
 func (h *DashboardHandler) ListExecutions(w http.ResponseWriter, r *http.Request) {
 	filter := domain.ExecutionFilter{
 		AllUsers:   true,
-		Pagination: domain.DefaultPagination(),
+		Pagination: queryPagination(r.URL.Query()),
 	}
-	filter.Page = queryInt(r.URL.Query(), "page", 1)
-	filter.PageSize = queryInt(r.URL.Query(), "page_size", 20)
 
 	if status := r.URL.Query().Get("status"); status != "" {
 		s := domain.TestStatus(status)
@@ -49,3 +50,59 @@ func (h *DashboardHandler) Stats(w http.ResponseWriter, r *http.Request) {
 
 	response.OK(w, stats)
 }
+
+// Admin: capacity consumed by one user
+func (h *DashboardHandler) UserUsage(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	filter, ok := usageFilter(w, r)
+	if !ok {
+		return
+	}
+	filter.UserID = &id
+
+	stats, err := h.execService.UserUsage(filter)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, stats)
+}
+
+// Admin: capacity consumed by all users, heaviest first
+func (h *DashboardHandler) Usage(w http.ResponseWriter, r *http.Request) {
+	filter, ok := usageFilter(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.execService.UsageReport(filter)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, report)
+}
+
+func usageFilter(w http.ResponseWriter, r *http.Request) (domain.UsageFilter, bool) {
+	var filter domain.UsageFilter
+	invalid := map[string]string{}
+	var err error
+	if filter.From, err = queryTime(r.URL.Query(), "from", false); err != nil {
+		invalid["from"] = "Must be an RFC 3339 timestamp or a YYYY-MM-DD date"
+	}
+	if filter.To, err = queryTime(r.URL.Query(), "to", true); err != nil {
+		invalid["to"] = "Must be an RFC 3339 timestamp or a YYYY-MM-DD date"
+	}
+	if len(invalid) > 0 {
+		response.ValidationError(w, invalid)
+		return filter, false
+	}
+	return filter, true
+}
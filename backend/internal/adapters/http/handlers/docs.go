@@ -0,0 +1,424 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/adapters/http/openapi"
+	"github.com/willianpsouza/StressTestPlatform/internal/adapters/http/response"
+	"github.com/willianpsouza/StressTestPlatform/internal/app"
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+	"github.com/willianpsouza/StressTestPlatform/internal/pkg/config"
+)
+
+type DocsHandler struct {
+	routes chi.Routes
+	config *config.Config
+
+	once sync.Once
+	spec []byte
+	err  error
+}
+
+// NewDocsHandler serves the OpenAPI document for routes. The document is built
+// on the first request, once every route has been registered.
+func NewDocsHandler(routes chi.Routes, cfg *config.Config) *DocsHandler {
+	return &DocsHandler{routes: routes, config: cfg}
+}
+
+func (h *DocsHandler) OpenAPI(w http.ResponseWriter, r *http.Request) {
+	h.once.Do(func() {
+		doc, undocumented, err := openapi.Build(h.routes, "/api/v1", h.config.App.Name+" API", "1.0.0", apiOperations)
+		if err != nil {
+			h.err = err
+			return
+		}
+		for _, route := range undocumented {
+			log.Printf("[OpenAPI] Route without operation metadata: %s", route)
+		}
+		h.spec, h.err = json.Marshal(doc)
+	})
+	if h.err != nil {
+		log.Printf("[OpenAPI] Failed to build document: %v", h.err)
+		response.InternalError(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(h.spec)
+}
+
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>API Docs</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>`
+
+func (h *DocsHandler) UI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(swaggerUIPage))
+}
+
+type messageBody struct {
+	Message string `json:"message"`
+}
+
+type deletedBody struct {
+	Deleted int64 `json:"deleted"`
+}
+
+type bulkDeleteBody struct {
+	Matched int64 `json:"matched"`
+	Deleted int64 `json:"deleted"` // 0 on a dry run
+	DryRun  bool  `json:"dry_run"`
+}
+
+var scriptForm = openapi.FormField{Name: "script", File: true}
+
+// apiOperations documents the /api/v1 routes registered in cmd/api/main.go,
+// keyed by method and route relative to /api/v1.
+var apiOperations = map[string]openapi.Operation{
+	// Auth (public)
+	openapi.Key("POST", "/auth/register"): {
+		Summary: "Register a user and return tokens", Tag: "Auth", Access: openapi.Public,
+		Request: domain.RegisterInput{}, Response: domain.LoginResponse{}, Status: http.StatusCreated,
+	},
+	openapi.Key("POST", "/auth/login"): {
+		Summary: "Log in; returns a TwoFactorChallenge instead of tokens when 2FA is enabled", Tag: "Auth", Access: openapi.Public,
+		Request: domain.LoginInput{}, Response: domain.LoginResponse{},
+	},
+	openapi.Key("POST", "/auth/refresh"): {
+		Summary: "Exchange a refresh token for new tokens", Tag: "Auth", Access: openapi.Public,
+		Request: struct {
+			RefreshToken string `json:"refresh_token"`
+		}{}, Response: domain.LoginResponse{},
+	},
+	openapi.Key("POST", "/auth/2fa/login"): {
+		Summary: "Complete a login with the challenge token and a TOTP code", Tag: "Auth", Access: openapi.Public,
+		Request: domain.TwoFactorLoginInput{}, Response: domain.LoginResponse{},
+	},
+	openapi.Key("POST", "/auth/forgot-password"): {
+		Summary: "Send a password reset link (always succeeds)", Tag: "Auth", Access: openapi.Public,
+		Request: domain.ForgotPasswordInput{}, Response: messageBody{},
+	},
+	openapi.Key("POST", "/auth/reset-password"): {
+		Summary: "Reset the password with a one-time token", Tag: "Auth", Access: openapi.Public,
+		Request: domain.ResetPasswordInput{}, Response: messageBody{},
+	},
+
+	// Auth (authenticated)
+	openapi.Key("POST", "/auth/logout"): {
+		Summary: "Revoke a refresh token", Tag: "Auth", Access: openapi.Authenticated,
+		Request: struct {
+			RefreshToken string `json:"refresh_token"`
+		}{}, Status: http.StatusNoContent,
+	},
+	openapi.Key("GET", "/auth/me"): {
+		Summary: "Current user", Tag: "Auth", Access: openapi.Authenticated, Response: domain.User{},
+	},
+	openapi.Key("PUT", "/auth/me"): {
+		Summary: "Update the current user's profile", Tag: "Auth", Access: openapi.Authenticated,
+		Request: domain.UpdateProfileInput{}, Response: domain.User{},
+	},
+	openapi.Key("POST", "/auth/change-password"): {
+		Summary: "Change the current user's password", Tag: "Auth", Access: openapi.Authenticated,
+		Request: domain.ChangePasswordInput{}, Response: messageBody{},
+	},
+	openapi.Key("POST", "/auth/2fa/enable"): {
+		Summary: "Start 2FA enrollment", Tag: "Auth", Access: openapi.Authenticated, Response: domain.TwoFactorSetup{},
+	},
+	openapi.Key("POST", "/auth/2fa/verify"): {
+		Summary: "Confirm 2FA enrollment with a TOTP code", Tag: "Auth", Access: openapi.Authenticated,
+		Request: struct {
+			Code string `json:"code"`
+		}{}, Response: domain.User{},
+	},
+	openapi.Key("GET", "/auth/sessions"): {
+		Summary: "List active sessions", Tag: "Auth", Access: openapi.Authenticated, Response: []domain.Session{},
+	},
+	openapi.Key("DELETE", "/auth/sessions/{id}"): {
+		Summary: "Revoke a session", Tag: "Auth", Access: openapi.Authenticated, Status: http.StatusNoContent,
+	},
+	openapi.Key("GET", "/auth/api-keys"): {
+		Summary: "List API keys", Tag: "Auth", Access: openapi.Authenticated, Response: []domain.APIKey{},
+	},
+	openapi.Key("POST", "/auth/api-keys"): {
+		Summary: "Create an API key; the raw key is only returned once", Tag: "Auth", Access: openapi.Authenticated,
+		Request: domain.CreateAPIKeyInput{}, Response: domain.CreatedAPIKey{}, Status: http.StatusCreated,
+	},
+	openapi.Key("DELETE", "/auth/api-keys/{id}"): {
+		Summary: "Revoke an API key", Tag: "Auth", Access: openapi.Authenticated, Status: http.StatusNoContent,
+	},
+
+	// Domains
+	openapi.Key("GET", "/domains"): {
+		Summary: "List domains", Tag: "Domains", Access: openapi.Authenticated,
+		Query: []string{"search", "tag", "tag_match"}, Response: []domain.Domain{}, Paginated: true,
+	},
+	openapi.Key("POST", "/domains"): {
+		Summary: "Create a domain", Tag: "Domains", Access: openapi.Authenticated,
+		Request: domain.CreateDomainInput{}, Response: domain.Domain{}, Status: http.StatusCreated,
+	},
+	openapi.Key("GET", "/domains/{id}"): {
+		Summary: "Get a domain", Tag: "Domains", Access: openapi.Authenticated, Response: domain.Domain{},
+	},
+	openapi.Key("PUT", "/domains/{id}"): {
+		Summary: "Update a domain", Tag: "Domains", Access: openapi.Authenticated,
+		Request: domain.UpdateDomainInput{}, Response: domain.Domain{},
+	},
+	openapi.Key("DELETE", "/domains/{id}"): {
+		Summary: "Delete a domain", Tag: "Domains", Access: openapi.Authenticated, Status: http.StatusNoContent,
+	},
+
+	// Tests
+	openapi.Key("GET", "/tests"): {
+		Summary: "List tests", Tag: "Tests", Access: openapi.Authenticated,
+		Query: []string{"domain_id", "search", "tag", "tag_match"}, Response: []domain.Test{}, Paginated: true,
+	},
+	openapi.Key("POST", "/tests"): {
+		Summary: "Create a test with its k6 script", Tag: "Tests", Access: openapi.Authenticated,
+		Form: []openapi.FormField{
+			{Name: "domain_id"}, {Name: "name"}, {Name: "description"}, {Name: "tags"},
+			{Name: "default_vus"}, {Name: "default_duration"}, {Name: "default_stages"}, {Name: "cooldown"},
+			{Name: "success_status_codes"}, {Name: "webhook_url"}, {Name: "webhook_secret"}, {Name: "thresholds"},
+			{Name: "max_retries"}, {Name: "retry_backoff"}, {Name: "max_memory_mb"}, {Name: "max_cpus"},
+			{Name: "summary_trend_stats"}, {Name: "normalize_urls"}, scriptForm, {Name: "script_entry"},
+		},
+		Request: domain.CreateTestJSONInput{}, Response: domain.Test{}, Status: http.StatusCreated,
+	},
+	openapi.Key("GET", "/tests/{id}"): {
+		Summary: "Get a test", Tag: "Tests", Access: openapi.Authenticated, Response: domain.Test{},
+	},
+	openapi.Key("PUT", "/tests/{id}"): {
+		Summary: "Update a test", Tag: "Tests", Access: openapi.Authenticated,
+		Request: domain.UpdateTestInput{}, Response: domain.Test{},
+	},
+	openapi.Key("POST", "/tests/{id}/clone"): {
+		Summary: "Clone a test and its script", Tag: "Tests", Access: openapi.Authenticated,
+		Request: domain.CloneTestInput{}, Response: domain.Test{}, Status: http.StatusCreated,
+	},
+	openapi.Key("POST", "/tests/{id}/estimate"): {
+		Summary: "Estimate a run's duration and requests without executing it", Tag: "Tests", Access: openapi.Authenticated,
+		Request: domain.EstimateTestInput{}, Response: domain.TestEstimate{},
+	},
+	openapi.Key("PUT", "/tests/{id}/script"): {
+		Summary: "Replace the script with an upload", Tag: "Tests", Access: openapi.Authenticated,
+		Form: []openapi.FormField{scriptForm, {Name: "script_entry"}}, Response: domain.Test{},
+	},
+	openapi.Key("GET", "/tests/{id}/script/content"): {
+		Summary: "Get the script source", Tag: "Tests", Access: openapi.Authenticated,
+		Response: struct {
+			Content string `json:"content"`
+		}{},
+	},
+	openapi.Key("PUT", "/tests/{id}/script/content"): {
+		Summary: "Save the script source", Tag: "Tests", Access: openapi.Authenticated,
+		Request: struct {
+			Content string `json:"content"`
+		}{}, Response: domain.Test{},
+	},
+	openapi.Key("GET", "/tests/{id}/script/versions"): {
+		Summary: "List script versions", Tag: "Tests", Access: openapi.Authenticated, Response: []domain.ScriptVersion{},
+	},
+	openapi.Key("GET", "/tests/{id}/script/versions/{version}"): {
+		Summary: "Get a script version", Tag: "Tests", Access: openapi.Authenticated, Response: domain.ScriptVersion{},
+	},
+	openapi.Key("POST", "/tests/{id}/script/rollback/{version}"): {
+		Summary: "Restore a previous script version", Tag: "Tests", Access: openapi.Authenticated, Response: domain.Test{},
+	},
+	openapi.Key("DELETE", "/tests/{id}"): {
+		Summary: "Delete a test", Tag: "Tests", Access: openapi.Authenticated, Status: http.StatusNoContent,
+	},
+	openapi.Key("GET", "/tests/{id}/executions"): {
+		Summary: "List the executions of a test", Tag: "Executions", Access: openapi.Authenticated,
+		Query: []string{"status", "search", "sort", "order", "from", "to"}, Response: []domain.TestExecution{}, Paginated: true,
+	},
+	openapi.Key("DELETE", "/tests/{id}/executions"): {
+		Summary: "Delete all executions of a test", Tag: "Executions", Access: openapi.Authenticated, Response: deletedBody{},
+	},
+	openapi.Key("GET", "/tests/{id}/metrics/storage"): {
+		Summary: "Metric rows and approximate size kept for a test", Tag: "Executions", Access: openapi.Authenticated,
+		Response: domain.MetricStorageStats{},
+	},
+
+	// Executions
+	openapi.Key("GET", "/executions"): {
+		Summary: "List executions", Tag: "Executions", Access: openapi.Authenticated,
+		Query: []string{"test_id", "status", "search", "sort", "order", "from", "to"}, Response: []domain.TestExecution{}, Paginated: true,
+	},
+	openapi.Key("POST", "/executions"): {
+		Summary: "Start an execution", Tag: "Executions", Access: openapi.Authenticated,
+		Headers: []string{"Idempotency-Key"}, Request: domain.CreateExecutionInput{}, Response: domain.TestExecution{},
+		Status: http.StatusCreated, RateLimited: true,
+	},
+	openapi.Key("POST", "/executions/bulk-delete"): {
+		Summary: "Delete finished executions matching a filter, or count them with dry_run", Tag: "Executions", Access: openapi.Authenticated,
+		Query: []string{"dry_run"}, Request: domain.ExecutionDeleteFilter{}, Response: bulkDeleteBody{},
+	},
+	openapi.Key("GET", "/executions/events"): {
+		Summary: "Execution status changes as server-sent events", Tag: "Executions", Access: openapi.Authenticated,
+		ContentType: "text/event-stream",
+	},
+	openapi.Key("GET", "/executions/{id}"): {
+		Summary: "Get an execution", Tag: "Executions", Access: openapi.Authenticated, Response: domain.TestExecution{},
+	},
+	openapi.Key("PUT", "/executions/{id}/notes"): {
+		Summary: "Set the notes of an execution", Tag: "Executions", Access: openapi.Authenticated,
+		Request: domain.UpdateExecutionNotesInput{}, Response: domain.TestExecution{},
+	},
+	openapi.Key("PUT", "/executions/{id}/baseline"): {
+		Summary: "Make a completed execution the baseline of its test", Tag: "Executions", Access: openapi.Authenticated,
+		Response: domain.Test{},
+	},
+	openapi.Key("DELETE", "/executions/{id}/baseline"): {
+		Summary: "Stop using the execution as its test's baseline", Tag: "Executions", Access: openapi.Authenticated,
+		Response: domain.Test{},
+	},
+	openapi.Key("POST", "/executions/{id}/cancel"): {
+		Summary: "Cancel a running or pending execution", Tag: "Executions", Access: openapi.Authenticated, Response: messageBody{},
+	},
+	openapi.Key("GET", "/executions/{id}/logs"): {
+		Summary: "Stored k6 stdout and stderr", Tag: "Executions", Access: openapi.Authenticated,
+		Response: struct {
+			Stdout *string `json:"stdout"`
+			Stderr *string `json:"stderr"`
+		}{},
+	},
+	openapi.Key("GET", "/executions/{id}/logs/stream"): {
+		Summary: "Live k6 output as server-sent events", Tag: "Executions", Access: openapi.Authenticated,
+		ContentType: "text/event-stream",
+	},
+	openapi.Key("GET", "/executions/{id}/checks"): {
+		Summary: "k6 check results", Tag: "Executions", Access: openapi.Authenticated, Response: domain.CheckResults{},
+	},
+	openapi.Key("POST", "/executions/{id}/recalculate-metrics"): {
+		Summary: "Recompute the metrics summary", Tag: "Executions", Access: openapi.Authenticated, Response: domain.TestExecution{},
+	},
+	openapi.Key("GET", "/executions/{id}/metrics/timeseries"): {
+		Summary: "Requests, rps, response time and failures per interval", Tag: "Executions", Access: openapi.Authenticated,
+		Query: []string{"interval"}, Response: []domain.ExecutionTimeseriesPoint{},
+	},
+	openapi.Key("GET", "/executions/{id}/error-samples"): {
+		Summary: "Failing responses captured by a capture_errors run", Tag: "Executions", Access: openapi.Authenticated,
+		Response: []domain.ErrorSample{},
+	},
+	openapi.Key("DELETE", "/executions/{id}"): {
+		Summary: "Soft-delete an execution", Tag: "Executions", Access: openapi.Authenticated, Status: http.StatusNoContent,
+	},
+	openapi.Key("POST", "/executions/{id}/restore"): {
+		Summary: "Restore a soft-deleted execution (ROOT)", Tag: "Executions", Access: openapi.RootOnly, Response: domain.TestExecution{},
+	},
+
+	// Schedules
+	openapi.Key("GET", "/schedules"): {
+		Summary: "List schedules", Tag: "Schedules", Access: openapi.Authenticated,
+		Query: []string{"test_id", "status"}, Response: []domain.Schedule{}, Paginated: true,
+	},
+	openapi.Key("POST", "/schedules"): {
+		Summary: "Create a schedule", Tag: "Schedules", Access: openapi.Authenticated,
+		Request: domain.CreateScheduleInput{}, Response: domain.Schedule{}, Status: http.StatusCreated,
+	},
+	openapi.Key("GET", "/schedules/{id}"): {
+		Summary: "Get a schedule", Tag: "Schedules", Access: openapi.Authenticated, Response: domain.Schedule{},
+	},
+	openapi.Key("GET", "/schedules/{id}/runs"): {
+		Summary: "Dispatch history of a schedule", Tag: "Schedules", Access: openapi.Authenticated,
+		Response: []domain.ScheduleRun{}, Paginated: true,
+	},
+	openapi.Key("PUT", "/schedules/{id}"): {
+		Summary: "Update a schedule", Tag: "Schedules", Access: openapi.Authenticated,
+		Request: domain.UpdateScheduleInput{}, Response: domain.Schedule{},
+	},
+	openapi.Key("DELETE", "/schedules/{id}"): {
+		Summary: "Delete a schedule", Tag: "Schedules", Access: openapi.Authenticated, Status: http.StatusNoContent,
+	},
+	openapi.Key("POST", "/schedules/{id}/pause"): {
+		Summary: "Pause a schedule", Tag: "Schedules", Access: openapi.Authenticated, Response: domain.Schedule{},
+	},
+	openapi.Key("POST", "/schedules/{id}/resume"): {
+		Summary: "Resume a schedule", Tag: "Schedules", Access: openapi.Authenticated, Response: domain.Schedule{},
+	},
+
+	// Dashboard and services
+	openapi.Key("GET", "/dashboard/executions"): {
+		Summary: "Executions of all users", Tag: "Dashboard", Access: openapi.Authenticated,
+		Query: []string{"status"}, Response: []domain.TestExecution{}, Paginated: true,
+	},
+	openapi.Key("GET", "/dashboard/stats"): {
+		Summary: "Execution counters", Tag: "Dashboard", Access: openapi.Authenticated, Response: map[string]any{},
+	},
+	openapi.Key("GET", "/services/status"): {
+		Summary: "Status of the platform services", Tag: "Dashboard", Access: openapi.Authenticated, Response: []serviceStatus{},
+	},
+
+	// Docs
+	openapi.Key("GET", "/openapi.json"): {
+		Summary: "This OpenAPI document", Tag: "Docs", Access: openapi.Public, ContentType: "application/json",
+	},
+	openapi.Key("GET", "/docs"): {
+		Summary: "Swagger UI", Tag: "Docs", Access: openapi.Public, ContentType: "text/html",
+	},
+
+	// Admin (ROOT)
+	openapi.Key("GET", "/users"): {
+		Summary: "List users", Tag: "Admin", Access: openapi.RootOnly,
+		Query: []string{"search"}, Response: []domain.User{}, Paginated: true,
+	},
+	openapi.Key("GET", "/users/{id}"): {
+		Summary: "Get a user", Tag: "Admin", Access: openapi.RootOnly, Response: domain.User{},
+	},
+	openapi.Key("PUT", "/users/{id}"): {
+		Summary: "Update a user", Tag: "Admin", Access: openapi.RootOnly,
+		Request: domain.UpdateUserInput{}, Response: domain.User{},
+	},
+	openapi.Key("DELETE", "/users/{id}"): {
+		Summary: "Delete a user", Tag: "Admin", Access: openapi.RootOnly, Status: http.StatusNoContent,
+	},
+	openapi.Key("POST", "/users/{id}/impersonate"): {
+		Summary: "Get a short-lived access token acting as the user (no refresh token)", Tag: "Admin", Access: openapi.RootOnly,
+		Response: domain.LoginResponse{},
+	},
+	openapi.Key("GET", "/users/{id}/usage"): {
+		Summary: "Capacity consumed by a user", Tag: "Admin", Access: openapi.RootOnly,
+		Query: []string{"from", "to"}, Response: domain.UsageStats{},
+	},
+	openapi.Key("GET", "/dashboard/usage"): {
+		Summary: "Capacity consumed per user", Tag: "Admin", Access: openapi.RootOnly,
+		Query: []string{"from", "to"}, Response: domain.UsageReport{},
+	},
+	openapi.Key("GET", "/settings"): {
+		Summary: "Platform settings with secrets masked", Tag: "Admin", Access: openapi.RootOnly, Response: map[string]string{},
+	},
+	openapi.Key("PUT", "/settings"): {
+		Summary: "Update platform settings", Tag: "Admin", Access: openapi.RootOnly,
+		Request: map[string]string{}, Response: messageBody{},
+	},
+	openapi.Key("POST", "/metrics/purge"): {
+		Summary: "Purge aggregated metrics older than older_than days", Tag: "Admin", Access: openapi.RootOnly,
+		Query: []string{"older_than"}, Response: app.MetricsPurge{},
+	},
+	openapi.Key("POST", "/grafana/token"): {
+		Summary: "Create and store a Grafana service account token", Tag: "Admin", Access: openapi.RootOnly,
+		Request: struct {
+			Name string `json:"name,omitempty"`
+			Role string `json:"role,omitempty"`
+		}{}, Response: struct {
+			Message      string `json:"message"`
+			GrafanaToken string `json:"grafana_token"`
+		}{}, Status: http.StatusCreated,
+	},
+}
@@ -25,14 +25,13 @@ func (h *DomainHandler) List(w http.ResponseWriter, r *http.Request) {
 	claims := middleware.GetClaims(r.Context())
 
 	filter := domain.DomainFilter{
-		Pagination: domain.DefaultPagination(),
+		Pagination: queryPagination(r.URL.Query()),
 	}
-	filter.Page = queryInt(r.URL.Query(), "page", 1)
-	filter.PageSize = queryInt(r.URL.Query(), "page_size", 20)
 
 	if search := r.URL.Query().Get("search"); search != "" {
 		filter.Search = &search
 	}
+	filter.Tags, filter.MatchAllTags = queryTags(r.URL.Query())
 
 	// Non-ROOT users only see their own domains
 	if string(claims.Role) != "ROOT" {
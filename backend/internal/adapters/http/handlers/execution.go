@@ -2,7 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -30,33 +34,33 @@ func (h *ExecutionHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	exec, err := h.execService.Create(claims.UserID, claims.Role == domain.UserRoleRoot, input)
+	exec, replayed, err := h.execService.CreateIdempotent(claims.UserID, claims.Role == domain.UserRoleRoot,
+		r.Header.Get("Idempotency-Key"), input)
 	if err != nil {
 		response.Error(w, err)
 		return
 	}
 
+	if replayed {
+		w.Header().Set("Idempotent-Replayed", "true")
+		response.OK(w, exec)
+		return
+	}
 	response.Created(w, exec)
 }
 
 func (h *ExecutionHandler) List(w http.ResponseWriter, r *http.Request) {
 	claims := middleware.GetClaims(r.Context())
 
-	filter := domain.ExecutionFilter{
-		Pagination: domain.DefaultPagination(),
+	filter, ok := executionListFilter(w, r)
+	if !ok {
+		return
 	}
-	filter.Page = queryInt(r.URL.Query(), "page", 1)
-	filter.PageSize = queryInt(r.URL.Query(), "page_size", 20)
-
 	if testID := r.URL.Query().Get("test_id"); testID != "" {
 		if id, err := uuid.Parse(testID); err == nil {
 			filter.TestID = &id
 		}
 	}
-	if status := r.URL.Query().Get("status"); status != "" {
-		s := domain.TestStatus(status)
-		filter.Status = &s
-	}
 
 	// Non-ROOT users only see their own executions
 	if string(claims.Role) != "ROOT" {
@@ -72,6 +76,70 @@ func (h *ExecutionHandler) List(w http.ResponseWriter, r *http.Request) {
 	response.Paginated(w, domain.NewPaginatedResult(execs, total, filter.Pagination))
 }
 
+// ListByTest lists the executions of one test, with the same filters as List.
+// A test the caller does not own is a 403, not an empty list.
+func (h *ExecutionHandler) ListByTest(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+
+	testID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid test ID")
+		return
+	}
+
+	filter, ok := executionListFilter(w, r)
+	if !ok {
+		return
+	}
+
+	execs, total, err := h.execService.ListByTest(testID, claims.UserID, claims.Role == domain.UserRoleRoot, filter)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, domain.NewPaginatedResult(execs, total, filter.Pagination))
+}
+
+// executionListFilter reads the pagination, status, search, sort/order and
+// from/to query parameters shared by the execution lists. It writes a 422 and
+// returns false when one is invalid.
+func executionListFilter(w http.ResponseWriter, r *http.Request) (domain.ExecutionFilter, bool) {
+	filter := domain.ExecutionFilter{
+		Pagination: queryPagination(r.URL.Query()),
+	}
+
+	if status := r.URL.Query().Get("status"); status != "" {
+		s := domain.TestStatus(status)
+		filter.Status = &s
+	}
+	if search := r.URL.Query().Get("search"); search != "" {
+		filter.Search = &search
+	}
+
+	invalid := map[string]string{}
+	filter.Sort = r.URL.Query().Get("sort")
+	switch r.URL.Query().Get("order") {
+	case "", "desc":
+	case "asc":
+		filter.Asc = true
+	default:
+		invalid["order"] = "Must be asc or desc"
+	}
+	var err error
+	if filter.From, err = queryTime(r.URL.Query(), "from", false); err != nil {
+		invalid["from"] = "Must be an RFC 3339 timestamp or a YYYY-MM-DD date"
+	}
+	if filter.To, err = queryTime(r.URL.Query(), "to", true); err != nil {
+		invalid["to"] = "Must be an RFC 3339 timestamp or a YYYY-MM-DD date"
+	}
+	if len(invalid) > 0 {
+		response.ValidationError(w, invalid)
+		return filter, false
+	}
+	return filter, true
+}
+
 func (h *ExecutionHandler) Get(w http.ResponseWriter, r *http.Request) {
 	claims := middleware.GetClaims(r.Context())
 
@@ -90,6 +158,66 @@ func (h *ExecutionHandler) Get(w http.ResponseWriter, r *http.Request) {
 	response.OK(w, exec)
 }
 
+func (h *ExecutionHandler) SetNotes(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid execution ID")
+		return
+	}
+
+	var input domain.UpdateExecutionNotesInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	exec, err := h.execService.SetNotes(id, claims.UserID, claims.Role == domain.UserRoleRoot, input)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, exec)
+}
+
+func (h *ExecutionHandler) SetBaseline(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid execution ID")
+		return
+	}
+
+	test, err := h.execService.SetBaseline(id, claims.UserID, claims.Role == domain.UserRoleRoot)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, test)
+}
+
+func (h *ExecutionHandler) ClearBaseline(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid execution ID")
+		return
+	}
+
+	test, err := h.execService.ClearBaseline(id, claims.UserID, claims.Role == domain.UserRoleRoot)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, test)
+}
+
 func (h *ExecutionHandler) Cancel(w http.ResponseWriter, r *http.Request) {
 	claims := middleware.GetClaims(r.Context())
 
@@ -124,6 +252,22 @@ func (h *ExecutionHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	response.NoContent(w)
 }
 
+func (h *ExecutionHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid execution ID")
+		return
+	}
+
+	exec, err := h.execService.Restore(id)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, exec)
+}
+
 func (h *ExecutionHandler) DeleteByTest(w http.ResponseWriter, r *http.Request) {
 	claims := middleware.GetClaims(r.Context())
 
@@ -142,6 +286,58 @@ func (h *ExecutionHandler) DeleteByTest(w http.ResponseWriter, r *http.Request)
 	response.OK(w, map[string]int64{"deleted": deleted})
 }
 
+func (h *ExecutionHandler) MetricStorage(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+
+	testID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid test ID")
+		return
+	}
+
+	stats, err := h.execService.MetricStorage(testID, claims.UserID, claims.Role == domain.UserRoleRoot)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, stats)
+}
+
+// BulkDelete removes the executions matching the filter in the body. With
+// ?dry_run=true it only reports how many would be removed.
+func (h *ExecutionHandler) BulkDelete(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+
+	dryRun := false
+	if v := r.URL.Query().Get("dry_run"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			response.ValidationError(w, map[string]string{"dry_run": "Must be true or false"})
+			return
+		}
+		dryRun = b
+	}
+
+	var filter domain.ExecutionDeleteFilter
+	if err := json.NewDecoder(r.Body).Decode(&filter); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	matched, err := h.execService.BulkDelete(claims.UserID, claims.Role == domain.UserRoleRoot, filter, dryRun)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	deleted := matched
+	if dryRun {
+		deleted = 0
+	}
+	response.OK(w, map[string]any{"matched": matched, "deleted": deleted, "dry_run": dryRun})
+}
+
 func (h *ExecutionHandler) RecalculateMetrics(w http.ResponseWriter, r *http.Request) {
 	claims := middleware.GetClaims(r.Context())
 
@@ -160,6 +356,52 @@ func (h *ExecutionHandler) RecalculateMetrics(w http.ResponseWriter, r *http.Req
 	response.OK(w, exec)
 }
 
+// Timeseries returns the execution's requests, rps, response time and
+// failures per ?interval= seconds (default 5).
+func (h *ExecutionHandler) Timeseries(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid execution ID")
+		return
+	}
+
+	interval := 5
+	if v := r.URL.Query().Get("interval"); v != "" {
+		if interval, err = strconv.Atoi(v); err != nil {
+			response.ValidationError(w, map[string]string{"interval": "Must be a number of seconds"})
+			return
+		}
+	}
+
+	points, err := h.execService.Timeseries(id, claims.UserID, claims.Role == domain.UserRoleRoot, interval)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, points)
+}
+
+func (h *ExecutionHandler) ErrorSamples(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid execution ID")
+		return
+	}
+
+	samples, err := h.execService.ErrorSamples(id, claims.UserID, claims.Role == domain.UserRoleRoot)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, samples)
+}
+
 func (h *ExecutionHandler) Logs(w http.ResponseWriter, r *http.Request) {
 	claims := middleware.GetClaims(r.Context())
 
@@ -180,3 +422,190 @@ func (h *ExecutionHandler) Logs(w http.ResponseWriter, r *http.Request) {
 		"stderr": exec.Stderr,
 	})
 }
+
+func (h *ExecutionHandler) Checks(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid execution ID")
+		return
+	}
+
+	exec, err := h.execService.GetByID(id, claims.UserID, claims.Role == domain.UserRoleRoot)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	checks := exec.CheckResults
+	if checks == nil {
+		checks = domain.CheckResults{}
+	}
+	response.OK(w, checks)
+}
+
+// StreamLogs tails k6 output as Server-Sent Events. Each line is sent as a
+// "stdout" or "stderr" event; an "end" event carries the final status.
+func (h *ExecutionHandler) StreamLogs(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid execution ID")
+		return
+	}
+	isRoot := claims.Role == domain.UserRoleRoot
+
+	exec, sub, err := h.execService.SubscribeLogs(id, claims.UserID, isRoot)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		response.Error(w, fmt.Errorf("streaming not supported"))
+		return
+	}
+	// Runs may outlast the server write timeout
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+
+	// A PENDING execution may not have started streaming yet
+	for sub == nil && isActiveStatus(exec.Status) {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case <-time.After(time.Second):
+		}
+		exec, sub, err = h.execService.SubscribeLogs(id, claims.UserID, isRoot)
+		if err != nil {
+			return
+		}
+	}
+
+	if sub == nil {
+		// Already finished: replay what was stored
+		writeStoredLogs(w, exec)
+		writeSSE(w, "end", map[string]string{"status": string(exec.Status)})
+		flusher.Flush()
+		return
+	}
+	defer sub.Unsubscribe()
+
+	for _, line := range sub.Backlog {
+		writeSSE(w, line.Stream, line.Text)
+	}
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case line, ok := <-sub.Lines:
+			if !ok {
+				status := domain.TestStatusCompleted
+				if final, err := h.execService.GetByID(id, claims.UserID, isRoot); err == nil {
+					status = final.Status
+				}
+				writeSSE(w, "end", map[string]string{"status": string(status)})
+				flusher.Flush()
+				return
+			}
+			writeSSE(w, line.Stream, line.Text)
+			flusher.Flush()
+		}
+	}
+}
+
+// Events streams status changes as Server-Sent Events: one "status" event per
+// transition of the caller's executions (all executions for ROOT).
+func (h *ExecutionHandler) Events(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		response.Error(w, fmt.Errorf("streaming not supported"))
+		return
+	}
+
+	events, err := h.execService.SubscribeEvents(r.Context(), claims.UserID, claims.Role == domain.UserRoleRoot)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			writeSSE(w, "status", event)
+			flusher.Flush()
+		}
+	}
+}
+
+func isActiveStatus(status domain.TestStatus) bool {
+	return status == domain.TestStatusPending || status == domain.TestStatusRunning
+}
+
+func writeStoredLogs(w http.ResponseWriter, exec *domain.TestExecution) {
+	for _, stream := range []struct {
+		name string
+		text *string
+	}{{"stdout", exec.Stdout}, {"stderr", exec.Stderr}} {
+		if stream.text == nil {
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimRight(*stream.text, "\n"), "\n") {
+			writeSSE(w, stream.name, line)
+		}
+	}
+}
+
+// writeSSE writes a single event. Strings are sent as-is (lines never contain
+// newlines); anything else is JSON-encoded.
+func writeSSE(w http.ResponseWriter, event string, data interface{}) {
+	var payload string
+	if s, ok := data.(string); ok {
+		payload = s
+	} else {
+		b, _ := json.Marshal(data)
+		payload = string(b)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+}
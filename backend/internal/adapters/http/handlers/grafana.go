@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/adapters/grafana"
+	"github.com/willianpsouza/StressTestPlatform/internal/adapters/http/response"
+	"github.com/willianpsouza/StressTestPlatform/internal/adapters/postgres"
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+type GrafanaHandler struct {
+	grafClient   *grafana.Client
+	settingsRepo *postgres.SettingsRepository
+}
+
+func NewGrafanaHandler(grafClient *grafana.Client, settingsRepo *postgres.SettingsRepository) *GrafanaHandler {
+	return &GrafanaHandler{grafClient: grafClient, settingsRepo: settingsRepo}
+}
+
+var grafanaRoles = map[string]bool{"Viewer": true, "Editor": true, "Admin": true}
+
+// CreateToken mints a Grafana service-account token with the admin
+// credentials and stores it as the grafana_token setting.
+func (h *GrafanaHandler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	input := struct {
+		Name string `json:"name"`
+		Role string `json:"role"`
+	}{}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			response.BadRequest(w, "Invalid request body")
+			return
+		}
+	}
+	if input.Name == "" {
+		input.Name = "stresstest-platform"
+	}
+	if input.Role == "" {
+		input.Role = "Admin"
+	}
+	if !grafanaRoles[input.Role] {
+		response.ValidationError(w, map[string]string{"role": "Must be Viewer, Editor or Admin"})
+		return
+	}
+
+	token, err := h.grafClient.CreateServiceAccountToken(input.Name, input.Role)
+	if err != nil {
+		log.Printf("[Grafana] Failed to create service account token: %v", err)
+		response.Error(w, domain.NewAppError("GRAFANA_ERROR", "Failed to create Grafana token: "+err.Error(), http.StatusBadGateway))
+		return
+	}
+
+	if err := h.settingsRepo.Set("grafana_token", token); err != nil {
+		response.InternalError(w)
+		return
+	}
+
+	response.Created(w, map[string]string{
+		"message":       "Grafana token created",
+		"grafana_token": maskSecret(token),
+	})
+}
@@ -9,17 +9,19 @@ import (
 	"github.com/redis/go-redis/v9"
 
 	"github.com/willianpsouza/StressTestPlatform/internal/adapters/http/response"
+	"github.com/willianpsouza/StressTestPlatform/internal/app"
 	"github.com/willianpsouza/StressTestPlatform/internal/pkg/config"
 )
 
 type HealthHandler struct {
-	db     *pgxpool.Pool
-	redis  *redis.Client
-	config *config.Config
+	db          *pgxpool.Pool
+	redis       *redis.Client
+	execService *app.ExecutionService
+	config      *config.Config
 }
 
-func NewHealthHandler(db *pgxpool.Pool, redis *redis.Client, cfg *config.Config) *HealthHandler {
-	return &HealthHandler{db: db, redis: redis, config: cfg}
+func NewHealthHandler(db *pgxpool.Pool, redis *redis.Client, execService *app.ExecutionService, cfg *config.Config) *HealthHandler {
+	return &HealthHandler{db: db, redis: redis, execService: execService, config: cfg}
 }
 
 func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
@@ -57,8 +59,16 @@ func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
 		status = http.StatusServiceUnavailable
 	}
 
-	response.JSON(w, status, map[string]interface{}{
+	body := map[string]interface{}{
 		"status": map[bool]string{true: "ok", false: "degraded"}[healthy],
 		"checks": checks,
-	})
+	}
+	// Informational: a long lag means Grafana shows stale data, not that
+	// this instance cannot serve traffic
+	if healthy {
+		if lag, err := h.execService.AggregationLag(); err == nil && lag != nil {
+			body["aggregation_lag_seconds"] = lag.Seconds()
+		}
+	}
+	response.JSON(w, status, body)
 }
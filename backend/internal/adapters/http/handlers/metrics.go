@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/adapters/http/response"
+	"github.com/willianpsouza/StressTestPlatform/internal/app"
+)
+
+type MetricsHandler struct {
+	purger *app.MetricsPurger
+}
+
+func NewMetricsHandler(purger *app.MetricsPurger) *MetricsHandler {
+	return &MetricsHandler{purger: purger}
+}
+
+// Purge removes aggregated metrics of executions older than ?older_than=
+// days (METRICS_AGG_RETENTION_DAYS when omitted).
+func (h *MetricsHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	var age time.Duration
+	if v := r.URL.Query().Get("older_than"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days <= 0 {
+			response.ValidationError(w, map[string]string{"older_than": "Must be a positive number of days"})
+			return
+		}
+		age = time.Duration(days) * 24 * time.Hour
+	}
+
+	result, err := h.purger.PurgeOlderThan(age)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, result)
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+func TestQueryInt(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  int
+	}{
+		{name: "missing", value: "", want: 7},
+		{name: "positive", value: "42", want: 42},
+		{name: "negative", value: "-3", want: -3},
+		{name: "zero", value: "0", want: 0},
+		{name: "trailing garbage", value: "12abc", want: 7},
+		{name: "decimal", value: "1.5", want: 7},
+		{name: "overflow", value: "99999999999999999999", want: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := url.Values{}
+			if tt.value != "" {
+				q.Set("n", tt.value)
+			}
+			if got := queryInt(q, "n", 7); got != tt.want {
+				t.Errorf("queryInt(%q) = %d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryPagination(t *testing.T) {
+	def := domain.DefaultPagination()
+
+	tests := []struct {
+		name     string
+		query    string
+		wantPage int
+		wantSize int
+	}{
+		{name: "defaults", query: "", wantPage: def.Page, wantSize: def.PageSize},
+		{name: "explicit", query: "page=3&page_size=50", wantPage: 3, wantSize: 50},
+		{name: "page below one", query: "page=0", wantPage: 1, wantSize: def.PageSize},
+		{name: "negative page", query: "page=-2", wantPage: 1, wantSize: def.PageSize},
+		{name: "size below one", query: "page_size=0", wantPage: def.Page, wantSize: 1},
+		{name: "size above max", query: "page_size=10000", wantPage: def.Page, wantSize: domain.MaxPageSize},
+		{name: "malformed", query: "page=x&page_size=y", wantPage: def.Page, wantSize: def.PageSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := queryPagination(q)
+			if got.Page != tt.wantPage || got.PageSize != tt.wantSize {
+				t.Errorf("queryPagination(%q) = %+v, want page %d size %d", tt.query, got, tt.wantPage, tt.wantSize)
+			}
+		})
+	}
+}
@@ -43,10 +43,8 @@ func (h *ScheduleHandler) List(w http.ResponseWriter, r *http.Request) {
 	claims := middleware.GetClaims(r.Context())
 
 	filter := domain.ScheduleFilter{
-		Pagination: domain.DefaultPagination(),
+		Pagination: queryPagination(r.URL.Query()),
 	}
-	filter.Page = queryInt(r.URL.Query(), "page", 1)
-	filter.PageSize = queryInt(r.URL.Query(), "page_size", 20)
 
 	if testID := r.URL.Query().Get("test_id"); testID != "" {
 		if id, err := uuid.Parse(testID); err == nil {
@@ -89,6 +87,25 @@ func (h *ScheduleHandler) Get(w http.ResponseWriter, r *http.Request) {
 	response.OK(w, schedule)
 }
 
+func (h *ScheduleHandler) ListRuns(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid schedule ID")
+		return
+	}
+
+	pagination := queryPagination(r.URL.Query())
+	runs, total, err := h.scheduleService.ListRuns(id, claims.UserID, claims.Role == domain.UserRoleRoot, pagination)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Paginated(w, domain.NewPaginatedResult(runs, total, pagination))
+}
+
 func (h *ScheduleHandler) Update(w http.ResponseWriter, r *http.Request) {
 	claims := middleware.GetClaims(r.Context())
 
@@ -22,6 +22,7 @@ type ServicesHandler struct {
 	redis        *redis.Client
 	grafClient   *grafana.Client
 	settingsRepo *postgres.SettingsRepository
+	k6Binary     string
 }
 
 func NewServicesHandler(
@@ -29,12 +30,14 @@ func NewServicesHandler(
 	redis *redis.Client,
 	grafClient *grafana.Client,
 	settingsRepo *postgres.SettingsRepository,
+	k6Binary string,
 ) *ServicesHandler {
 	return &ServicesHandler{
 		db:           db,
 		redis:        redis,
 		grafClient:   grafClient,
 		settingsRepo: settingsRepo,
+		k6Binary:     k6Binary,
 	}
 }
 
@@ -106,7 +109,7 @@ func (h *ServicesHandler) CheckServices(w http.ResponseWriter, r *http.Request)
 	services = append(services, metricsStatus)
 
 	// K6 Engine
-	k6Path, err := exec.LookPath("k6")
+	k6Path, err := exec.LookPath(h.k6Binary)
 	if err != nil {
 		services = append(services, serviceStatus{Name: "k6", Status: "error", Message: "k6 binary not found"})
 	} else {
@@ -25,8 +25,8 @@ func (h *SettingsHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	// Mask sensitive values
 	masked := make(map[string]string)
 	for k, v := range settings {
-		if k == "grafana_token" && len(v) > 8 {
-			masked[k] = v[:4] + "..." + v[len(v)-4:]
+		if k == "grafana_token" {
+			masked[k] = maskSecret(v)
 		} else {
 			masked[k] = v
 		}
@@ -34,6 +34,14 @@ func (h *SettingsHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	response.OK(w, masked)
 }
 
+// maskSecret keeps only the first and last four characters of long secrets.
+func maskSecret(v string) string {
+	if len(v) <= 8 {
+		return v
+	}
+	return v[:4] + "..." + v[len(v)-4:]
+}
+
 func (h *SettingsHandler) Update(w http.ResponseWriter, r *http.Request) {
 	var input map[string]string
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
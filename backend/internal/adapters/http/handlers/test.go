@@ -2,8 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"mime"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -26,10 +29,8 @@ func (h *TestHandler) List(w http.ResponseWriter, r *http.Request) {
 	claims := middleware.GetClaims(r.Context())
 
 	filter := domain.TestFilter{
-		Pagination: domain.DefaultPagination(),
+		Pagination: queryPagination(r.URL.Query()),
 	}
-	filter.Page = queryInt(r.URL.Query(), "page", 1)
-	filter.PageSize = queryInt(r.URL.Query(), "page_size", 20)
 
 	if domainID := r.URL.Query().Get("domain_id"); domainID != "" {
 		if id, err := uuid.Parse(domainID); err == nil {
@@ -39,6 +40,7 @@ func (h *TestHandler) List(w http.ResponseWriter, r *http.Request) {
 	if search := r.URL.Query().Get("search"); search != "" {
 		filter.Search = &search
 	}
+	filter.Tags, filter.MatchAllTags = queryTags(r.URL.Query())
 
 	// Non-ROOT users only see their own tests
 	if string(claims.Role) != "ROOT" {
@@ -57,6 +59,11 @@ func (h *TestHandler) List(w http.ResponseWriter, r *http.Request) {
 func (h *TestHandler) Create(w http.ResponseWriter, r *http.Request) {
 	claims := middleware.GetClaims(r.Context())
 
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "application/json" {
+		h.createFromJSON(w, r)
+		return
+	}
+
 	if err := r.ParseMultipartForm(2 << 20); err != nil {
 		response.BadRequest(w, "Invalid multipart form")
 		return
@@ -73,6 +80,7 @@ func (h *TestHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 	input.DomainID = domainID
 	input.Name = r.FormValue("name")
+	input.ScriptEntry = r.FormValue("script_entry")
 	input.DefaultDuration = r.FormValue("default_duration")
 	if input.DefaultDuration == "" {
 		input.DefaultDuration = "30s"
@@ -86,6 +94,75 @@ func (h *TestHandler) Create(w http.ResponseWriter, r *http.Request) {
 	if desc := r.FormValue("description"); desc != "" {
 		input.Description = &desc
 	}
+	input.Cooldown = r.FormValue("cooldown")
+	if webhookURL := r.FormValue("webhook_url"); webhookURL != "" {
+		input.WebhookURL = &webhookURL
+	}
+	if webhookSecret := r.FormValue("webhook_secret"); webhookSecret != "" {
+		input.WebhookSecret = &webhookSecret
+	}
+	if tags := r.FormValue("tags"); tags != "" {
+		input.Tags = strings.Split(tags, ",")
+	}
+	if codes := r.FormValue("success_status_codes"); codes != "" {
+		for _, c := range strings.Split(codes, ",") {
+			code, err := strconv.Atoi(strings.TrimSpace(c))
+			if err != nil {
+				response.BadRequest(w, "Invalid success_status_codes")
+				return
+			}
+			input.SuccessStatusCodes = append(input.SuccessStatusCodes, code)
+		}
+	}
+
+	if maxRetries := r.FormValue("max_retries"); maxRetries != "" {
+		v, err := strconv.Atoi(maxRetries)
+		if err != nil {
+			response.BadRequest(w, "Invalid max_retries")
+			return
+		}
+		input.MaxRetries = v
+	}
+	input.RetryBackoff = r.FormValue("retry_backoff")
+	if maxMemory := r.FormValue("max_memory_mb"); maxMemory != "" {
+		v, err := strconv.Atoi(maxMemory)
+		if err != nil {
+			response.BadRequest(w, "Invalid max_memory_mb")
+			return
+		}
+		input.MaxMemoryMB = &v
+	}
+	if maxCPUs := r.FormValue("max_cpus"); maxCPUs != "" {
+		v, err := strconv.ParseFloat(maxCPUs, 64)
+		if err != nil {
+			response.BadRequest(w, "Invalid max_cpus")
+			return
+		}
+		input.MaxCPUs = &v
+	}
+	if stats := r.FormValue("summary_trend_stats"); stats != "" {
+		input.SummaryTrendStats = strings.Split(stats, ",")
+	}
+	if normalize := r.FormValue("normalize_urls"); normalize != "" {
+		v, err := strconv.ParseBool(normalize)
+		if err != nil {
+			response.BadRequest(w, "Invalid normalize_urls")
+			return
+		}
+		input.NormalizeURLs = v
+	}
+	if thresholds := r.FormValue("thresholds"); thresholds != "" {
+		if err := json.Unmarshal([]byte(thresholds), &input.Thresholds); err != nil {
+			response.BadRequest(w, "Invalid thresholds")
+			return
+		}
+	}
+	if stages := r.FormValue("default_stages"); stages != "" {
+		if err := json.Unmarshal([]byte(stages), &input.DefaultStages); err != nil {
+			response.BadRequest(w, "Invalid default_stages")
+			return
+		}
+	}
 
 	// Get script file
 	file, header, err := r.FormFile("script")
@@ -104,6 +181,36 @@ func (h *TestHandler) Create(w http.ResponseWriter, r *http.Request) {
 	response.Created(w, test)
 }
 
+// createFromJSON creates a test from a JSON body carrying the script inline in
+// script_content. The body may be about twice the script limit, to leave room
+// for JSON escaping and the other fields; the service checks the script itself.
+func (h *TestHandler) createFromJSON(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+
+	maxScript := h.testService.MaxScriptBytes()
+	r.Body = http.MaxBytesReader(w, r.Body, int64(maxScript)*2+(1<<20))
+
+	var input domain.CreateTestJSONInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			response.ValidationError(w, map[string]string{
+				"script_content": "Request body is too large for the script size limit",
+			})
+			return
+		}
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	test, err := h.testService.CreateFromContent(claims.UserID, claims.Role == domain.UserRoleRoot, input)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, test)
+}
+
 func (h *TestHandler) Get(w http.ResponseWriter, r *http.Request) {
 	claims := middleware.GetClaims(r.Context())
 
@@ -146,6 +253,58 @@ func (h *TestHandler) Update(w http.ResponseWriter, r *http.Request) {
 	response.OK(w, test)
 }
 
+func (h *TestHandler) Clone(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid test ID")
+		return
+	}
+
+	var input domain.CloneTestInput
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			response.BadRequest(w, "Invalid request body")
+			return
+		}
+	}
+
+	test, err := h.testService.Clone(id, claims.UserID, claims.Role == domain.UserRoleRoot, input)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.Created(w, test)
+}
+
+func (h *TestHandler) Estimate(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid test ID")
+		return
+	}
+
+	var input domain.EstimateTestInput
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			response.BadRequest(w, "Invalid request body")
+			return
+		}
+	}
+
+	estimate, err := h.testService.Estimate(id, claims.UserID, claims.Role == domain.UserRoleRoot, input)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, estimate)
+}
+
 func (h *TestHandler) UpdateScript(w http.ResponseWriter, r *http.Request) {
 	claims := middleware.GetClaims(r.Context())
 
@@ -167,7 +326,7 @@ func (h *TestHandler) UpdateScript(w http.ResponseWriter, r *http.Request) {
 	}
 	defer file.Close()
 
-	test, err := h.testService.UpdateScript(id, claims.UserID, claims.Role == domain.UserRoleRoot, header.Filename, file, header.Size)
+	test, err := h.testService.UpdateScript(id, claims.UserID, claims.Role == domain.UserRoleRoot, header.Filename, r.FormValue("script_entry"), file, header.Size)
 	if err != nil {
 		response.Error(w, err)
 		return
@@ -220,6 +379,70 @@ func (h *TestHandler) SaveScriptContent(w http.ResponseWriter, r *http.Request)
 	response.OK(w, test)
 }
 
+func (h *TestHandler) ListScriptVersions(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid test ID")
+		return
+	}
+
+	versions, err := h.testService.ListScriptVersions(id, claims.UserID, claims.Role == domain.UserRoleRoot)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, versions)
+}
+
+func (h *TestHandler) GetScriptVersion(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid test ID")
+		return
+	}
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil || version < 1 {
+		response.BadRequest(w, "Invalid version")
+		return
+	}
+
+	v, err := h.testService.GetScriptVersion(id, version, claims.UserID, claims.Role == domain.UserRoleRoot)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, v)
+}
+
+func (h *TestHandler) RollbackScript(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetClaims(r.Context())
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, "Invalid test ID")
+		return
+	}
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil || version < 1 {
+		response.BadRequest(w, "Invalid version")
+		return
+	}
+
+	test, err := h.testService.RollbackScript(id, version, claims.UserID, claims.Role == domain.UserRoleRoot)
+	if err != nil {
+		response.Error(w, err)
+		return
+	}
+
+	response.OK(w, test)
+}
+
 func (h *TestHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	claims := middleware.GetClaims(r.Context())
 
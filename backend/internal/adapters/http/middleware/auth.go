@@ -16,9 +16,22 @@ const (
 	ClaimsContextKey contextKey = "claims"
 )
 
-func Auth(authService *app.AuthService) func(next http.Handler) http.Handler {
+// Auth accepts either a Bearer access token or an X-API-Key header.
+func Auth(authService *app.AuthService, apiKeyService *app.APIKeyService) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if apiKey := r.Header.Get("X-API-Key"); apiKey != "" {
+				claims, err := apiKeyService.Authenticate(apiKey)
+				if err != nil {
+					response.Error(w, domain.NewUnauthorizedError("Invalid or expired API key"))
+					return
+				}
+				setLogUser(r.Context(), claims)
+				ctx := context.WithValue(r.Context(), ClaimsContextKey, claims)
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" {
 				response.Error(w, domain.NewUnauthorizedError("Missing authorization header"))
@@ -37,6 +50,7 @@ func Auth(authService *app.AuthService) func(next http.Handler) http.Handler {
 				return
 			}
 
+			setLogUser(r.Context(), claims)
 			ctx := context.WithValue(r.Context(), ClaimsContextKey, claims)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
@@ -70,6 +84,18 @@ func RequireRole(roles ...string) func(next http.Handler) http.Handler {
 	}
 }
 
+// RejectImpersonation blocks impersonation tokens, so a ROOT acting as a user
+// cannot change that user's credentials or mint longer-lived ones (API keys).
+func RejectImpersonation(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if claims := GetClaims(r.Context()); claims != nil && claims.ActorID != nil {
+			response.Error(w, domain.NewForbiddenError("Not allowed while impersonating"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 func GetClaims(ctx context.Context) *domain.TokenClaims {
 	claims, ok := ctx.Value(ClaimsContextKey).(*domain.TokenClaims)
 	if !ok {
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+func TestRejectImpersonation(t *testing.T) {
+	actor := uuid.New()
+	tests := []struct {
+		name   string
+		claims *domain.TokenClaims
+		want   int
+	}{
+		{"regular token", &domain.TokenClaims{UserID: uuid.New(), Role: domain.UserRoleUser}, http.StatusNoContent},
+		{"impersonation token", &domain.TokenClaims{UserID: uuid.New(), Role: domain.UserRoleUser, ActorID: &actor}, http.StatusForbidden},
+	}
+
+	handler := RejectImpersonation(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/auth/api-keys", nil)
+			req = req.WithContext(context.WithValue(req.Context(), ClaimsContextKey, tt.claims))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
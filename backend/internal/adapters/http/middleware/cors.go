@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/go-chi/cors"
+)
+
+// CORS reflects the request origin only when it is in allowedOrigins.
+// Credentials are never allowed together with "*".
+func CORS(allowedOrigins []string) func(next http.Handler) http.Handler {
+	return cors.Handler(cors.Options{
+		AllowedOrigins:   allowedOrigins,
+		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-API-Key", "Idempotency-Key"},
+		ExposedHeaders:   []string{"Link", "Idempotent-Replayed"},
+		AllowCredentials: !slices.Contains(allowedOrigins, "*"),
+		MaxAge:           300,
+	})
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+const logUserContextKey contextKey = "log_user"
+
+// logUser is who made the request: the user, and the ROOT user acting as
+// them when the token is an impersonation token.
+type logUser struct {
+	userID  uuid.UUID
+	actorID *uuid.UUID
+}
+
+// RequestLogger logs one structured line per request. Auth runs deeper in the
+// chain with its own request context, so it reports the user back through a
+// slot placed in the context here.
+func RequestLogger(logger *slog.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			user := new(logUser)
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			next.ServeHTTP(ww, r.WithContext(context.WithValue(r.Context(), logUserContextKey, user)))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			attrs := []any{
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", status,
+				"bytes", ww.BytesWritten(),
+				"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+				"request_id", chimiddleware.GetReqID(r.Context()),
+				"remote_ip", r.RemoteAddr,
+			}
+			if user.userID != uuid.Nil {
+				attrs = append(attrs, "user_id", user.userID.String())
+			}
+			if user.actorID != nil {
+				attrs = append(attrs, "impersonated_by", user.actorID.String())
+			}
+
+			level := slog.LevelInfo
+			switch {
+			case status >= 500:
+				level = slog.LevelError
+			case status >= 400:
+				level = slog.LevelWarn
+			}
+			logger.Log(r.Context(), level, "HTTP request", attrs...)
+		})
+	}
+}
+
+// setLogUser records the authenticated user for RequestLogger.
+func setLogUser(ctx context.Context, claims *domain.TokenClaims) {
+	if slot, ok := ctx.Value(logUserContextKey).(*logUser); ok {
+		slot.userID = claims.UserID
+		slot.actorID = claims.ActorID
+	}
+}
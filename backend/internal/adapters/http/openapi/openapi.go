@@ -0,0 +1,286 @@
+// Package openapi builds the OpenAPI 3 document for the REST API from the
+// routes registered on the chi router and the domain types they exchange.
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/adapters/http/response"
+)
+
+// Access is the authentication a route requires.
+type Access int
+
+const (
+	Public Access = iota
+	Authenticated
+	RootOnly
+)
+
+// FormField is a multipart/form-data field; File marks the uploaded script.
+type FormField struct {
+	Name string
+	File bool
+}
+
+// Operation describes a route. Request and Response are sample values
+// (usually zero values of domain types) whose JSON shape becomes the schema.
+// A route may accept both a JSON Request and a multipart Form.
+type Operation struct {
+	Summary     string
+	Tag         string
+	Access      Access
+	Query       []string
+	Headers     []string // optional request headers, e.g. Idempotency-Key
+	Request     any
+	Form        []FormField
+	Response    any
+	Status      int    // success status, 200 when zero
+	Paginated   bool   // response carries the meta block
+	ContentType string // non-JSON success response (e.g. text/event-stream)
+	RateLimited bool   // may answer 429 with Retry-After (public routes always may)
+}
+
+// Key identifies an operation by method and route relative to the prefix,
+// e.g. Key("GET", "/tests/{id}").
+func Key(method, route string) string {
+	return method + " " + route
+}
+
+var pathParamRe = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+// Build walks the router and documents every route under prefix. Routes
+// without an entry in ops are still listed so the document never hides an
+// endpoint; their names are returned for logging.
+func Build(routes chi.Routes, prefix, title, version string, ops map[string]Operation) (map[string]any, []string, error) {
+	b := newSchemaBuilder()
+	b.schemas["Response"] = b.structSchema(typeOf(response.Response{}))
+
+	paths := map[string]map[string]any{}
+	var undocumented []string
+
+	err := chi.Walk(routes, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if !strings.HasPrefix(route, prefix) {
+			return nil
+		}
+		route = strings.TrimSuffix(strings.TrimPrefix(route, prefix), "/")
+		if route == "" {
+			route = "/"
+		}
+
+		op, ok := ops[Key(method, route)]
+		if !ok {
+			undocumented = append(undocumented, Key(method, route))
+			op = Operation{Access: Authenticated}
+		}
+
+		path := pathParamRe.ReplaceAllString(route, "{$1}")
+		if paths[path] == nil {
+			paths[path] = map[string]any{}
+		}
+		paths[path][strings.ToLower(method)] = b.operation(method, route, op)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Strings(undocumented)
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info":    map[string]any{"title": title, "version": version},
+		"servers": []any{map[string]any{"url": prefix}},
+		"paths":   paths,
+		"components": map[string]any{
+			"schemas": b.schemas,
+			"securitySchemes": map[string]any{
+				"bearerAuth": map[string]any{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+				"apiKeyAuth": map[string]any{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+			},
+			"responses": errorResponses(),
+		},
+	}, undocumented, nil
+}
+
+func (b *schemaBuilder) operation(method, route string, op Operation) map[string]any {
+	out := map[string]any{
+		"operationId": operationID(method, route),
+	}
+	if op.Summary != "" {
+		out["summary"] = op.Summary
+	}
+	if op.Tag != "" {
+		out["tags"] = []string{op.Tag}
+	}
+	if op.Access != Public {
+		out["security"] = []any{
+			map[string]any{"bearerAuth": []string{}},
+			map[string]any{"apiKeyAuth": []string{}},
+		}
+	}
+
+	var params []any
+	for _, m := range pathParamRe.FindAllStringSubmatch(route, -1) {
+		params = append(params, map[string]any{
+			"name": m[1], "in": "path", "required": true,
+			"schema": map[string]any{"type": "string"},
+		})
+	}
+	query := op.Query
+	if op.Paginated {
+		query = append([]string{"page", "page_size"}, query...)
+	}
+	for _, name := range query {
+		params = append(params, map[string]any{
+			"name": name, "in": "query",
+			"schema": map[string]any{"type": "string"},
+		})
+	}
+	for _, name := range op.Headers {
+		params = append(params, map[string]any{
+			"name": name, "in": "header",
+			"schema": map[string]any{"type": "string"},
+		})
+	}
+	if len(params) > 0 {
+		out["parameters"] = params
+	}
+
+	hasBody := op.Request != nil || len(op.Form) > 0
+	if hasBody {
+		content := map[string]any{}
+		if op.Request != nil {
+			content["application/json"] = map[string]any{"schema": b.schemaOf(typeOf(op.Request))}
+		}
+		if len(op.Form) > 0 {
+			props := map[string]any{}
+			for _, f := range op.Form {
+				if f.File {
+					props[f.Name] = map[string]any{"type": "string", "format": "binary"}
+				} else {
+					props[f.Name] = map[string]any{"type": "string"}
+				}
+			}
+			content["multipart/form-data"] = map[string]any{"schema": map[string]any{"type": "object", "properties": props}}
+		}
+		out["requestBody"] = map[string]any{"required": true, "content": content}
+	}
+
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	responses := map[string]any{}
+	switch {
+	case status == http.StatusNoContent:
+		responses["204"] = map[string]any{"description": "No Content"}
+	case op.ContentType != "":
+		schema := map[string]any{"type": "string"}
+		if op.ContentType == "application/json" {
+			schema = map[string]any{"type": "object"}
+		}
+		responses[fmt.Sprint(status)] = map[string]any{
+			"description": http.StatusText(status),
+			"content":     map[string]any{op.ContentType: map[string]any{"schema": schema}},
+		}
+	default:
+		responses[fmt.Sprint(status)] = map[string]any{
+			"description": http.StatusText(status),
+			"content": map[string]any{
+				"application/json": map[string]any{"schema": b.envelope(op)},
+			},
+		}
+	}
+
+	errorRef := func(code int, name string) {
+		responses[fmt.Sprint(code)] = map[string]any{"$ref": "#/components/responses/" + name}
+	}
+	if hasBody || strings.Contains(route, "{") {
+		errorRef(http.StatusBadRequest, "BadRequest")
+	}
+	if hasBody {
+		errorRef(http.StatusUnprocessableEntity, "ValidationError")
+	}
+	if op.Access != Public {
+		errorRef(http.StatusUnauthorized, "Unauthorized")
+		errorRef(http.StatusForbidden, "Forbidden")
+	}
+	if strings.Contains(route, "{") {
+		errorRef(http.StatusNotFound, "NotFound")
+	}
+	if op.Access == Public || op.RateLimited {
+		errorRef(http.StatusTooManyRequests, "TooManyRequests")
+	}
+	errorRef(http.StatusInternalServerError, "InternalError")
+	out["responses"] = responses
+
+	return out
+}
+
+// envelope wraps the response data schema in the Response envelope.
+func (b *schemaBuilder) envelope(op Operation) map[string]any {
+	data := map[string]any{}
+	if op.Response != nil {
+		data = b.schemaOf(typeOf(op.Response))
+	}
+	props := map[string]any{"data": data}
+	required := []string{"success", "data"}
+	if op.Paginated {
+		required = append(required, "meta")
+	}
+	return map[string]any{
+		"allOf": []any{
+			map[string]any{"$ref": "#/components/schemas/Response"},
+			map[string]any{"type": "object", "properties": props, "required": required},
+		},
+	}
+}
+
+func errorResponses() map[string]any {
+	errorBody := map[string]any{
+		"content": map[string]any{
+			"application/json": map[string]any{
+				"schema": map[string]any{
+					"allOf": []any{
+						map[string]any{"$ref": "#/components/schemas/Response"},
+						map[string]any{"type": "object", "required": []string{"success", "error"}},
+					},
+				},
+			},
+		},
+	}
+	out := map[string]any{}
+	for name, desc := range map[string]string{
+		"BadRequest":      "Malformed request body or parameter (BAD_REQUEST)",
+		"ValidationError": "Invalid input; error.details maps fields to messages (VALIDATION_ERROR)",
+		"Unauthorized":    "Missing or invalid credentials (UNAUTHORIZED)",
+		"Forbidden":       "Access denied or ROOT role required (FORBIDDEN)",
+		"NotFound":        "Resource not found (NOT_FOUND)",
+		"TooManyRequests": "Rate limit exceeded (TOO_MANY_REQUESTS)",
+		"InternalError":   "Unexpected error (INTERNAL_ERROR)",
+	} {
+		resp := map[string]any{"description": desc}
+		for k, v := range errorBody {
+			resp[k] = v
+		}
+		out[name] = resp
+	}
+	return out
+}
+
+// operationID turns "GET /tests/{id}/script" into "get_tests_id_script".
+func operationID(method, route string) string {
+	id := strings.ToLower(method)
+	for _, part := range strings.Split(pathParamRe.ReplaceAllString(route, "$1"), "/") {
+		if part != "" {
+			id += "_" + strings.ReplaceAll(part, "-", "_")
+		}
+	}
+	return id
+}
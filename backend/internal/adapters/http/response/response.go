@@ -3,7 +3,9 @@ package response
 import (
 	"encoding/json"
 	"log"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/willianpsouza/StressTestPlatform/internal/domain"
 )
@@ -59,6 +61,9 @@ func Error(w http.ResponseWriter, err error) {
 			Message: e.Message,
 			Details: e.Details,
 		}
+		if e.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(e.RetryAfter.Seconds()))))
+		}
 	default:
 		log.Printf("[ERROR] Unexpected error: %v", err)
 		status = http.StatusInternalServerError
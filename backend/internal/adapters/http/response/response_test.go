@@ -0,0 +1,41 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+func TestErrorRetryAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantHeader string
+	}{
+		{"rounded up to whole seconds", domain.NewTooManyRequestsError("slow down").WithRetryAfter(1500 * time.Millisecond), http.StatusTooManyRequests, "2"},
+		{"exact seconds", domain.NewTooManyRequestsError("slow down").WithRetryAfter(20 * time.Second), http.StatusTooManyRequests, "20"},
+		{"no hint", domain.NewTooManyRequestsError("slow down"), http.StatusTooManyRequests, ""},
+		{"other errors", domain.NewForbiddenError("no"), http.StatusForbidden, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			Error(rec, tt.err)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Retry-After"); got != tt.wantHeader {
+				t.Errorf("Retry-After = %q, want %q", got, tt.wantHeader)
+			}
+			var body Response
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil || body.Error == nil || body.Success {
+				t.Errorf("body = %+v, %v; want an error response", body, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+type APIKeyRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAPIKeyRepository(db *pgxpool.Pool) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+func (r *APIKeyRepository) Create(key *domain.APIKey) error {
+	key.ID = uuid.New()
+	key.CreatedAt = time.Now()
+
+	_, err := r.db.Exec(context.Background(),
+		`INSERT INTO api_keys (id, user_id, name, prefix, key_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		key.ID, key.UserID, key.Name, key.Prefix, key.KeyHash, key.ExpiresAt, key.CreatedAt,
+	)
+	return err
+}
+
+func (r *APIKeyRepository) GetByID(id uuid.UUID) (*domain.APIKey, error) {
+	return r.getOne(`WHERE id = $1`, id)
+}
+
+func (r *APIKeyRepository) GetByHash(hash string) (*domain.APIKey, error) {
+	return r.getOne(`WHERE key_hash = $1`, hash)
+}
+
+func (r *APIKeyRepository) getOne(where string, arg interface{}) (*domain.APIKey, error) {
+	k := &domain.APIKey{}
+	err := r.db.QueryRow(context.Background(),
+		`SELECT id, user_id, name, prefix, key_hash, expires_at, last_used_at, revoked_at, created_at
+		FROM api_keys `+where, arg,
+	).Scan(
+		&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.KeyHash,
+		&k.ExpiresAt, &k.LastUsedAt, &k.RevokedAt, &k.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return k, nil
+}
+
+func (r *APIKeyRepository) ListByUser(userID uuid.UUID) ([]domain.APIKey, error) {
+	rows, err := r.db.Query(context.Background(),
+		`SELECT id, user_id, name, prefix, key_hash, expires_at, last_used_at, revoked_at, created_at
+		FROM api_keys WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY created_at DESC`, userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []domain.APIKey
+	for rows.Next() {
+		var k domain.APIKey
+		if err := rows.Scan(
+			&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.KeyHash,
+			&k.ExpiresAt, &k.LastUsedAt, &k.RevokedAt, &k.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+
+	if keys == nil {
+		keys = []domain.APIKey{}
+	}
+	return keys, nil
+}
+
+func (r *APIKeyRepository) Revoke(id uuid.UUID) error {
+	_, err := r.db.Exec(context.Background(),
+		`UPDATE api_keys SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL`, time.Now(), id,
+	)
+	return err
+}
+
+func (r *APIKeyRepository) TouchLastUsed(id uuid.UUID) error {
+	_, err := r.db.Exec(context.Background(),
+		`UPDATE api_keys SET last_used_at = $1 WHERE id = $2`, time.Now(), id,
+	)
+	return err
+}
@@ -28,9 +28,9 @@ func (r *DomainRepository) Create(d *domain.Domain) error {
 	d.UpdatedAt = time.Now()
 
 	_, err := r.db.Exec(context.Background(),
-		`INSERT INTO domains (id, user_id, name, description, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)`,
-		d.ID, d.UserID, d.Name, d.Description, d.CreatedAt, d.UpdatedAt,
+		`INSERT INTO domains (id, user_id, name, description, tags, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		d.ID, d.UserID, d.Name, d.Description, tagsOrEmpty(d.Tags), d.CreatedAt, d.UpdatedAt,
 	)
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint") {
@@ -44,9 +44,9 @@ func (r *DomainRepository) Create(d *domain.Domain) error {
 func (r *DomainRepository) GetByID(id uuid.UUID) (*domain.Domain, error) {
 	d := &domain.Domain{}
 	err := r.db.QueryRow(context.Background(),
-		`SELECT id, user_id, name, description, created_at, updated_at, deleted_at
+		`SELECT id, user_id, name, description, tags, max_vus, max_duration, max_concurrent, created_at, updated_at, deleted_at
 		FROM domains WHERE id = $1 AND deleted_at IS NULL`, id,
-	).Scan(&d.ID, &d.UserID, &d.Name, &d.Description, &d.CreatedAt, &d.UpdatedAt, &d.DeletedAt)
+	).Scan(&d.ID, &d.UserID, &d.Name, &d.Description, &d.Tags, &d.MaxVUs, &d.MaxDuration, &d.MaxConcurrent, &d.CreatedAt, &d.UpdatedAt, &d.DeletedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrDomainNotFound
@@ -59,9 +59,9 @@ func (r *DomainRepository) GetByID(id uuid.UUID) (*domain.Domain, error) {
 func (r *DomainRepository) GetByUserAndName(userID uuid.UUID, name string) (*domain.Domain, error) {
 	d := &domain.Domain{}
 	err := r.db.QueryRow(context.Background(),
-		`SELECT id, user_id, name, description, created_at, updated_at, deleted_at
+		`SELECT id, user_id, name, description, tags, max_vus, max_duration, max_concurrent, created_at, updated_at, deleted_at
 		FROM domains WHERE user_id = $1 AND name = $2 AND deleted_at IS NULL`, userID, name,
-	).Scan(&d.ID, &d.UserID, &d.Name, &d.Description, &d.CreatedAt, &d.UpdatedAt, &d.DeletedAt)
+	).Scan(&d.ID, &d.UserID, &d.Name, &d.Description, &d.Tags, &d.MaxVUs, &d.MaxDuration, &d.MaxConcurrent, &d.CreatedAt, &d.UpdatedAt, &d.DeletedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrDomainNotFound
@@ -74,8 +74,9 @@ func (r *DomainRepository) GetByUserAndName(userID uuid.UUID, name string) (*dom
 func (r *DomainRepository) Update(d *domain.Domain) error {
 	d.UpdatedAt = time.Now()
 	_, err := r.db.Exec(context.Background(),
-		`UPDATE domains SET name=$1, description=$2, updated_at=$3 WHERE id=$4 AND deleted_at IS NULL`,
-		d.Name, d.Description, d.UpdatedAt, d.ID,
+		`UPDATE domains SET name=$1, description=$2, tags=$3, max_vus=$4, max_duration=$5, max_concurrent=$6, updated_at=$7
+		WHERE id=$8 AND deleted_at IS NULL`,
+		d.Name, d.Description, tagsOrEmpty(d.Tags), d.MaxVUs, d.MaxDuration, d.MaxConcurrent, d.UpdatedAt, d.ID,
 	)
 	return err
 }
@@ -89,6 +90,22 @@ func (r *DomainRepository) Delete(id uuid.UUID) error {
 	return err
 }
 
+// tagsOperator picks array containment (all tags) or overlap (any tag).
+func tagsOperator(matchAll bool) string {
+	if matchAll {
+		return "@>"
+	}
+	return "&&"
+}
+
+// tagsOrEmpty keeps the NOT NULL tags columns from receiving NULL.
+func tagsOrEmpty(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
 func (r *DomainRepository) List(filter domain.DomainFilter) ([]domain.Domain, int64, error) {
 	where := []string{"deleted_at IS NULL"}
 	args := []interface{}{}
@@ -104,6 +121,11 @@ func (r *DomainRepository) List(filter domain.DomainFilter) ([]domain.Domain, in
 		args = append(args, "%"+*filter.Search+"%")
 		argIdx++
 	}
+	if len(filter.Tags) > 0 {
+		where = append(where, fmt.Sprintf("tags %s $%d", tagsOperator(filter.MatchAllTags), argIdx))
+		args = append(args, filter.Tags)
+		argIdx++
+	}
 
 	whereClause := strings.Join(where, " AND ")
 
@@ -116,7 +138,7 @@ func (r *DomainRepository) List(filter domain.DomainFilter) ([]domain.Domain, in
 	}
 
 	query := fmt.Sprintf(
-		`SELECT id, user_id, name, description, created_at, updated_at, deleted_at
+		`SELECT id, user_id, name, description, tags, max_vus, max_duration, max_concurrent, created_at, updated_at, deleted_at
 		FROM domains WHERE %s ORDER BY created_at DESC LIMIT $%d OFFSET $%d`,
 		whereClause, argIdx, argIdx+1,
 	)
@@ -131,7 +153,7 @@ func (r *DomainRepository) List(filter domain.DomainFilter) ([]domain.Domain, in
 	var domains []domain.Domain
 	for rows.Next() {
 		var d domain.Domain
-		if err := rows.Scan(&d.ID, &d.UserID, &d.Name, &d.Description, &d.CreatedAt, &d.UpdatedAt, &d.DeletedAt); err != nil {
+		if err := rows.Scan(&d.ID, &d.UserID, &d.Name, &d.Description, &d.Tags, &d.MaxVUs, &d.MaxDuration, &d.MaxConcurrent, &d.CreatedAt, &d.UpdatedAt, &d.DeletedAt); err != nil {
 			return nil, 0, err
 		}
 		domains = append(domains, d)
@@ -28,11 +28,12 @@ func (r *ExecutionRepository) Create(exec *domain.TestExecution) error {
 	exec.UpdatedAt = time.Now()
 
 	_, err := r.db.Exec(context.Background(),
-		`INSERT INTO test_executions (id, test_id, user_id, schedule_id, vus, duration, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7::test_status, $8, $9)`,
+		`INSERT INTO test_executions (id, test_id, user_id, schedule_id, vus, duration, targets, env, stages, status,
+			max_retries, retry_backoff, capture_errors, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10::test_status, $11, $12, $13, $14, $15)`,
 		exec.ID, exec.TestID, exec.UserID, exec.ScheduleID,
-		exec.VUs, exec.Duration, string(exec.Status),
-		exec.CreatedAt, exec.UpdatedAt,
+		exec.VUs, exec.Duration, exec.Targets, exec.Env, exec.Stages, string(exec.Status),
+		exec.MaxRetries, exec.RetryBackoff, exec.CaptureErrors, exec.CreatedAt, exec.UpdatedAt,
 	)
 	return err
 }
@@ -40,21 +41,23 @@ func (r *ExecutionRepository) Create(exec *domain.TestExecution) error {
 func (r *ExecutionRepository) GetByID(id uuid.UUID) (*domain.TestExecution, error) {
 	exec := &domain.TestExecution{}
 	err := r.db.QueryRow(context.Background(),
-		`SELECT e.id, e.test_id, e.user_id, e.schedule_id, e.vus, e.duration,
-			e.status::text, e.started_at, e.completed_at, e.exit_code,
-			e.stdout, e.stderr, e.metrics_summary, e.error_message,
+		`SELECT e.id, e.test_id, e.user_id, e.schedule_id, e.vus, e.duration, e.effective_vus, e.effective_duration, e.capped,
+			e.status::text, e.started_at, e.completed_at, e.aggregated_at, e.exit_code, e.exit_reason::text,
+			e.stdout, e.stderr, e.metrics_summary, e.summary_export, e.targets, e.env, e.stages, e.error_message,
+			e.thresholds_passed, e.threshold_results, e.regressed, e.baseline_comparison, e.check_results, e.max_retries, e.retry_backoff, e.attempts, e.notes, e.capture_errors,
 			e.created_at, e.updated_at,
 			t.name, d.name, u.name, u.email
 		FROM test_executions e
 		JOIN tests t ON t.id = e.test_id
 		JOIN domains d ON d.id = t.domain_id
 		JOIN users u ON u.id = e.user_id
-		WHERE e.id = $1`, id,
+		WHERE e.id = $1 AND e.deleted_at IS NULL`, id,
 	).Scan(
 		&exec.ID, &exec.TestID, &exec.UserID, &exec.ScheduleID,
-		&exec.VUs, &exec.Duration,
-		&exec.Status, &exec.StartedAt, &exec.CompletedAt, &exec.ExitCode,
-		&exec.Stdout, &exec.Stderr, &exec.MetricsSummary, &exec.ErrorMessage,
+		&exec.VUs, &exec.Duration, &exec.EffectiveVUs, &exec.EffectiveDuration, &exec.Capped,
+		&exec.Status, &exec.StartedAt, &exec.CompletedAt, &exec.AggregatedAt, &exec.ExitCode, &exec.ExitReason,
+		&exec.Stdout, &exec.Stderr, &exec.MetricsSummary, &exec.SummaryExport, &exec.Targets, &exec.Env, &exec.Stages, &exec.ErrorMessage,
+		&exec.ThresholdsPassed, &exec.ThresholdResults, &exec.Regressed, &exec.BaselineComparison, &exec.CheckResults, &exec.MaxRetries, &exec.RetryBackoff, &exec.Attempts, &exec.Notes, &exec.CaptureErrors,
 		&exec.CreatedAt, &exec.UpdatedAt,
 		&exec.TestName, &exec.DomainName, &exec.UserName, &exec.UserEmail,
 	)
@@ -71,17 +74,83 @@ func (r *ExecutionRepository) Update(exec *domain.TestExecution) error {
 	exec.UpdatedAt = time.Now()
 	_, err := r.db.Exec(context.Background(),
 		`UPDATE test_executions SET status=$1::test_status, started_at=$2, completed_at=$3,
-			exit_code=$4, stdout=$5, stderr=$6, metrics_summary=$7, error_message=$8, updated_at=$9
-		WHERE id=$10`,
+			exit_code=$4, stdout=$5, stderr=$6, metrics_summary=$7, error_message=$8,
+			thresholds_passed=$9, threshold_results=$10, check_results=$11, attempts=$12, updated_at=$13,
+			exit_reason=$14::exit_reason, effective_vus=$15, effective_duration=$16, capped=$17, aggregated_at=$18,
+			regressed=$19, baseline_comparison=$20
+		WHERE id=$21`,
 		string(exec.Status), exec.StartedAt, exec.CompletedAt,
 		exec.ExitCode, exec.Stdout, exec.Stderr, exec.MetricsSummary, exec.ErrorMessage,
-		exec.UpdatedAt, exec.ID,
+		exec.ThresholdsPassed, exec.ThresholdResults, exec.CheckResults, exec.Attempts,
+		exec.UpdatedAt, exec.ExitReason, exec.EffectiveVUs, exec.EffectiveDuration, exec.Capped, exec.AggregatedAt,
+		exec.Regressed, exec.BaselineComparison, exec.ID,
 	)
 	return err
 }
 
+func (r *ExecutionRepository) UpdateSummaryExport(id uuid.UUID, summary domain.JSONMap) error {
+	_, err := r.db.Exec(context.Background(),
+		`UPDATE test_executions SET summary_export=$1, updated_at=$2 WHERE id=$3`,
+		summary, time.Now(), id,
+	)
+	return err
+}
+
+// UpdateNotes is kept apart from Update so a runner finishing the execution
+// never overwrites notes written in the meantime.
+func (r *ExecutionRepository) UpdateNotes(id uuid.UUID, notes *string) error {
+	_, err := r.db.Exec(context.Background(),
+		`UPDATE test_executions SET notes=$1, updated_at=$2 WHERE id=$3`,
+		notes, time.Now(), id,
+	)
+	return err
+}
+
+// AddErrorSamples stores the samples in capture order.
+func (r *ExecutionRepository) AddErrorSamples(executionID uuid.UUID, samples []domain.ErrorSample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(samples))
+	args := make([]interface{}, 0, len(samples)*7)
+	for i, s := range samples {
+		n := i * 7
+		values = append(values, fmt.Sprintf("($%d,$%d,$%d,$%d,$%d,$%d,$%d)", n+1, n+2, n+3, n+4, n+5, n+6, n+7))
+		args = append(args, executionID, i, s.Method, s.URL, s.Status, s.Body, s.Truncated)
+	}
+
+	_, err := r.db.Exec(context.Background(),
+		`INSERT INTO error_samples (execution_id, position, method, url, status, body, truncated)
+		VALUES `+strings.Join(values, ","), args...,
+	)
+	return err
+}
+
+// ListErrorSamples returns the samples in the order they were captured.
+func (r *ExecutionRepository) ListErrorSamples(executionID uuid.UUID) ([]domain.ErrorSample, error) {
+	rows, err := r.db.Query(context.Background(),
+		`SELECT id, execution_id, method, url, status, body, truncated, created_at
+		FROM error_samples WHERE execution_id = $1 ORDER BY position`, executionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	samples := []domain.ErrorSample{}
+	for rows.Next() {
+		var s domain.ErrorSample
+		if err := rows.Scan(&s.ID, &s.ExecutionID, &s.Method, &s.URL, &s.Status, &s.Body, &s.Truncated, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		samples = append(samples, s)
+	}
+	return samples, rows.Err()
+}
+
 func (r *ExecutionRepository) List(filter domain.ExecutionFilter) ([]domain.TestExecution, int64, error) {
-	where := []string{"1=1"}
+	where := []string{"e.deleted_at IS NULL"}
 	args := []interface{}{}
 	argIdx := 1
 
@@ -100,29 +169,48 @@ func (r *ExecutionRepository) List(filter domain.ExecutionFilter) ([]domain.Test
 		args = append(args, string(*filter.Status))
 		argIdx++
 	}
+	if filter.Search != nil && *filter.Search != "" {
+		where = append(where, fmt.Sprintf("(t.name ILIKE $%d OR d.name ILIKE $%d)", argIdx, argIdx))
+		args = append(args, "%"+*filter.Search+"%")
+		argIdx++
+	}
+	if filter.From != nil {
+		where = append(where, fmt.Sprintf("e.created_at >= $%d", argIdx))
+		args = append(args, *filter.From)
+		argIdx++
+	}
+	if filter.To != nil {
+		where = append(where, fmt.Sprintf("e.created_at <= $%d", argIdx))
+		args = append(args, *filter.To)
+		argIdx++
+	}
 
 	whereClause := strings.Join(where, " AND ")
 
 	var total int64
 	err := r.db.QueryRow(context.Background(),
-		fmt.Sprintf("SELECT COUNT(*) FROM test_executions e WHERE %s", whereClause), args...,
+		fmt.Sprintf(`SELECT COUNT(*) FROM test_executions e
+		JOIN tests t ON t.id = e.test_id
+		JOIN domains d ON d.id = t.domain_id
+		WHERE %s`, whereClause), args...,
 	).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	query := fmt.Sprintf(
-		`SELECT e.id, e.test_id, e.user_id, e.schedule_id, e.vus, e.duration,
-			e.status::text, e.started_at, e.completed_at, e.exit_code,
-			e.stdout, e.stderr, e.metrics_summary, e.error_message,
+		`SELECT e.id, e.test_id, e.user_id, e.schedule_id, e.vus, e.duration, e.effective_vus, e.effective_duration, e.capped,
+			e.status::text, e.started_at, e.completed_at, e.aggregated_at, e.exit_code, e.exit_reason::text,
+			e.stdout, e.stderr, e.metrics_summary, e.targets, e.env, e.stages, e.error_message,
+			e.thresholds_passed, e.threshold_results, e.regressed, e.baseline_comparison, e.check_results, e.max_retries, e.retry_backoff, e.attempts, e.notes, e.capture_errors,
 			e.created_at, e.updated_at,
 			t.name, d.name, u.name, u.email
 		FROM test_executions e
 		JOIN tests t ON t.id = e.test_id
 		JOIN domains d ON d.id = t.domain_id
 		JOIN users u ON u.id = e.user_id
-		WHERE %s ORDER BY e.created_at DESC LIMIT $%d OFFSET $%d`,
-		whereClause, argIdx, argIdx+1,
+		WHERE %s ORDER BY %s LIMIT $%d OFFSET $%d`,
+		whereClause, executionOrderBy(filter), argIdx, argIdx+1,
 	)
 	args = append(args, filter.Limit(), filter.Offset())
 
@@ -137,9 +225,10 @@ func (r *ExecutionRepository) List(filter domain.ExecutionFilter) ([]domain.Test
 		var e domain.TestExecution
 		if err := rows.Scan(
 			&e.ID, &e.TestID, &e.UserID, &e.ScheduleID,
-			&e.VUs, &e.Duration,
-			&e.Status, &e.StartedAt, &e.CompletedAt, &e.ExitCode,
-			&e.Stdout, &e.Stderr, &e.MetricsSummary, &e.ErrorMessage,
+			&e.VUs, &e.Duration, &e.EffectiveVUs, &e.EffectiveDuration, &e.Capped,
+			&e.Status, &e.StartedAt, &e.CompletedAt, &e.AggregatedAt, &e.ExitCode, &e.ExitReason,
+			&e.Stdout, &e.Stderr, &e.MetricsSummary, &e.Targets, &e.Env, &e.Stages, &e.ErrorMessage,
+			&e.ThresholdsPassed, &e.ThresholdResults, &e.Regressed, &e.BaselineComparison, &e.CheckResults, &e.MaxRetries, &e.RetryBackoff, &e.Attempts, &e.Notes, &e.CaptureErrors,
 			&e.CreatedAt, &e.UpdatedAt,
 			&e.TestName, &e.DomainName, &e.UserName, &e.UserEmail,
 		); err != nil {
@@ -154,14 +243,124 @@ func (r *ExecutionRepository) List(filter domain.ExecutionFilter) ([]domain.Test
 	return execs, total, nil
 }
 
+// executionSortColumns is the allow-list of ORDER BY expressions; only these
+// strings ever reach the query.
+var executionSortColumns = map[string]string{
+	domain.ExecutionSortCreatedAt: "e.created_at",
+	domain.ExecutionSortDuration:  "(e.completed_at - e.started_at)",
+	domain.ExecutionSortStatus:    "e.status::text",
+}
+
+// executionOrderBy renders the filter's sort, breaking ties newest first.
+func executionOrderBy(filter domain.ExecutionFilter) string {
+	column, ok := executionSortColumns[filter.Sort]
+	if !ok {
+		column = executionSortColumns[domain.ExecutionSortCreatedAt]
+	}
+	direction := "DESC"
+	if filter.Asc {
+		direction = "ASC"
+	}
+	return fmt.Sprintf("%s %s NULLS LAST, e.created_at DESC", column, direction)
+}
+
+// Delete soft-deletes the execution. Its metrics are kept until PurgeDeleted
+// removes the row for good.
 func (r *ExecutionRepository) Delete(id uuid.UUID) error {
-	_, err := r.db.Exec(context.Background(), `DELETE FROM test_executions WHERE id = $1`, id)
+	_, err := r.db.Exec(context.Background(),
+		`UPDATE test_executions SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, id)
+	return err
+}
+
+// Discard removes a PENDING execution that never started for good, rather
+// than soft-deleting it.
+func (r *ExecutionRepository) Discard(id uuid.UUID) error {
+	_, err := r.db.Exec(context.Background(),
+		`DELETE FROM test_executions WHERE id = $1 AND status::text = 'PENDING'`, id)
 	return err
 }
 
 func (r *ExecutionRepository) DeleteByTestID(testID uuid.UUID) (int64, error) {
 	tag, err := r.db.Exec(context.Background(),
-		`DELETE FROM test_executions WHERE test_id = $1 AND status::text NOT IN ('PENDING', 'RUNNING')`, testID)
+		`UPDATE test_executions SET deleted_at = NOW()
+		WHERE test_id = $1 AND deleted_at IS NULL AND status::text NOT IN ('PENDING', 'RUNNING')`, testID)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// DeleteByFilter soft-deletes the matching finished executions. With dryRun
+// nothing is deleted and the number of executions that would be is returned.
+func (r *ExecutionRepository) DeleteByFilter(filter domain.ExecutionDeleteFilter, dryRun bool) (int64, error) {
+	query, args := deleteByFilterQuery(filter, dryRun)
+	if dryRun {
+		var count int64
+		err := r.db.QueryRow(context.Background(), query, args...).Scan(&count)
+		return count, err
+	}
+
+	tag, err := r.db.Exec(context.Background(), query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// deleteByFilterQuery builds the statement for DeleteByFilter. The dry-run
+// count and the delete share the same WHERE clause and arguments, so a dry
+// run reports exactly what the delete would remove.
+func deleteByFilterQuery(filter domain.ExecutionDeleteFilter, dryRun bool) (string, []interface{}) {
+	where := []string{"deleted_at IS NULL", "status::text NOT IN ('PENDING', 'RUNNING')"}
+	args := []interface{}{}
+	argIdx := 1
+
+	if filter.UserID != nil {
+		where = append(where, fmt.Sprintf("user_id = $%d", argIdx))
+		args = append(args, *filter.UserID)
+		argIdx++
+	}
+	if filter.TestID != nil {
+		where = append(where, fmt.Sprintf("test_id = $%d", argIdx))
+		args = append(args, *filter.TestID)
+		argIdx++
+	}
+	if filter.Status != nil {
+		where = append(where, fmt.Sprintf("status::text = $%d", argIdx))
+		args = append(args, string(*filter.Status))
+		argIdx++
+	}
+	if filter.OlderThan != nil {
+		where = append(where, fmt.Sprintf("created_at < $%d", argIdx))
+		args = append(args, *filter.OlderThan)
+		argIdx++
+	}
+
+	whereClause := strings.Join(where, " AND ")
+	if dryRun {
+		return fmt.Sprintf("SELECT COUNT(*) FROM test_executions WHERE %s", whereClause), args
+	}
+	return fmt.Sprintf("UPDATE test_executions SET deleted_at = NOW() WHERE %s", whereClause), args
+}
+
+// Restore brings back a soft-deleted execution.
+func (r *ExecutionRepository) Restore(id uuid.UUID) error {
+	tag, err := r.db.Exec(context.Background(),
+		`UPDATE test_executions SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrExecutionNotFound
+	}
+	return nil
+}
+
+// PurgeDeleted permanently removes executions soft-deleted before the given
+// time. Their metrics go with them through the ON DELETE CASCADE foreign keys.
+func (r *ExecutionRepository) PurgeDeleted(before time.Time) (int64, error) {
+	tag, err := r.db.Exec(context.Background(),
+		`DELETE FROM test_executions WHERE deleted_at IS NOT NULL AND deleted_at < $1`, before)
 	if err != nil {
 		return 0, err
 	}
@@ -177,6 +376,32 @@ func (r *ExecutionRepository) CountRunningByUser(userID uuid.UUID) (int, error)
 	return count, err
 }
 
+func (r *ExecutionRepository) GetLastCompletedAt(testID uuid.UUID) (*time.Time, error) {
+	var completedAt *time.Time
+	err := r.db.QueryRow(context.Background(),
+		`SELECT MAX(completed_at) FROM test_executions WHERE test_id = $1`,
+		testID,
+	).Scan(&completedAt)
+	return completedAt, err
+}
+
+// GetLatestBySchedule returns the most recent execution fired by the schedule,
+// or nil if it has never run.
+func (r *ExecutionRepository) GetLatestBySchedule(scheduleID uuid.UUID) (*domain.TestExecution, error) {
+	var id uuid.UUID
+	err := r.db.QueryRow(context.Background(),
+		`SELECT id FROM test_executions WHERE schedule_id = $1 AND deleted_at IS NULL ORDER BY created_at DESC LIMIT 1`,
+		scheduleID,
+	).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
 func (r *ExecutionRepository) MarkOrphansAsFailed() (int, error) {
 	now := time.Now()
 	tag, err := r.db.Exec(context.Background(),
@@ -203,19 +428,130 @@ func (r *ExecutionRepository) GetStats() (map[string]interface{}, error) {
 
 	var completedToday int64
 	r.db.QueryRow(context.Background(),
-		"SELECT COUNT(*) FROM test_executions WHERE status::text = 'COMPLETED' AND created_at >= CURRENT_DATE",
+		"SELECT COUNT(*) FROM test_executions WHERE status::text = 'COMPLETED' AND created_at >= CURRENT_DATE AND deleted_at IS NULL",
 	).Scan(&completedToday)
 	stats["completed_today"] = completedToday
 
 	var failedToday int64
 	r.db.QueryRow(context.Background(),
-		"SELECT COUNT(*) FROM test_executions WHERE status::text IN ('FAILED', 'TIMEOUT') AND created_at >= CURRENT_DATE",
+		"SELECT COUNT(*) FROM test_executions WHERE status::text IN ('FAILED', 'TIMEOUT') AND created_at >= CURRENT_DATE AND deleted_at IS NULL",
 	).Scan(&failedToday)
 	stats["failed_today"] = failedToday
 
 	var totalExecutions int64
-	r.db.QueryRow(context.Background(), "SELECT COUNT(*) FROM test_executions").Scan(&totalExecutions)
+	r.db.QueryRow(context.Background(), "SELECT COUNT(*) FROM test_executions WHERE deleted_at IS NULL").Scan(&totalExecutions)
 	stats["total_executions"] = totalExecutions
 
 	return stats, nil
 }
+
+func (r *ExecutionRepository) LastAggregationLag() (time.Duration, bool, error) {
+	var seconds float64
+	err := r.db.QueryRow(context.Background(),
+		`SELECT EXTRACT(EPOCH FROM (aggregated_at - completed_at))::float8
+		FROM test_executions
+		WHERE aggregated_at IS NOT NULL AND completed_at IS NOT NULL
+		ORDER BY aggregated_at DESC LIMIT 1`,
+	).Scan(&seconds)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return time.Duration(seconds * float64(time.Second)), true, nil
+}
+
+// CountByStatus counts the executions that are not soft-deleted, per status.
+func (r *ExecutionRepository) CountByStatus() (map[domain.TestStatus]int64, error) {
+	rows, err := r.db.Query(context.Background(),
+		"SELECT status::text, COUNT(*) FROM test_executions WHERE deleted_at IS NULL GROUP BY status")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[domain.TestStatus]int64{}
+	for rows.Next() {
+		var status string
+		var count int64
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[domain.TestStatus(status)] = count
+	}
+	return counts, rows.Err()
+}
+
+// usageColumns aggregates the usage of the executions matched by usageWhere.
+// Soft-deleted runs are counted too: deleting a run does not give back the
+// capacity it used. Requests come from the global http_reqs summary row.
+const usageColumns = `COUNT(*),
+	COALESCE(SUM(r.requests), 0)::bigint,
+	COALESCE(SUM(COALESCE(e.effective_vus, e.vus)
+		* EXTRACT(EPOCH FROM (e.completed_at - e.started_at)) / 60), 0)::float8,
+	COALESCE(AVG((e.metrics_summary->>'error_rate')::float8), 0)`
+
+const usageFrom = `FROM test_executions e
+	LEFT JOIN LATERAL (
+		SELECT SUM(m.sum_value) AS requests FROM k6_metrics_aggregated m
+		WHERE m.execution_id = e.id AND m.is_summary = TRUE AND m.url IS NULL AND m.metric_name = 'http_reqs'
+	) r ON TRUE`
+
+func usageWhere(filter domain.UsageFilter) (string, []interface{}) {
+	where := []string{"TRUE"}
+	args := []interface{}{}
+	if filter.UserID != nil {
+		args = append(args, *filter.UserID)
+		where = append(where, fmt.Sprintf("e.user_id = $%d", len(args)))
+	}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		where = append(where, fmt.Sprintf("e.created_at >= $%d", len(args)))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		where = append(where, fmt.Sprintf("e.created_at <= $%d", len(args)))
+	}
+	return strings.Join(where, " AND "), args
+}
+
+func (r *ExecutionRepository) Usage(filter domain.UsageFilter) (*domain.UsageStats, error) {
+	where, args := usageWhere(filter)
+	stats := &domain.UsageStats{UserID: filter.UserID}
+	err := r.db.QueryRow(context.Background(),
+		fmt.Sprintf("SELECT %s %s WHERE %s", usageColumns, usageFrom, where), args...,
+	).Scan(&stats.Executions, &stats.TotalRequests, &stats.VUMinutes, &stats.AvgErrorRate)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (r *ExecutionRepository) UsageByUser(filter domain.UsageFilter) ([]domain.UsageStats, error) {
+	where, args := usageWhere(filter)
+	rows, err := r.db.Query(context.Background(),
+		fmt.Sprintf(`SELECT e.user_id, u.name, u.email, %s %s
+		JOIN users u ON u.id = e.user_id
+		WHERE %s
+		GROUP BY e.user_id, u.name, u.email
+		ORDER BY 6 DESC, 3`, usageColumns, usageFrom, where), args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []domain.UsageStats{}
+	for rows.Next() {
+		var s domain.UsageStats
+		var userID uuid.UUID
+		if err := rows.Scan(&userID, &s.UserName, &s.UserEmail,
+			&s.Executions, &s.TotalRequests, &s.VUMinutes, &s.AvgErrorRate); err != nil {
+			return nil, err
+		}
+		s.UserID = &userID
+		users = append(users, s)
+	}
+	return users, rows.Err()
+}
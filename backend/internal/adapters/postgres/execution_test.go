@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+func TestDeleteByFilterQueryParity(t *testing.T) {
+	userID := uuid.New()
+	testID := uuid.New()
+	status := domain.TestStatusFailed
+	olderThan := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		filter   domain.ExecutionDeleteFilter
+		wantArgs []interface{}
+		wantSQL  []string
+	}{
+		{
+			name:     "test only",
+			filter:   domain.ExecutionDeleteFilter{TestID: &testID},
+			wantArgs: []interface{}{testID},
+			wantSQL:  []string{"test_id = $1"},
+		},
+		{
+			name:     "status scoped to user",
+			filter:   domain.ExecutionDeleteFilter{UserID: &userID, Status: &status},
+			wantArgs: []interface{}{userID, "FAILED"},
+			wantSQL:  []string{"user_id = $1", "status::text = $2"},
+		},
+		{
+			name:     "all filters",
+			filter:   domain.ExecutionDeleteFilter{UserID: &userID, TestID: &testID, Status: &status, OlderThan: &olderThan},
+			wantArgs: []interface{}{userID, testID, "FAILED", olderThan},
+			wantSQL:  []string{"user_id = $1", "test_id = $2", "status::text = $3", "created_at < $4"},
+		},
+		{
+			name:     "older than only",
+			filter:   domain.ExecutionDeleteFilter{OlderThan: &olderThan},
+			wantArgs: []interface{}{olderThan},
+			wantSQL:  []string{"created_at < $1"},
+		},
+	}
+
+	const (
+		countPrefix  = "SELECT COUNT(*) FROM test_executions WHERE "
+		deletePrefix = "UPDATE test_executions SET deleted_at = NOW() WHERE "
+	)
+	placeholderRe := regexp.MustCompile(`\$\d+`)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			countSQL, countArgs := deleteByFilterQuery(tt.filter, true)
+			deleteSQL, deleteArgs := deleteByFilterQuery(tt.filter, false)
+
+			countWhere, ok := strings.CutPrefix(countSQL, countPrefix)
+			if !ok {
+				t.Fatalf("dry-run query = %q, want a COUNT", countSQL)
+			}
+			deleteWhere, ok := strings.CutPrefix(deleteSQL, deletePrefix)
+			if !ok {
+				t.Fatalf("delete query = %q, want a soft delete", deleteSQL)
+			}
+			if countWhere != deleteWhere {
+				t.Errorf("WHERE clauses differ:\n dry run: %s\n delete:  %s", countWhere, deleteWhere)
+			}
+			if !reflect.DeepEqual(countArgs, deleteArgs) {
+				t.Errorf("args differ: dry run %v, delete %v", countArgs, deleteArgs)
+			}
+			if !reflect.DeepEqual(deleteArgs, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", deleteArgs, tt.wantArgs)
+			}
+			if n := len(placeholderRe.FindAllString(deleteWhere, -1)); n != len(deleteArgs) {
+				t.Errorf("%d placeholders for %d args", n, len(deleteArgs))
+			}
+
+			for _, cond := range append([]string{"deleted_at IS NULL", "status::text NOT IN ('PENDING', 'RUNNING')"}, tt.wantSQL...) {
+				if !strings.Contains(deleteWhere, cond) {
+					t.Errorf("WHERE %q is missing %q", deleteWhere, cond)
+				}
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -35,22 +36,22 @@ func (r *MetricRepository) BulkInsert(metrics []domain.K6Metric) error {
 		batch := metrics[i:end]
 
 		values := make([]string, 0, len(batch))
-		args := make([]interface{}, 0, len(batch)*8)
+		args := make([]interface{}, 0, len(batch)*10)
 		argIdx := 1
 
 		for _, m := range batch {
 			values = append(values, fmt.Sprintf(
-				"($%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d)",
+				"($%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d)",
 				argIdx, argIdx+1, argIdx+2, argIdx+3, argIdx+4,
-				argIdx+5, argIdx+6, argIdx+7, argIdx+8,
+				argIdx+5, argIdx+6, argIdx+7, argIdx+8, argIdx+9,
 			))
-			args = append(args, m.ExecutionID, m.TestID, m.MetricName,
+			args = append(args, m.ExecutionID, m.TestID, m.MetricName, string(m.MetricType),
 				m.Timestamp, m.MetricValue, m.Method, m.Status, m.URL, m.Scenario)
-			argIdx += 9
+			argIdx += 10
 		}
 
 		query := fmt.Sprintf(
-			`INSERT INTO k6_metrics (execution_id, test_id, metric_name, timestamp, metric_value, method, status, url, scenario)
+			`INSERT INTO k6_metrics (execution_id, test_id, metric_name, metric_type, timestamp, metric_value, method, status, url, scenario)
 			VALUES %s`, strings.Join(values, ","),
 		)
 
@@ -85,9 +86,14 @@ func (r *MetricRepository) GetTimeseries(executionID uuid.UUID, metricName strin
 	return points, nil
 }
 
+// GetMetricNames lists built-in and custom metrics of the execution, from the
+// raw rows while it runs and from the aggregated summaries afterwards.
 func (r *MetricRepository) GetMetricNames(executionID uuid.UUID) ([]string, error) {
 	rows, err := r.pool.Query(context.Background(),
-		`SELECT DISTINCT metric_name FROM k6_metrics WHERE execution_id = $1 ORDER BY metric_name`,
+		`SELECT metric_name FROM k6_metrics WHERE execution_id = $1
+		UNION
+		SELECT metric_name FROM k6_metrics_aggregated WHERE execution_id = $1 AND is_summary = TRUE AND url IS NULL
+		ORDER BY metric_name`,
 		executionID)
 	if err != nil {
 		return nil, err
@@ -112,7 +118,9 @@ func (r *MetricRepository) GetSummary(executionID uuid.UUID) ([]domain.MetricSum
 	rows, err := r.pool.Query(context.Background(),
 		`SELECT metric_name, COUNT(*), AVG(metric_value), MIN(metric_value), MAX(metric_value),
 			PERCENTILE_CONT(0.90) WITHIN GROUP (ORDER BY metric_value),
-			PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY metric_value)
+			PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY metric_value),
+			PERCENTILE_CONT(0.99) WITHIN GROUP (ORDER BY metric_value),
+			COALESCE(STDDEV_SAMP(metric_value), 0)
 		FROM k6_metrics WHERE execution_id = $1
 		GROUP BY metric_name ORDER BY metric_name`, executionID)
 	if err != nil {
@@ -123,7 +131,7 @@ func (r *MetricRepository) GetSummary(executionID uuid.UUID) ([]domain.MetricSum
 	var summaries []domain.MetricSummary
 	for rows.Next() {
 		var s domain.MetricSummary
-		if err := rows.Scan(&s.MetricName, &s.Count, &s.Avg, &s.Min, &s.Max, &s.P90, &s.P95); err != nil {
+		if err := rows.Scan(&s.MetricName, &s.Count, &s.Avg, &s.Min, &s.Max, &s.P90, &s.P95, &s.P99, &s.StdDev); err != nil {
 			return nil, err
 		}
 		summaries = append(summaries, s)
@@ -138,10 +146,13 @@ func (r *MetricRepository) ComputeExecutionSummary(executionID uuid.UUID) (domai
 	var totalRequests, totalFailures, avgResponse, errorRate float64
 	err := r.pool.QueryRow(context.Background(), `
 		SELECT
-			COALESCE(SUM(CASE WHEN metric_name = 'http_reqs' THEN metric_value END), 0),
-			COALESCE(SUM(CASE WHEN metric_name = 'http_reqs' AND status NOT IN ('200','201') THEN metric_value ELSE 0 END), 0),
-			COALESCE(AVG(CASE WHEN metric_name = 'http_req_duration' THEN metric_value END), 0)
-		FROM k6_metrics WHERE execution_id = $1`, executionID,
+			COALESCE(SUM(CASE WHEN m.metric_name = 'http_reqs' THEN m.metric_value END), 0),
+			COALESCE(SUM(CASE WHEN m.metric_name = 'http_reqs'
+				AND NOT (m.status = ANY(t.success_status_codes::TEXT[])) THEN m.metric_value ELSE 0 END), 0),
+			COALESCE(AVG(CASE WHEN m.metric_name = 'http_req_duration' THEN m.metric_value END), 0)
+		FROM k6_metrics m
+		JOIN tests t ON t.id = m.test_id
+		WHERE m.execution_id = $1`, executionID,
 	).Scan(&totalRequests, &totalFailures, &avgResponse)
 	if err != nil {
 		return nil, err
@@ -152,11 +163,76 @@ func (r *MetricRepository) ComputeExecutionSummary(executionID uuid.UUID) (domai
 	}
 	avgResponse = math.Round(avgResponse*100) / 100
 
-	return domain.JSONMap{
+	summary := domain.JSONMap{
 		"total_requests":  totalRequests,
 		"avg_response_ms": avgResponse,
 		"error_rate":      errorRate,
-	}, nil
+	}
+
+	custom, err := r.computeCustomMetrics(executionID)
+	if err != nil {
+		return nil, err
+	}
+	if len(custom) > 0 {
+		summary["custom_metrics"] = custom
+	}
+	return summary, nil
+}
+
+// computeCustomMetrics summarizes the script-defined metrics of an execution,
+// keyed by metric name, with the statistics that make sense for each type.
+func (r *MetricRepository) computeCustomMetrics(executionID uuid.UUID) (map[string]interface{}, error) {
+	builtin := make([]string, 0, len(domain.BuiltinMetrics))
+	for name := range domain.BuiltinMetrics {
+		builtin = append(builtin, name)
+	}
+
+	rows, err := r.pool.Query(context.Background(), `
+		SELECT metric_name, COALESCE(MAX(metric_type), 'trend'),
+			COUNT(*), SUM(metric_value), AVG(metric_value), MIN(metric_value), MAX(metric_value),
+			PERCENTILE_CONT(0.90) WITHIN GROUP (ORDER BY metric_value),
+			PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY metric_value),
+			PERCENTILE_CONT(0.99) WITHIN GROUP (ORDER BY metric_value),
+			(ARRAY_AGG(metric_value ORDER BY timestamp DESC))[1]
+		FROM k6_metrics
+		WHERE execution_id = $1 AND NOT (metric_name = ANY($2))
+		GROUP BY metric_name`, executionID, builtin)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	custom := map[string]interface{}{}
+	for rows.Next() {
+		var name, metricType string
+		var count int64
+		var sum, avg, minV, maxV, p90, p95, p99, last float64
+		if err := rows.Scan(&name, &metricType, &count, &sum, &avg, &minV, &maxV, &p90, &p95, &p99, &last); err != nil {
+			return nil, err
+		}
+
+		var stats domain.JSONMap
+		switch domain.MetricType(metricType) {
+		case domain.MetricTypeCounter:
+			stats = domain.JSONMap{"count": count, "total": round2(sum)}
+		case domain.MetricTypeGauge:
+			stats = domain.JSONMap{"value": round2(last), "min": round2(minV), "max": round2(maxV)}
+		case domain.MetricTypeRate:
+			stats = domain.JSONMap{"rate": math.Round(avg*10000) / 10000, "passes": int64(sum), "fails": count - int64(sum)}
+		default:
+			stats = domain.JSONMap{
+				"count": count, "avg": round2(avg), "min": round2(minV), "max": round2(maxV),
+				"p90": round2(p90), "p95": round2(p95), "p99": round2(p99),
+			}
+		}
+		stats["type"] = metricType
+		custom[name] = stats
+	}
+	return custom, rows.Err()
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
 }
 
 func (r *MetricRepository) AggregateAndCleanup(executionID uuid.UUID) error {
@@ -171,11 +247,93 @@ func (r *MetricRepository) DeleteByExecution(executionID uuid.UUID) error {
 	if err != nil {
 		return err
 	}
+	_, err = r.pool.Exec(context.Background(),
+		`DELETE FROM k6_metrics_scenarios WHERE execution_id = $1`, executionID)
+	if err != nil {
+		return err
+	}
 	_, err = r.pool.Exec(context.Background(),
 		`DELETE FROM k6_metrics WHERE execution_id = $1`, executionID)
 	return err
 }
 
+// PurgeAggregated removes the aggregated and per-scenario rows of executions
+// started before the cutoff and returns the number of aggregated rows removed.
+func (r *MetricRepository) PurgeAggregated(before time.Time) (int64, error) {
+	const oldExecutions = `SELECT id FROM test_executions WHERE COALESCE(started_at, created_at) < $1`
+
+	tag, err := r.pool.Exec(context.Background(),
+		`DELETE FROM k6_metrics_aggregated WHERE execution_id IN (`+oldExecutions+`)`, before)
+	if err != nil {
+		return 0, err
+	}
+	_, err = r.pool.Exec(context.Background(),
+		`DELETE FROM k6_metrics_scenarios WHERE execution_id IN (`+oldExecutions+`)`, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// GetExecutionTimeseries rolls the execution's per-second buckets up into
+// intervals. Failures follow the test's success_status_codes.
+func (r *MetricRepository) GetExecutionTimeseries(executionID uuid.UUID, intervalSeconds int) ([]domain.ExecutionTimeseriesPoint, error) {
+	rows, err := r.pool.Query(context.Background(),
+		`SELECT to_timestamp(floor(extract(epoch FROM bucket_time) / $2) * $2) AS time,
+			COALESCE(SUM(CASE WHEN metric_name = 'http_reqs' THEN sum_value END), 0) AS requests,
+			COALESCE(SUM(CASE WHEN metric_name = 'http_reqs' THEN sum_value END) / $2, 0) AS rps,
+			COALESCE(SUM(CASE WHEN metric_name = 'http_req_duration' THEN avg_value * count END)
+				/ NULLIF(SUM(CASE WHEN metric_name = 'http_req_duration' THEN count END), 0), 0) AS response_time,
+			COALESCE(SUM(CASE WHEN metric_name = 'http_reqs' AND fn_is_failure_status(test_id, status) THEN sum_value END), 0) AS failures
+		FROM k6_metrics_aggregated
+		WHERE execution_id = $1 AND is_summary = FALSE
+		GROUP BY 1 ORDER BY 1`,
+		executionID, float64(intervalSeconds),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := []domain.ExecutionTimeseriesPoint{}
+	for rows.Next() {
+		var p domain.ExecutionTimeseriesPoint
+		if err := rows.Scan(&p.Time, &p.Requests, &p.RPS, &p.ResponseTime, &p.Failures); err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, rows.Err()
+}
+
+// StorageStats counts a test's metric rows. Sizes come from pg_column_size,
+// so they cover row data but not indexes or page overhead.
+func (r *MetricRepository) StorageStats(testID uuid.UUID) (*domain.MetricStorageStats, error) {
+	stats := &domain.MetricStorageStats{TestID: testID}
+	var rawBytes, aggBytes, scenarioBytes int64
+	err := r.pool.QueryRow(context.Background(),
+		`SELECT
+			(SELECT COUNT(*) FROM test_executions WHERE test_id = $1),
+			raw.rows, raw.bytes, agg.rows, agg.bytes, sc.rows, sc.bytes,
+			LEAST(raw.oldest, agg.oldest), GREATEST(raw.newest, agg.newest)
+		FROM
+			(SELECT COUNT(*) AS rows, COALESCE(SUM(pg_column_size(m.*)), 0)::bigint AS bytes,
+				MIN(timestamp) AS oldest, MAX(timestamp) AS newest
+			FROM k6_metrics m WHERE test_id = $1) raw,
+			(SELECT COUNT(*) AS rows, COALESCE(SUM(pg_column_size(a.*)), 0)::bigint AS bytes,
+				MIN(bucket_time) AS oldest, MAX(bucket_time) AS newest
+			FROM k6_metrics_aggregated a WHERE test_id = $1) agg,
+			(SELECT COUNT(*) AS rows, COALESCE(SUM(pg_column_size(s.*)), 0)::bigint AS bytes
+			FROM k6_metrics_scenarios s WHERE test_id = $1) sc`, testID,
+	).Scan(&stats.Executions, &stats.RawRows, &rawBytes, &stats.AggregatedRows, &aggBytes,
+		&stats.ScenarioRows, &scenarioBytes, &stats.Oldest, &stats.Newest)
+	if err != nil {
+		return nil, err
+	}
+	stats.ApproxBytes = rawBytes + aggBytes + scenarioBytes
+	return stats, nil
+}
+
 // Grafana queries — join with tests and domains to filter by name
 
 func (r *MetricRepository) GetTimeseriesByFilter(filter domain.MetricFilter) ([]domain.MetricDatapoint, error) {
@@ -237,7 +395,9 @@ func (r *MetricRepository) GetSummaryByFilter(domainName, testName string) ([]do
 	rows, err := r.pool.Query(context.Background(),
 		`SELECT m.metric_name, COUNT(*), AVG(m.metric_value), MIN(m.metric_value), MAX(m.metric_value),
 			PERCENTILE_CONT(0.90) WITHIN GROUP (ORDER BY m.metric_value),
-			PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY m.metric_value)
+			PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY m.metric_value),
+			PERCENTILE_CONT(0.99) WITHIN GROUP (ORDER BY m.metric_value),
+			COALESCE(STDDEV_SAMP(m.metric_value), 0)
 		FROM k6_metrics m
 		JOIN tests t ON t.id = m.test_id
 		JOIN domains d ON d.id = t.domain_id
@@ -251,7 +411,7 @@ func (r *MetricRepository) GetSummaryByFilter(domainName, testName string) ([]do
 	var summaries []domain.MetricSummary
 	for rows.Next() {
 		var s domain.MetricSummary
-		if err := rows.Scan(&s.MetricName, &s.Count, &s.Avg, &s.Min, &s.Max, &s.P90, &s.P95); err != nil {
+		if err := rows.Scan(&s.MetricName, &s.Count, &s.Avg, &s.Min, &s.Max, &s.P90, &s.P95, &s.P99, &s.StdDev); err != nil {
 			return nil, err
 		}
 		summaries = append(summaries, s)
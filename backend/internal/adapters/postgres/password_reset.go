@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+type PasswordResetRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPasswordResetRepository(db *pgxpool.Pool) *PasswordResetRepository {
+	return &PasswordResetRepository{db: db}
+}
+
+func (r *PasswordResetRepository) Create(reset *domain.PasswordReset) error {
+	reset.ID = uuid.New()
+	reset.CreatedAt = time.Now()
+
+	_, err := r.db.Exec(context.Background(),
+		`INSERT INTO password_resets (id, user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)`,
+		reset.ID, reset.UserID, reset.TokenHash, reset.ExpiresAt, reset.CreatedAt,
+	)
+	return err
+}
+
+func (r *PasswordResetRepository) GetByTokenHash(hash string) (*domain.PasswordReset, error) {
+	reset := &domain.PasswordReset{}
+	err := r.db.QueryRow(context.Background(),
+		`SELECT id, user_id, token_hash, expires_at, used_at, created_at
+		FROM password_resets WHERE token_hash = $1`, hash,
+	).Scan(
+		&reset.ID, &reset.UserID, &reset.TokenHash,
+		&reset.ExpiresAt, &reset.UsedAt, &reset.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return reset, nil
+}
+
+func (r *PasswordResetRepository) MarkUsed(id uuid.UUID) (bool, error) {
+	tag, err := r.db.Exec(context.Background(),
+		`UPDATE password_resets SET used_at = $1 WHERE id = $2 AND used_at IS NULL`,
+		time.Now(), id,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *PasswordResetRepository) InvalidateAllForUser(userID uuid.UUID) error {
+	_, err := r.db.Exec(context.Background(),
+		`UPDATE password_resets SET used_at = $1 WHERE user_id = $2 AND used_at IS NULL`,
+		time.Now(), userID,
+	)
+	return err
+}
@@ -28,31 +28,72 @@ func (r *ScheduleRepository) Create(s *domain.Schedule) error {
 	s.UpdatedAt = time.Now()
 
 	_, err := r.db.Exec(context.Background(),
-		`INSERT INTO schedules (id, test_id, user_id, schedule_type, cron_expression, next_run_at,
-			vus, duration, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4::schedule_type, $5, $6, $7, $8, $9::schedule_status, $10, $11)`,
-		s.ID, s.TestID, s.UserID, string(s.ScheduleType), s.CronExpression, s.NextRunAt,
-		s.VUs, s.Duration, string(s.Status), s.CreatedAt, s.UpdatedAt,
+		`INSERT INTO schedules (id, test_id, user_id, schedule_type, cron_expression, interval_seconds, timezone,
+			next_run_at, vus, duration, status, end_at, max_runs, created_at, updated_at)
+		VALUES ($1, $2, $3, $4::schedule_type, $5, $6, $7, $8, $9, $10, $11::schedule_status, $12, $13, $14, $15)`,
+		s.ID, s.TestID, s.UserID, string(s.ScheduleType), s.CronExpression, s.IntervalSeconds, s.Timezone,
+		s.NextRunAt, s.VUs, s.Duration, string(s.Status), s.EndAt, s.MaxRuns, s.CreatedAt, s.UpdatedAt,
 	)
 	return err
 }
 
+// scheduleLastRunJoin joins the most recent execution of each schedule, with
+// the error rate from its metrics summary and the p95 from its k6 summary.
+const scheduleLastRunJoin = `LEFT JOIN LATERAL (
+			SELECT e.id, e.status::text AS status, e.started_at, e.completed_at,
+				(e.metrics_summary->>'error_rate')::float8 AS error_rate,
+				(e.summary_export->'metrics'->'http_req_duration'->>'p(95)')::float8 AS p95
+			FROM test_executions e
+			WHERE e.schedule_id = s.id AND e.deleted_at IS NULL
+			ORDER BY e.created_at DESC LIMIT 1
+		) lr ON true`
+
+const scheduleLastRunColumns = "lr.id, lr.status, lr.started_at, lr.completed_at, lr.error_rate, lr.p95"
+
+// lastRunScan receives the scheduleLastRunColumns, which are all NULL when
+// the schedule has never fired.
+type lastRunScan struct {
+	id          *uuid.UUID
+	status      *string
+	startedAt   *time.Time
+	completedAt *time.Time
+	errorRate   *float64
+	p95         *float64
+}
+
+func (l *lastRunScan) lastRun() *domain.ScheduleLastRun {
+	if l.id == nil {
+		return nil
+	}
+	return &domain.ScheduleLastRun{
+		ExecutionID: *l.id,
+		Status:      domain.TestStatus(*l.status),
+		StartedAt:   l.startedAt,
+		CompletedAt: l.completedAt,
+		ErrorRate:   l.errorRate,
+		P95Ms:       l.p95,
+	}
+}
+
 func (r *ScheduleRepository) GetByID(id uuid.UUID) (*domain.Schedule, error) {
 	s := &domain.Schedule{}
+	var lr lastRunScan
 	err := r.db.QueryRow(context.Background(),
-		`SELECT s.id, s.test_id, s.user_id, s.schedule_type::text, s.cron_expression, s.next_run_at,
-			s.vus, s.duration, s.status::text, s.last_run_at, s.run_count,
+		`SELECT s.id, s.test_id, s.user_id, s.schedule_type::text, s.cron_expression, s.interval_seconds, s.timezone, s.next_run_at,
+			s.vus, s.duration, s.status::text, s.last_run_at, s.run_count, s.end_at, s.max_runs,
 			s.created_at, s.updated_at,
-			t.name, d.name
+			t.name, d.name, `+scheduleLastRunColumns+`
 		FROM schedules s
 		JOIN tests t ON t.id = s.test_id
 		JOIN domains d ON d.id = t.domain_id
+		`+scheduleLastRunJoin+`
 		WHERE s.id = $1`, id,
 	).Scan(
-		&s.ID, &s.TestID, &s.UserID, &s.ScheduleType, &s.CronExpression, &s.NextRunAt,
-		&s.VUs, &s.Duration, &s.Status, &s.LastRunAt, &s.RunCount,
+		&s.ID, &s.TestID, &s.UserID, &s.ScheduleType, &s.CronExpression, &s.IntervalSeconds, &s.Timezone, &s.NextRunAt,
+		&s.VUs, &s.Duration, &s.Status, &s.LastRunAt, &s.RunCount, &s.EndAt, &s.MaxRuns,
 		&s.CreatedAt, &s.UpdatedAt,
 		&s.TestName, &s.DomainName,
+		&lr.id, &lr.status, &lr.startedAt, &lr.completedAt, &lr.errorRate, &lr.p95,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -60,17 +101,19 @@ func (r *ScheduleRepository) GetByID(id uuid.UUID) (*domain.Schedule, error) {
 		}
 		return nil, err
 	}
+	s.LastRun = lr.lastRun()
 	return s, nil
 }
 
 func (r *ScheduleRepository) Update(s *domain.Schedule) error {
 	s.UpdatedAt = time.Now()
 	_, err := r.db.Exec(context.Background(),
-		`UPDATE schedules SET cron_expression=$1, next_run_at=$2, vus=$3, duration=$4,
-			status=$5::schedule_status, last_run_at=$6, run_count=$7, updated_at=$8
-		WHERE id=$9`,
-		s.CronExpression, s.NextRunAt, s.VUs, s.Duration,
-		string(s.Status), s.LastRunAt, s.RunCount, s.UpdatedAt, s.ID,
+		`UPDATE schedules SET cron_expression=$1, interval_seconds=$2, timezone=$3, next_run_at=$4, vus=$5,
+			duration=$6, status=$7::schedule_status, last_run_at=$8, run_count=$9, end_at=$10, max_runs=$11,
+			updated_at=$12
+		WHERE id=$13`,
+		s.CronExpression, s.IntervalSeconds, s.Timezone, s.NextRunAt, s.VUs, s.Duration,
+		string(s.Status), s.LastRunAt, s.RunCount, s.EndAt, s.MaxRuns, s.UpdatedAt, s.ID,
 	)
 	return err
 }
@@ -114,15 +157,16 @@ func (r *ScheduleRepository) List(filter domain.ScheduleFilter) ([]domain.Schedu
 	}
 
 	query := fmt.Sprintf(
-		`SELECT s.id, s.test_id, s.user_id, s.schedule_type::text, s.cron_expression, s.next_run_at,
-			s.vus, s.duration, s.status::text, s.last_run_at, s.run_count,
+		`SELECT s.id, s.test_id, s.user_id, s.schedule_type::text, s.cron_expression, s.interval_seconds, s.timezone, s.next_run_at,
+			s.vus, s.duration, s.status::text, s.last_run_at, s.run_count, s.end_at, s.max_runs,
 			s.created_at, s.updated_at,
-			t.name, d.name
+			t.name, d.name, %s
 		FROM schedules s
 		JOIN tests t ON t.id = s.test_id
 		JOIN domains d ON d.id = t.domain_id
+		%s
 		WHERE %s ORDER BY s.created_at DESC LIMIT $%d OFFSET $%d`,
-		whereClause, argIdx, argIdx+1,
+		scheduleLastRunColumns, scheduleLastRunJoin, whereClause, argIdx, argIdx+1,
 	)
 	args = append(args, filter.Limit(), filter.Offset())
 
@@ -135,14 +179,17 @@ func (r *ScheduleRepository) List(filter domain.ScheduleFilter) ([]domain.Schedu
 	var schedules []domain.Schedule
 	for rows.Next() {
 		var s domain.Schedule
+		var lr lastRunScan
 		if err := rows.Scan(
-			&s.ID, &s.TestID, &s.UserID, &s.ScheduleType, &s.CronExpression, &s.NextRunAt,
-			&s.VUs, &s.Duration, &s.Status, &s.LastRunAt, &s.RunCount,
+			&s.ID, &s.TestID, &s.UserID, &s.ScheduleType, &s.CronExpression, &s.IntervalSeconds, &s.Timezone, &s.NextRunAt,
+			&s.VUs, &s.Duration, &s.Status, &s.LastRunAt, &s.RunCount, &s.EndAt, &s.MaxRuns,
 			&s.CreatedAt, &s.UpdatedAt,
 			&s.TestName, &s.DomainName,
+			&lr.id, &lr.status, &lr.startedAt, &lr.completedAt, &lr.errorRate, &lr.p95,
 		); err != nil {
 			return nil, 0, err
 		}
+		s.LastRun = lr.lastRun()
 		schedules = append(schedules, s)
 	}
 
@@ -154,8 +201,8 @@ func (r *ScheduleRepository) List(filter domain.ScheduleFilter) ([]domain.Schedu
 
 func (r *ScheduleRepository) GetDueSchedules() ([]domain.Schedule, error) {
 	rows, err := r.db.Query(context.Background(),
-		`SELECT s.id, s.test_id, s.user_id, s.schedule_type::text, s.cron_expression, s.next_run_at,
-			s.vus, s.duration, s.status::text, s.last_run_at, s.run_count,
+		`SELECT s.id, s.test_id, s.user_id, s.schedule_type::text, s.cron_expression, s.interval_seconds, s.timezone, s.next_run_at,
+			s.vus, s.duration, s.status::text, s.last_run_at, s.run_count, s.end_at, s.max_runs,
 			s.created_at, s.updated_at
 		FROM schedules s
 		WHERE s.status::text = 'ACTIVE' AND s.next_run_at <= NOW()`,
@@ -169,8 +216,8 @@ func (r *ScheduleRepository) GetDueSchedules() ([]domain.Schedule, error) {
 	for rows.Next() {
 		var s domain.Schedule
 		if err := rows.Scan(
-			&s.ID, &s.TestID, &s.UserID, &s.ScheduleType, &s.CronExpression, &s.NextRunAt,
-			&s.VUs, &s.Duration, &s.Status, &s.LastRunAt, &s.RunCount,
+			&s.ID, &s.TestID, &s.UserID, &s.ScheduleType, &s.CronExpression, &s.IntervalSeconds, &s.Timezone, &s.NextRunAt,
+			&s.VUs, &s.Duration, &s.Status, &s.LastRunAt, &s.RunCount, &s.EndAt, &s.MaxRuns,
 			&s.CreatedAt, &s.UpdatedAt,
 		); err != nil {
 			return nil, err
@@ -179,3 +226,48 @@ func (r *ScheduleRepository) GetDueSchedules() ([]domain.Schedule, error) {
 	}
 	return schedules, nil
 }
+
+func (r *ScheduleRepository) AddRun(run *domain.ScheduleRun) error {
+	run.ID = uuid.New()
+	run.CreatedAt = time.Now()
+	_, err := r.db.Exec(context.Background(),
+		`INSERT INTO schedule_runs (id, schedule_id, execution_id, outcome, error, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		run.ID, run.ScheduleID, run.ExecutionID, string(run.Outcome), run.Error, run.CreatedAt,
+	)
+	return err
+}
+
+// ListRuns returns the schedule's dispatch attempts, newest first.
+func (r *ScheduleRepository) ListRuns(scheduleID uuid.UUID, pagination domain.Pagination) ([]domain.ScheduleRun, int64, error) {
+	var total int64
+	err := r.db.QueryRow(context.Background(),
+		`SELECT COUNT(*) FROM schedule_runs WHERE schedule_id = $1`, scheduleID,
+	).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(context.Background(),
+		`SELECT id, schedule_id, execution_id, outcome, error, created_at
+		FROM schedule_runs WHERE schedule_id = $1
+		ORDER BY created_at DESC LIMIT $2 OFFSET $3`,
+		scheduleID, pagination.Limit(), pagination.Offset(),
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	runs := []domain.ScheduleRun{}
+	for rows.Next() {
+		var run domain.ScheduleRun
+		var outcome string
+		if err := rows.Scan(&run.ID, &run.ScheduleID, &run.ExecutionID, &outcome, &run.Error, &run.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		run.Outcome = domain.ScheduleRunOutcome(outcome)
+		runs = append(runs, run)
+	}
+	return runs, total, rows.Err()
+}
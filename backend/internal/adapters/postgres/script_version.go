@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+type ScriptVersionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewScriptVersionRepository(db *pgxpool.Pool) *ScriptVersionRepository {
+	return &ScriptVersionRepository{db: db}
+}
+
+func (r *ScriptVersionRepository) Create(v *domain.ScriptVersion) error {
+	v.ID = uuid.New()
+	v.CreatedAt = time.Now()
+
+	return r.db.QueryRow(context.Background(),
+		`INSERT INTO test_script_versions (id, test_id, version, filename, content, size_bytes,
+			created_by, restored_from, created_at)
+		SELECT $1, $2, COALESCE(MAX(version), 0) + 1, $3, $4, $5, $6, $7, $8
+		FROM test_script_versions WHERE test_id = $2
+		RETURNING version`,
+		v.ID, v.TestID, v.Filename, v.Content, v.SizeBytes,
+		v.CreatedBy, v.RestoredFrom, v.CreatedAt,
+	).Scan(&v.Version)
+}
+
+func (r *ScriptVersionRepository) ListByTest(testID uuid.UUID) ([]domain.ScriptVersion, error) {
+	rows, err := r.db.Query(context.Background(),
+		`SELECT v.id, v.test_id, v.version, v.filename, v.size_bytes, v.created_by, v.restored_from,
+			v.created_at, u.name
+		FROM test_script_versions v
+		LEFT JOIN users u ON u.id = v.created_by
+		WHERE v.test_id = $1
+		ORDER BY v.version DESC`, testID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := []domain.ScriptVersion{}
+	for rows.Next() {
+		var v domain.ScriptVersion
+		if err := rows.Scan(
+			&v.ID, &v.TestID, &v.Version, &v.Filename, &v.SizeBytes, &v.CreatedBy, &v.RestoredFrom,
+			&v.CreatedAt, &v.CreatedByName,
+		); err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
+
+func (r *ScriptVersionRepository) Get(testID uuid.UUID, version int) (*domain.ScriptVersion, error) {
+	v := &domain.ScriptVersion{}
+	err := r.db.QueryRow(context.Background(),
+		`SELECT v.id, v.test_id, v.version, v.filename, v.content, v.size_bytes, v.created_by, v.restored_from,
+			v.created_at, u.name
+		FROM test_script_versions v
+		LEFT JOIN users u ON u.id = v.created_by
+		WHERE v.test_id = $1 AND v.version = $2`, testID, version,
+	).Scan(
+		&v.ID, &v.TestID, &v.Version, &v.Filename, &v.Content, &v.SizeBytes, &v.CreatedBy, &v.RestoredFrom,
+		&v.CreatedAt, &v.CreatedByName,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return v, nil
+}
@@ -21,28 +21,58 @@ func NewSessionRepository(db *pgxpool.Pool) *SessionRepository {
 }
 
 func (r *SessionRepository) Create(session *domain.Session) error {
-	session.ID = uuid.New()
+	if session.ID == uuid.Nil {
+		session.ID = uuid.New()
+	}
 	session.CreatedAt = time.Now()
+	if session.FamilyID == uuid.Nil {
+		session.FamilyID = session.ID
+	}
 
 	_, err := r.db.Exec(context.Background(),
-		`INSERT INTO sessions (id, user_id, token_hash, user_agent, ip_address, expires_at, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		`INSERT INTO sessions (id, user_id, token_hash, user_agent, ip_address, expires_at, created_at,
+			family_id, parent_token_hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
 		session.ID, session.UserID, session.TokenHash,
 		session.UserAgent, session.IPAddress,
 		session.ExpiresAt, session.CreatedAt,
+		session.FamilyID, session.ParentTokenHash,
 	)
 	return err
 }
 
+func (r *SessionRepository) GetByID(id uuid.UUID) (*domain.Session, error) {
+	session := &domain.Session{}
+	err := r.db.QueryRow(context.Background(),
+		`SELECT id, user_id, token_hash, user_agent, ip_address, expires_at, created_at, revoked_at,
+			family_id, parent_token_hash, rotated_at
+		FROM sessions WHERE id = $1`, id,
+	).Scan(
+		&session.ID, &session.UserID, &session.TokenHash,
+		&session.UserAgent, &session.IPAddress,
+		&session.ExpiresAt, &session.CreatedAt, &session.RevokedAt,
+		&session.FamilyID, &session.ParentTokenHash, &session.RotatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return session, nil
+}
+
 func (r *SessionRepository) GetByTokenHash(hash string) (*domain.Session, error) {
 	session := &domain.Session{}
 	err := r.db.QueryRow(context.Background(),
-		`SELECT id, user_id, token_hash, user_agent, ip_address, expires_at, created_at, revoked_at
+		`SELECT id, user_id, token_hash, user_agent, ip_address, expires_at, created_at, revoked_at,
+			family_id, parent_token_hash, rotated_at
 		FROM sessions WHERE token_hash = $1`, hash,
 	).Scan(
 		&session.ID, &session.UserID, &session.TokenHash,
 		&session.UserAgent, &session.IPAddress,
 		&session.ExpiresAt, &session.CreatedAt, &session.RevokedAt,
+		&session.FamilyID, &session.ParentTokenHash, &session.RotatedAt,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -53,6 +83,39 @@ func (r *SessionRepository) GetByTokenHash(hash string) (*domain.Session, error)
 	return session, nil
 }
 
+func (r *SessionRepository) ListByUser(userID uuid.UUID) ([]domain.Session, error) {
+	rows, err := r.db.Query(context.Background(),
+		`SELECT id, user_id, token_hash, user_agent, ip_address, expires_at, created_at, revoked_at,
+			family_id, parent_token_hash, rotated_at
+		FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY created_at DESC`, userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []domain.Session
+	for rows.Next() {
+		var s domain.Session
+		if err := rows.Scan(
+			&s.ID, &s.UserID, &s.TokenHash,
+			&s.UserAgent, &s.IPAddress,
+			&s.ExpiresAt, &s.CreatedAt, &s.RevokedAt,
+			&s.FamilyID, &s.ParentTokenHash, &s.RotatedAt,
+		); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+
+	if sessions == nil {
+		sessions = []domain.Session{}
+	}
+	return sessions, nil
+}
+
 func (r *SessionRepository) Revoke(id uuid.UUID) error {
 	now := time.Now()
 	_, err := r.db.Exec(context.Background(),
@@ -61,6 +124,17 @@ func (r *SessionRepository) Revoke(id uuid.UUID) error {
 	return err
 }
 
+func (r *SessionRepository) Rotate(id uuid.UUID) (bool, error) {
+	now := time.Now()
+	tag, err := r.db.Exec(context.Background(),
+		`UPDATE sessions SET revoked_at = $1, rotated_at = $1 WHERE id = $2 AND revoked_at IS NULL`, now, id,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
 func (r *SessionRepository) RevokeAllForUser(userID uuid.UUID) error {
 	now := time.Now()
 	_, err := r.db.Exec(context.Background(),
@@ -69,9 +143,14 @@ func (r *SessionRepository) RevokeAllForUser(userID uuid.UUID) error {
 	return err
 }
 
-func (r *SessionRepository) CleanExpired() error {
-	_, err := r.db.Exec(context.Background(),
-		`DELETE FROM sessions WHERE expires_at < NOW() OR revoked_at IS NOT NULL`,
+// CleanExpired deletes expired and revoked sessions. Rotated sessions are kept
+// until they expire, so that replaying their token is still detected.
+func (r *SessionRepository) CleanExpired() (int64, error) {
+	tag, err := r.db.Exec(context.Background(),
+		`DELETE FROM sessions WHERE expires_at < NOW() OR (revoked_at IS NOT NULL AND rotated_at IS NULL)`,
 	)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
 }
@@ -27,12 +27,15 @@ func (r *TestRepository) Create(t *domain.Test) error {
 	t.UpdatedAt = time.Now()
 
 	_, err := r.db.Exec(context.Background(),
-		`INSERT INTO tests (id, domain_id, user_id, name, description, script_filename, script_path,
-			script_size_bytes, default_vus, default_duration, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
-		t.ID, t.DomainID, t.UserID, t.Name, t.Description, t.ScriptFilename, t.ScriptPath,
-		t.ScriptSizeBytes, t.DefaultVUs, t.DefaultDuration,
-		t.CreatedAt, t.UpdatedAt,
+		`INSERT INTO tests (id, domain_id, user_id, name, description, tags, script_filename, script_path,
+			script_size_bytes, default_vus, default_duration, cooldown, success_status_codes,
+			webhook_url, webhook_secret, thresholds, max_retries, retry_backoff, default_stages,
+			max_memory_mb, max_cpus, summary_trend_stats, normalize_urls, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25)`,
+		t.ID, t.DomainID, t.UserID, t.Name, t.Description, tagsOrEmpty(t.Tags), t.ScriptFilename, t.ScriptPath,
+		t.ScriptSizeBytes, t.DefaultVUs, t.DefaultDuration, t.Cooldown, t.SuccessStatusCodes,
+		t.WebhookURL, t.WebhookSecret, t.Thresholds, t.MaxRetries, t.RetryBackoff, t.DefaultStages,
+		t.MaxMemoryMB, t.MaxCPUs, t.SummaryTrendStats, t.NormalizeURLs, t.CreatedAt, t.UpdatedAt,
 	)
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint") {
@@ -46,9 +49,11 @@ func (r *TestRepository) Create(t *domain.Test) error {
 func (r *TestRepository) GetByID(id uuid.UUID) (*domain.Test, error) {
 	t := &domain.Test{}
 	err := r.db.QueryRow(context.Background(),
-		`SELECT t.id, t.domain_id, t.user_id, t.name, t.description,
+		`SELECT t.id, t.domain_id, t.user_id, t.name, t.description, t.tags,
 			t.script_filename, t.script_path, t.script_size_bytes,
-			t.default_vus, t.default_duration,
+			t.default_vus, t.default_duration, t.cooldown, t.success_status_codes,
+			t.webhook_url, t.webhook_secret, t.thresholds, t.max_retries, t.retry_backoff, t.default_stages,
+			t.max_memory_mb, t.max_cpus, t.summary_trend_stats, t.normalize_urls, t.baseline_execution_id, t.grafana_dashboard_uid, t.grafana_dashboard_url,
 			t.created_at, t.updated_at, t.deleted_at,
 			d.name, u.name, u.email
 		FROM tests t
@@ -56,9 +61,11 @@ func (r *TestRepository) GetByID(id uuid.UUID) (*domain.Test, error) {
 		JOIN users u ON u.id = t.user_id
 		WHERE t.id = $1 AND t.deleted_at IS NULL`, id,
 	).Scan(
-		&t.ID, &t.DomainID, &t.UserID, &t.Name, &t.Description,
+		&t.ID, &t.DomainID, &t.UserID, &t.Name, &t.Description, &t.Tags,
 		&t.ScriptFilename, &t.ScriptPath, &t.ScriptSizeBytes,
-		&t.DefaultVUs, &t.DefaultDuration,
+		&t.DefaultVUs, &t.DefaultDuration, &t.Cooldown, &t.SuccessStatusCodes,
+		&t.WebhookURL, &t.WebhookSecret, &t.Thresholds, &t.MaxRetries, &t.RetryBackoff, &t.DefaultStages,
+		&t.MaxMemoryMB, &t.MaxCPUs, &t.SummaryTrendStats, &t.NormalizeURLs, &t.BaselineExecutionID, &t.GrafanaDashboardUID, &t.GrafanaDashboardURL,
 		&t.CreatedAt, &t.UpdatedAt, &t.DeletedAt,
 		&t.DomainName, &t.UserName, &t.UserEmail,
 	)
@@ -74,15 +81,19 @@ func (r *TestRepository) GetByID(id uuid.UUID) (*domain.Test, error) {
 func (r *TestRepository) GetByDomainAndName(domainID uuid.UUID, name string) (*domain.Test, error) {
 	t := &domain.Test{}
 	err := r.db.QueryRow(context.Background(),
-		`SELECT id, domain_id, user_id, name, description,
+		`SELECT id, domain_id, user_id, name, description, tags,
 			script_filename, script_path, script_size_bytes,
-			default_vus, default_duration,
+			default_vus, default_duration, cooldown, success_status_codes,
+			webhook_url, webhook_secret, thresholds, max_retries, retry_backoff, default_stages,
+			max_memory_mb, max_cpus, summary_trend_stats, normalize_urls, baseline_execution_id, grafana_dashboard_uid, grafana_dashboard_url,
 			created_at, updated_at, deleted_at
 		FROM tests WHERE domain_id = $1 AND name = $2 AND deleted_at IS NULL`, domainID, name,
 	).Scan(
-		&t.ID, &t.DomainID, &t.UserID, &t.Name, &t.Description,
+		&t.ID, &t.DomainID, &t.UserID, &t.Name, &t.Description, &t.Tags,
 		&t.ScriptFilename, &t.ScriptPath, &t.ScriptSizeBytes,
-		&t.DefaultVUs, &t.DefaultDuration,
+		&t.DefaultVUs, &t.DefaultDuration, &t.Cooldown, &t.SuccessStatusCodes,
+		&t.WebhookURL, &t.WebhookSecret, &t.Thresholds, &t.MaxRetries, &t.RetryBackoff, &t.DefaultStages,
+		&t.MaxMemoryMB, &t.MaxCPUs, &t.SummaryTrendStats, &t.NormalizeURLs, &t.BaselineExecutionID, &t.GrafanaDashboardUID, &t.GrafanaDashboardURL,
 		&t.CreatedAt, &t.UpdatedAt, &t.DeletedAt,
 	)
 	if err != nil {
@@ -98,10 +109,35 @@ func (r *TestRepository) Update(t *domain.Test) error {
 	t.UpdatedAt = time.Now()
 	_, err := r.db.Exec(context.Background(),
 		`UPDATE tests SET name=$1, description=$2, script_filename=$3, script_path=$4,
-			script_size_bytes=$5, default_vus=$6, default_duration=$7, updated_at=$8
-		WHERE id=$9 AND deleted_at IS NULL`,
+			script_size_bytes=$5, default_vus=$6, default_duration=$7, cooldown=$8,
+			success_status_codes=$9, webhook_url=$10, webhook_secret=$11, thresholds=$12,
+			max_retries=$13, retry_backoff=$14, default_stages=$15, tags=$16,
+			max_memory_mb=$17, max_cpus=$18, summary_trend_stats=$19, normalize_urls=$20, updated_at=$21
+		WHERE id=$22 AND deleted_at IS NULL`,
 		t.Name, t.Description, t.ScriptFilename, t.ScriptPath,
-		t.ScriptSizeBytes, t.DefaultVUs, t.DefaultDuration, t.UpdatedAt, t.ID,
+		t.ScriptSizeBytes, t.DefaultVUs, t.DefaultDuration, t.Cooldown,
+		t.SuccessStatusCodes, t.WebhookURL, t.WebhookSecret, t.Thresholds,
+		t.MaxRetries, t.RetryBackoff, t.DefaultStages, tagsOrEmpty(t.Tags),
+		t.MaxMemoryMB, t.MaxCPUs, t.SummaryTrendStats, t.NormalizeURLs, t.UpdatedAt, t.ID,
+	)
+	return err
+}
+
+// SetGrafanaDashboard records the dashboard provisioned for the test.
+func (r *TestRepository) SetGrafanaDashboard(id uuid.UUID, uid, url string) error {
+	_, err := r.db.Exec(context.Background(),
+		`UPDATE tests SET grafana_dashboard_uid=$1, grafana_dashboard_url=$2, updated_at=$3
+		WHERE id=$4 AND deleted_at IS NULL`,
+		uid, url, time.Now(), id,
+	)
+	return err
+}
+
+func (r *TestRepository) SetBaseline(id uuid.UUID, executionID *uuid.UUID) error {
+	_, err := r.db.Exec(context.Background(),
+		`UPDATE tests SET baseline_execution_id=$1, updated_at=$2
+		WHERE id=$3 AND deleted_at IS NULL`,
+		executionID, time.Now(), id,
 	)
 	return err
 }
@@ -135,6 +171,11 @@ func (r *TestRepository) List(filter domain.TestFilter) ([]domain.Test, int64, e
 		args = append(args, "%"+*filter.Search+"%")
 		argIdx++
 	}
+	if len(filter.Tags) > 0 {
+		where = append(where, fmt.Sprintf("t.tags %s $%d", tagsOperator(filter.MatchAllTags), argIdx))
+		args = append(args, filter.Tags)
+		argIdx++
+	}
 
 	whereClause := strings.Join(where, " AND ")
 
@@ -147,9 +188,11 @@ func (r *TestRepository) List(filter domain.TestFilter) ([]domain.Test, int64, e
 	}
 
 	query := fmt.Sprintf(
-		`SELECT t.id, t.domain_id, t.user_id, t.name, t.description,
+		`SELECT t.id, t.domain_id, t.user_id, t.name, t.description, t.tags,
 			t.script_filename, t.script_path, t.script_size_bytes,
-			t.default_vus, t.default_duration,
+			t.default_vus, t.default_duration, t.cooldown, t.success_status_codes,
+			t.webhook_url, t.webhook_secret, t.thresholds, t.max_retries, t.retry_backoff, t.default_stages,
+			t.max_memory_mb, t.max_cpus, t.summary_trend_stats, t.normalize_urls, t.baseline_execution_id, t.grafana_dashboard_uid, t.grafana_dashboard_url,
 			t.created_at, t.updated_at, t.deleted_at,
 			d.name, u.name, u.email
 		FROM tests t
@@ -170,9 +213,11 @@ func (r *TestRepository) List(filter domain.TestFilter) ([]domain.Test, int64, e
 	for rows.Next() {
 		var t domain.Test
 		if err := rows.Scan(
-			&t.ID, &t.DomainID, &t.UserID, &t.Name, &t.Description,
+			&t.ID, &t.DomainID, &t.UserID, &t.Name, &t.Description, &t.Tags,
 			&t.ScriptFilename, &t.ScriptPath, &t.ScriptSizeBytes,
-			&t.DefaultVUs, &t.DefaultDuration,
+			&t.DefaultVUs, &t.DefaultDuration, &t.Cooldown, &t.SuccessStatusCodes,
+			&t.WebhookURL, &t.WebhookSecret, &t.Thresholds, &t.MaxRetries, &t.RetryBackoff, &t.DefaultStages,
+			&t.MaxMemoryMB, &t.MaxCPUs, &t.SummaryTrendStats, &t.NormalizeURLs, &t.BaselineExecutionID, &t.GrafanaDashboardUID, &t.GrafanaDashboardURL,
 			&t.CreatedAt, &t.UpdatedAt, &t.DeletedAt,
 			&t.DomainName, &t.UserName, &t.UserEmail,
 		); err != nil {
@@ -28,11 +28,13 @@ func (r *UserRepository) Create(user *domain.User) error {
 	user.UpdatedAt = time.Now()
 
 	_, err := r.db.Exec(context.Background(),
-		`INSERT INTO users (id, email, password_hash, name, role, status, grafana_user_id, grafana_username, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5::user_role, $6::user_status, $7, $8, $9, $10)`,
+		`INSERT INTO users (id, email, password_hash, name, role, status, grafana_user_id, grafana_username,
+			grafana_folder_uid, totp_secret, totp_enabled, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5::user_role, $6::user_status, $7, $8, $9, $10, $11, $12, $13)`,
 		user.ID, user.Email, user.PasswordHash, user.Name,
 		string(user.Role), string(user.Status),
-		user.GrafanaUserID, user.GrafanaUsername,
+		user.GrafanaUserID, user.GrafanaUsername, user.GrafanaFolderUID,
+		user.TOTPSecret, user.TOTPEnabled,
 		user.CreatedAt, user.UpdatedAt,
 	)
 	if err != nil {
@@ -48,13 +50,13 @@ func (r *UserRepository) GetByID(id uuid.UUID) (*domain.User, error) {
 	user := &domain.User{}
 	err := r.db.QueryRow(context.Background(),
 		`SELECT id, email, password_hash, name, role::text, status::text,
-			grafana_user_id, grafana_username, last_login_at,
+			grafana_user_id, grafana_username, grafana_folder_uid, totp_secret, totp_enabled, last_login_at,
 			created_at, updated_at, deleted_at
 		FROM users WHERE id = $1 AND deleted_at IS NULL`, id,
 	).Scan(
 		&user.ID, &user.Email, &user.PasswordHash, &user.Name,
 		&user.Role, &user.Status,
-		&user.GrafanaUserID, &user.GrafanaUsername, &user.LastLoginAt,
+		&user.GrafanaUserID, &user.GrafanaUsername, &user.GrafanaFolderUID, &user.TOTPSecret, &user.TOTPEnabled, &user.LastLoginAt,
 		&user.CreatedAt, &user.UpdatedAt, &user.DeletedAt,
 	)
 	if err != nil {
@@ -70,13 +72,13 @@ func (r *UserRepository) GetByEmail(email string) (*domain.User, error) {
 	user := &domain.User{}
 	err := r.db.QueryRow(context.Background(),
 		`SELECT id, email, password_hash, name, role::text, status::text,
-			grafana_user_id, grafana_username, last_login_at,
+			grafana_user_id, grafana_username, grafana_folder_uid, totp_secret, totp_enabled, last_login_at,
 			created_at, updated_at, deleted_at
 		FROM users WHERE email = $1 AND deleted_at IS NULL`, email,
 	).Scan(
 		&user.ID, &user.Email, &user.PasswordHash, &user.Name,
 		&user.Role, &user.Status,
-		&user.GrafanaUserID, &user.GrafanaUsername, &user.LastLoginAt,
+		&user.GrafanaUserID, &user.GrafanaUsername, &user.GrafanaFolderUID, &user.TOTPSecret, &user.TOTPEnabled, &user.LastLoginAt,
 		&user.CreatedAt, &user.UpdatedAt, &user.DeletedAt,
 	)
 	if err != nil {
@@ -92,16 +94,50 @@ func (r *UserRepository) Update(user *domain.User) error {
 	user.UpdatedAt = time.Now()
 	_, err := r.db.Exec(context.Background(),
 		`UPDATE users SET email=$1, password_hash=$2, name=$3, role=$4::user_role, status=$5::user_status,
-			grafana_user_id=$6, grafana_username=$7, last_login_at=$8, updated_at=$9
-		WHERE id = $10 AND deleted_at IS NULL`,
+			grafana_user_id=$6, grafana_username=$7, grafana_folder_uid=$8, totp_secret=$9, totp_enabled=$10,
+			last_login_at=$11, updated_at=$12
+		WHERE id = $13 AND deleted_at IS NULL`,
 		user.Email, user.PasswordHash, user.Name,
 		string(user.Role), string(user.Status),
-		user.GrafanaUserID, user.GrafanaUsername, user.LastLoginAt,
-		user.UpdatedAt, user.ID,
+		user.GrafanaUserID, user.GrafanaUsername, user.GrafanaFolderUID, user.TOTPSecret, user.TOTPEnabled,
+		user.LastLoginAt, user.UpdatedAt, user.ID,
 	)
 	return err
 }
 
+func (r *UserRepository) StartTwoFactorChallenge(userID, challengeID uuid.UUID) error {
+	_, err := r.db.Exec(context.Background(),
+		`UPDATE users SET totp_challenge_id = $2, totp_challenge_attempts = 0 WHERE id = $1`,
+		userID, challengeID,
+	)
+	return err
+}
+
+func (r *UserRepository) CompleteTwoFactorChallenge(userID, challengeID uuid.UUID, step int64) (bool, error) {
+	tag, err := r.db.Exec(context.Background(),
+		`UPDATE users SET totp_challenge_id = NULL, totp_challenge_attempts = 0, totp_last_step = $3
+		WHERE id = $1 AND totp_challenge_id = $2 AND (totp_last_step IS NULL OR totp_last_step < $3)`,
+		userID, challengeID, step,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *UserRepository) FailTwoFactorChallenge(userID, challengeID uuid.UUID, maxAttempts int) (bool, error) {
+	tag, err := r.db.Exec(context.Background(),
+		`UPDATE users SET totp_challenge_attempts = totp_challenge_attempts + 1,
+			totp_challenge_id = CASE WHEN totp_challenge_attempts + 1 >= $3 THEN NULL ELSE totp_challenge_id END
+		WHERE id = $1 AND totp_challenge_id = $2`,
+		userID, challengeID, maxAttempts,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
 func (r *UserRepository) Delete(id uuid.UUID) error {
 	now := time.Now()
 	_, err := r.db.Exec(context.Background(),
@@ -144,7 +180,7 @@ func (r *UserRepository) List(filter domain.UserFilter) ([]domain.User, int64, e
 	// Fetch
 	query := fmt.Sprintf(
 		`SELECT id, email, password_hash, name, role::text, status::text,
-			grafana_user_id, grafana_username, last_login_at,
+			grafana_user_id, grafana_username, grafana_folder_uid, totp_secret, totp_enabled, last_login_at,
 			created_at, updated_at, deleted_at
 		FROM users WHERE %s ORDER BY created_at DESC LIMIT $%d OFFSET $%d`,
 		whereClause, argIdx, argIdx+1,
@@ -163,7 +199,7 @@ func (r *UserRepository) List(filter domain.UserFilter) ([]domain.User, int64, e
 		if err := rows.Scan(
 			&u.ID, &u.Email, &u.PasswordHash, &u.Name,
 			&u.Role, &u.Status,
-			&u.GrafanaUserID, &u.GrafanaUsername, &u.LastLoginAt,
+			&u.GrafanaUserID, &u.GrafanaUsername, &u.GrafanaFolderUID, &u.TOTPSecret, &u.TOTPEnabled, &u.LastLoginAt,
 			&u.CreatedAt, &u.UpdatedAt, &u.DeletedAt,
 		); err != nil {
 			return nil, 0, err
@@ -0,0 +1,137 @@
+// Package prometheus exports platform health for Prometheus: HTTP request
+// rates and latencies plus k6 execution gauges.
+package prometheus
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/app"
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+// statuses are always exported so a status with no executions reads 0
+// instead of disappearing from the series.
+var statuses = []domain.TestStatus{
+	domain.TestStatusPending,
+	domain.TestStatusRunning,
+	domain.TestStatusCompleted,
+	domain.TestStatusFailed,
+	domain.TestStatusCancelled,
+	domain.TestStatusTimeout,
+}
+
+type Metrics struct {
+	registry        *prometheus.Registry
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+}
+
+func NewMetrics(runner *app.K6Runner, execRepo domain.ExecutionRepository) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests by method, route pattern and status code.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by method and route pattern.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestDuration,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "k6_executions_running",
+			Help: "k6 executions currently running on this instance.",
+		}, func() float64 {
+			running, _ := runner.Load()
+			return float64(running)
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "k6_executions_queued",
+			Help: "Executions waiting for a free k6 slot.",
+		}, func() float64 {
+			_, queued := runner.Load()
+			return float64(queued)
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "k6_aggregation_lag_seconds",
+			Help: "Time between k6 exiting and the metrics being aggregated, for the most recently aggregated execution.",
+		}, func() float64 {
+			lag, ok, err := execRepo.LastAggregationLag()
+			if err != nil || !ok {
+				return 0
+			}
+			return lag.Seconds()
+		}),
+		&statusCollector{
+			execRepo: execRepo,
+			desc: prometheus.NewDesc("k6_executions",
+				"Executions stored in the database by status (soft-deleted excluded).",
+				[]string{"status"}, nil),
+		},
+	)
+	return m
+}
+
+// Handler serves the registry in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Instrument counts and times requests. Routes are labelled by their chi
+// pattern (e.g. /api/v1/tests/{id}) to keep the label cardinality bounded.
+func (m *Metrics) Instrument(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r)
+
+		route := "unmatched"
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		m.requests.WithLabelValues(r.Method, route, strconv.Itoa(status)).Inc()
+		m.requestDuration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+	})
+}
+
+// statusCollector queries the execution counts at scrape time.
+type statusCollector struct {
+	execRepo domain.ExecutionRepository
+	desc     *prometheus.Desc
+}
+
+func (c *statusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *statusCollector) Collect(ch chan<- prometheus.Metric) {
+	counts, err := c.execRepo.CountByStatus()
+	if err != nil {
+		log.Printf("[Metrics] Failed to count executions by status: %v", err)
+		return
+	}
+	for _, status := range statuses {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(counts[status]), string(status))
+	}
+}
@@ -0,0 +1,67 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+const executionEventsChannel = "exec:events"
+
+// ExecutionEvents carries execution status changes over Redis pub/sub.
+type ExecutionEvents struct {
+	client *redis.Client
+}
+
+func NewExecutionEvents(client *redis.Client) *ExecutionEvents {
+	return &ExecutionEvents{client: client}
+}
+
+func (e *ExecutionEvents) Publish(event domain.ExecutionEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return e.client.Publish(context.Background(), executionEventsChannel, payload).Err()
+}
+
+func (e *ExecutionEvents) Subscribe(ctx context.Context) (<-chan domain.ExecutionEvent, error) {
+	pubsub := e.client.Subscribe(ctx, executionEventsChannel)
+	// Wait for the confirmation so no event published after this call is missed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+
+	out := make(chan domain.ExecutionEvent)
+	go func() {
+		defer close(out)
+		defer pubsub.Close()
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				var event domain.ExecutionEvent
+				if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+					log.Printf("[Events] Dropping malformed execution event: %v", err)
+					continue
+				}
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
@@ -0,0 +1,48 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// IdempotencyStore keeps idempotency keys in Redis so a retry reaching any
+// API instance finds the original request. A key holds
+// "<fingerprint>\n<id>", with an empty id until the request completes.
+type IdempotencyStore struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewIdempotencyStore(client *redis.Client, prefix string) *IdempotencyStore {
+	return &IdempotencyStore{client: client, prefix: prefix}
+}
+
+func (s *IdempotencyStore) Reserve(key, fingerprint string, ttl time.Duration) (bool, string, string, error) {
+	ctx := context.Background()
+	ok, err := s.client.SetNX(ctx, s.prefix+key, fingerprint+"\n", ttl).Result()
+	if err != nil || ok {
+		return ok, "", "", err
+	}
+	value, err := s.client.Get(ctx, s.prefix+key).Result()
+	if errors.Is(err, redis.Nil) {
+		// Expired or released in between: try again
+		return s.Reserve(key, fingerprint, ttl)
+	}
+	if err != nil {
+		return false, "", "", err
+	}
+	storedFingerprint, id, _ := strings.Cut(value, "\n")
+	return false, id, storedFingerprint, nil
+}
+
+func (s *IdempotencyStore) Complete(key, fingerprint, id string, ttl time.Duration) error {
+	return s.client.Set(context.Background(), s.prefix+key, fingerprint+"\n"+id, ttl).Err()
+}
+
+func (s *IdempotencyStore) Release(key string) error {
+	return s.client.Del(context.Background(), s.prefix+key).Err()
+}
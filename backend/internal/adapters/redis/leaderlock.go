@@ -0,0 +1,51 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// acquireScript takes the key with SET NX, or renews it when it already holds
+// the caller's owner ID, in one round trip.
+var acquireScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+if redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ARGV[2]) then
+  return 1
+end
+return 0
+`)
+
+// releaseScript deletes the key only if the caller still owns it.
+var releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// LeaderLock is a single Redis key holding the ID of the current leader. It
+// expires unless renewed, so a crashed leader is replaced after its TTL.
+type LeaderLock struct {
+	client *redis.Client
+	key    string
+}
+
+func NewLeaderLock(client *redis.Client, key string) *LeaderLock {
+	return &LeaderLock{client: client, key: key}
+}
+
+func (l *LeaderLock) Acquire(owner string, ttl time.Duration) (bool, error) {
+	n, err := acquireScript.Run(context.Background(), l.client, []string{l.key}, owner, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (l *LeaderLock) Release(owner string) error {
+	return releaseScript.Run(context.Background(), l.client, []string{l.key}, owner).Err()
+}
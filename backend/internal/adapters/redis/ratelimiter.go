@@ -0,0 +1,82 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBucket refills limit tokens per window continuously and takes one.
+// Returns {allowed, retry_after_ms}. The hash expires once it would be full
+// again, so idle users leave nothing behind.
+var tokenBucket = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local rate = limit / window
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or limit
+local ts = tonumber(state[2]) or now
+tokens = math.min(limit, tokens + math.max(0, now - ts) * rate)
+
+local allowed, retry = 0, 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+else
+  retry = math.ceil((1 - tokens) / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], window)
+return {allowed, retry}
+`)
+
+// refundBucket puts one token back, never above limit. A missing bucket is
+// already full.
+var refundBucket = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local rate = limit / window
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+if not state[1] then
+  return 0
+end
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2]) or now
+tokens = math.min(limit, tokens + math.max(0, now - ts) * rate + 1)
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], window)
+return 1
+`)
+
+// RateLimiter keeps token buckets in Redis so every API instance shares them.
+type RateLimiter struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewRateLimiter(client *redis.Client, prefix string) *RateLimiter {
+	return &RateLimiter{client: client, prefix: prefix}
+}
+
+func (l *RateLimiter) Take(key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	res, err := tokenBucket.Run(context.Background(), l.client,
+		[]string{l.prefix + key}, limit, window.Milliseconds(), time.Now().UnixMilli(),
+	).Int64Slice()
+	if err != nil {
+		return false, 0, err
+	}
+	return res[0] == 1, time.Duration(res[1]) * time.Millisecond, nil
+}
+
+func (l *RateLimiter) Refund(key string, limit int, window time.Duration) error {
+	return refundBucket.Run(context.Background(), l.client,
+		[]string{l.prefix + key}, limit, window.Milliseconds(), time.Now().UnixMilli(),
+	).Err()
+}
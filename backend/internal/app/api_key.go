@@ -0,0 +1,107 @@
+package app
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+const apiKeyPrefix = "stp_"
+
+type APIKeyService struct {
+	apiKeyRepo domain.APIKeyRepository
+	userRepo   domain.UserRepository
+}
+
+func NewAPIKeyService(apiKeyRepo domain.APIKeyRepository, userRepo domain.UserRepository) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepo: apiKeyRepo,
+		userRepo:   userRepo,
+	}
+}
+
+func (s *APIKeyService) Create(userID uuid.UUID, input domain.CreateAPIKeyInput) (*domain.CreatedAPIKey, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, domain.NewValidationError(map[string]string{
+			"name": "Name is required",
+		})
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		return nil, domain.NewValidationError(map[string]string{
+			"expires_at": "Expiry must be in the future",
+		})
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	raw := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(b)
+
+	key := &domain.APIKey{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    raw[:len(apiKeyPrefix)+6],
+		KeyHash:   hashToken(raw),
+		ExpiresAt: input.ExpiresAt,
+	}
+	if err := s.apiKeyRepo.Create(key); err != nil {
+		return nil, err
+	}
+
+	return &domain.CreatedAPIKey{APIKey: *key, Key: raw}, nil
+}
+
+func (s *APIKeyService) List(userID uuid.UUID) ([]domain.APIKey, error) {
+	return s.apiKeyRepo.ListByUser(userID)
+}
+
+func (s *APIKeyService) Revoke(id uuid.UUID, userID uuid.UUID) error {
+	key, err := s.apiKeyRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.NewNotFoundError("API key")
+		}
+		return err
+	}
+	if key.UserID != userID {
+		return domain.NewNotFoundError("API key")
+	}
+	return s.apiKeyRepo.Revoke(key.ID)
+}
+
+// Authenticate resolves a raw API key to the owner's claims. The role is read
+// from the user record so the key carries exactly the owner's current access.
+func (s *APIKeyService) Authenticate(raw string) (*domain.TokenClaims, error) {
+	if !strings.HasPrefix(raw, apiKeyPrefix) {
+		return nil, domain.ErrTokenInvalid
+	}
+
+	key, err := s.apiKeyRepo.GetByHash(hashToken(raw))
+	if err != nil || !key.IsValid() {
+		return nil, domain.ErrTokenInvalid
+	}
+
+	user, err := s.userRepo.GetByID(key.UserID)
+	if err != nil || user.Status != domain.UserStatusActive {
+		return nil, domain.ErrTokenInvalid
+	}
+
+	if err := s.apiKeyRepo.TouchLastUsed(key.ID); err != nil {
+		log.Printf("[Auth] Failed to update API key %s last use: %v", key.ID, err)
+	}
+
+	return &domain.TokenClaims{
+		UserID: user.ID,
+		Email:  user.Email,
+		Role:   user.Role,
+	}, nil
+}
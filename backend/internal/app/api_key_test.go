@@ -0,0 +1,158 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+func newAPIKeyFixture(t *testing.T) (*APIKeyService, *fakeAPIKeyRepo, *domain.User) {
+	t.Helper()
+	user := &domain.User{Email: "user@example.com", Role: domain.UserRoleUser, Status: domain.UserStatusActive}
+	keys := newFakeAPIKeyRepo()
+	return NewAPIKeyService(keys, newFakeUserRepo(user)), keys, user
+}
+
+func TestCreateAPIKeyStoresOnlyHash(t *testing.T) {
+	svc, keys, user := newAPIKeyFixture(t)
+
+	created, err := svc.Create(user.ID, domain.CreateAPIKeyInput{Name: " ci "})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if !strings.HasPrefix(created.Key, apiKeyPrefix) {
+		t.Errorf("key %q lacks the %q prefix", created.Key, apiKeyPrefix)
+	}
+	if created.Prefix != created.Key[:len(apiKeyPrefix)+6] {
+		t.Errorf("prefix = %q, want the first characters of %q", created.Prefix, created.Key)
+	}
+
+	stored, err := keys.GetByHash(hashToken(created.Key))
+	if err != nil {
+		t.Fatalf("key not stored under its hash: %v", err)
+	}
+	if stored.KeyHash == created.Key || strings.Contains(stored.KeyHash, created.Key) {
+		t.Error("raw key stored")
+	}
+	if stored.Name != "ci" || stored.UserID != user.ID {
+		t.Errorf("stored key = %+v", stored)
+	}
+}
+
+func TestCreateAPIKeyValidation(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name  string
+		input domain.CreateAPIKeyInput
+		field string
+	}{
+		{"blank name", domain.CreateAPIKeyInput{Name: "  "}, "name"},
+		{"expiry in the past", domain.CreateAPIKeyInput{Name: "ci", ExpiresAt: &past}, "expires_at"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _, user := newAPIKeyFixture(t)
+			_, err := svc.Create(user.ID, tt.input)
+			if _, ok := validationDetails(t, err)[tt.field]; !ok {
+				t.Errorf("Create = %v, want a %s error", err, tt.field)
+			}
+		})
+	}
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	svc, keys, user := newAPIKeyFixture(t)
+	created, err := svc.Create(user.ID, domain.CreateAPIKeyInput{Name: "ci"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := svc.Authenticate(created.Key)
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if claims.UserID != user.ID || claims.Role != user.Role {
+		t.Errorf("claims = %+v, want the owner's", claims)
+	}
+	if stored, _ := keys.GetByID(created.ID); stored.LastUsedAt == nil {
+		t.Error("last use not recorded")
+	}
+}
+
+func TestAuthenticateAPIKeyRejected(t *testing.T) {
+	tests := []struct {
+		name string
+		// change returns the key to present after adjusting the stored one
+		change func(keys *fakeAPIKeyRepo, created *domain.CreatedAPIKey) string
+	}{
+		{"missing prefix", func(keys *fakeAPIKeyRepo, created *domain.CreatedAPIKey) string {
+			return strings.TrimPrefix(created.Key, apiKeyPrefix)
+		}},
+		{"unknown key", func(keys *fakeAPIKeyRepo, created *domain.CreatedAPIKey) string {
+			return created.Prefix + "unknown"
+		}},
+		{"revoked", func(keys *fakeAPIKeyRepo, created *domain.CreatedAPIKey) string {
+			keys.Revoke(created.ID)
+			return created.Key
+		}},
+		{"expired", func(keys *fakeAPIKeyRepo, created *domain.CreatedAPIKey) string {
+			key := keys.keys[created.ID]
+			expired := time.Now().Add(-time.Second)
+			key.ExpiresAt = &expired
+			keys.keys[created.ID] = key
+			return created.Key
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, keys, user := newAPIKeyFixture(t)
+			created, err := svc.Create(user.ID, domain.CreateAPIKeyInput{Name: "ci"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := svc.Authenticate(tt.change(keys, created)); !errors.Is(err, domain.ErrTokenInvalid) {
+				t.Errorf("Authenticate = %v, want ErrTokenInvalid", err)
+			}
+		})
+	}
+}
+
+func TestAuthenticateAPIKeyInactiveOwner(t *testing.T) {
+	svc, _, user := newAPIKeyFixture(t)
+	created, err := svc.Create(user.ID, domain.CreateAPIKeyInput{Name: "ci"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user.Status = domain.UserStatusSuspended
+	svc.userRepo.Update(user)
+
+	if _, err := svc.Authenticate(created.Key); !errors.Is(err, domain.ErrTokenInvalid) {
+		t.Errorf("Authenticate = %v, want ErrTokenInvalid", err)
+	}
+}
+
+func TestRevokeAPIKeyOfAnotherUser(t *testing.T) {
+	svc, _, user := newAPIKeyFixture(t)
+	created, err := svc.Create(user.ID, domain.CreateAPIKeyInput{Name: "ci"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.Revoke(created.ID, uuid.New()); errorCode(err) != "NOT_FOUND" {
+		t.Errorf("Revoke by another user = %v, want NOT_FOUND", err)
+	}
+	if _, err := svc.Authenticate(created.Key); err != nil {
+		t.Errorf("key revoked by another user: %v", err)
+	}
+	if err := svc.Revoke(created.ID, user.ID); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if _, err := svc.Authenticate(created.Key); !errors.Is(err, domain.ErrTokenInvalid) {
+		t.Errorf("Authenticate after revoke = %v, want ErrTokenInvalid", err)
+	}
+}
@@ -3,36 +3,48 @@ package app
 import (
 	"crypto/rand"
 	"crypto/sha256"
-	"crypto/subtle"
 	"encoding/base64"
 	"encoding/hex"
 	"errors"
+	"log"
+	"net/mail"
 	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
-	"golang.org/x/crypto/argon2"
 
 	"github.com/willianpsouza/StressTestPlatform/internal/domain"
 	"github.com/willianpsouza/StressTestPlatform/internal/pkg/config"
 )
 
 type AuthService struct {
-	jwtConfig   config.JWTConfig
-	userRepo    domain.UserRepository
-	sessionRepo domain.SessionRepository
+	jwtConfig      config.JWTConfig
+	passwordParams Argon2Params
+	userRepo       domain.UserRepository
+	sessionRepo    domain.SessionRepository
+	resetRepo      domain.PasswordResetRepository
+	grafana        domain.GrafanaProvisioner
+	mailer         domain.Mailer // nil when SMTP is not configured
 }
 
 func NewAuthService(
 	jwtConfig config.JWTConfig,
+	passwordConfig config.PasswordConfig,
 	userRepo domain.UserRepository,
 	sessionRepo domain.SessionRepository,
+	resetRepo domain.PasswordResetRepository,
+	grafana domain.GrafanaProvisioner,
+	mailer domain.Mailer,
 ) *AuthService {
 	return &AuthService{
-		jwtConfig:   jwtConfig,
-		userRepo:    userRepo,
-		sessionRepo: sessionRepo,
+		jwtConfig:      jwtConfig,
+		passwordParams: Argon2ParamsFromConfig(passwordConfig),
+		userRepo:       userRepo,
+		sessionRepo:    sessionRepo,
+		resetRepo:      resetRepo,
+		grafana:        grafana,
+		mailer:         mailer,
 	}
 }
 
@@ -62,7 +74,7 @@ func (s *AuthService) Register(input domain.RegisterInput) (*domain.LoginRespons
 		return nil, domain.NewConflictError("Email already registered")
 	}
 
-	passwordHash, err := HashPassword(input.Password)
+	passwordHash, err := HashPasswordWithParams(input.Password, s.passwordParams)
 	if err != nil {
 		return nil, err
 	}
@@ -79,31 +91,172 @@ func (s *AuthService) Register(input domain.RegisterInput) (*domain.LoginRespons
 		return nil, err
 	}
 
+	s.provisionGrafana(user, input.Password)
+
 	return s.generateLoginResponse(user, "", "")
 }
 
-func (s *AuthService) Login(input domain.LoginInput, ip, userAgent string) (*domain.LoginResponse, error) {
+// provisionGrafana creates the user's Grafana account and a personal folder
+// that only they can see. Grafana being unavailable must not block
+// registration, so failures are only logged.
+func (s *AuthService) provisionGrafana(user *domain.User, password string) {
+	if s.grafana == nil {
+		return
+	}
+
+	grafanaUser, err := s.grafana.CreateUser(user.Email, user.Name, password)
+	if err != nil {
+		log.Printf("[Grafana] Failed to create user for %s: %v", user.ID, err)
+		return
+	}
+	user.GrafanaUserID = &grafanaUser.ID
+	user.GrafanaUsername = &grafanaUser.Login
+
+	folder, err := s.grafana.CreateFolder(user.Name + " (" + user.Email + ")")
+	if err != nil {
+		log.Printf("[Grafana] Failed to create folder for %s: %v", user.ID, err)
+	} else if err := s.grafana.RestrictFolderToUser(folder.UID, grafanaUser.ID); err != nil {
+		log.Printf("[Grafana] Failed to set folder permissions for %s: %v", user.ID, err)
+	} else {
+		user.GrafanaFolderUID = &folder.UID
+	}
+
+	if err := s.userRepo.Update(user); err != nil {
+		log.Printf("[Grafana] Failed to save Grafana account for %s: %v", user.ID, err)
+	}
+}
+
+// Login verifies the password. When the user has TOTP enabled it returns a
+// challenge instead of tokens; the login is completed by LoginTwoFactor.
+func (s *AuthService) Login(input domain.LoginInput, ip, userAgent string) (*domain.LoginResponse, *domain.TwoFactorChallenge, error) {
 	user, err := s.userRepo.GetByEmail(input.Email)
 	if err != nil {
 		if errors.Is(err, domain.ErrUserNotFound) {
-			return nil, domain.NewUnauthorizedError("Invalid credentials")
+			return nil, nil, domain.NewUnauthorizedError("Invalid credentials")
 		}
-		return nil, err
+		return nil, nil, err
 	}
 
 	if !VerifyPassword(input.Password, user.PasswordHash) {
-		return nil, domain.NewUnauthorizedError("Invalid credentials")
+		return nil, nil, domain.NewUnauthorizedError("Invalid credentials")
 	}
 
+	if user.Status != domain.UserStatusActive {
+		return nil, nil, domain.NewUnauthorizedError("Account is not active")
+	}
+
+	// Transparently upgrade legacy hashes and outdated cost parameters
+	if passwordNeedsRehash(user.PasswordHash, s.passwordParams) {
+		if hash, err := HashPasswordWithParams(input.Password, s.passwordParams); err == nil {
+			user.PasswordHash = hash
+			if err := s.userRepo.Update(user); err != nil {
+				log.Printf("[Auth] Failed to rehash password for user %s: %v", user.ID, err)
+			}
+		}
+	}
+
+	if user.TOTPEnabled {
+		challenge, err := s.generateTwoFactorChallenge(user)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, challenge, nil
+	}
+
+	resp, err := s.completeLogin(user, ip, userAgent)
+	return resp, nil, err
+}
+
+func (s *AuthService) LoginTwoFactor(input domain.TwoFactorLoginInput, ip, userAgent string) (*domain.LoginResponse, error) {
+	userID, challengeID, err := s.parseTwoFactorChallenge(input.ChallengeToken)
+	if err != nil {
+		return nil, domain.NewUnauthorizedError("Invalid or expired challenge")
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, domain.NewUnauthorizedError("Invalid or expired challenge")
+	}
 	if user.Status != domain.UserStatusActive {
 		return nil, domain.NewUnauthorizedError("Account is not active")
 	}
+	if !user.TOTPEnabled || user.TOTPSecret == nil {
+		return nil, domain.NewUnauthorizedError("Invalid or expired challenge")
+	}
 
-	now := time.Now()
-	user.LastLoginAt = &now
-	_ = s.userRepo.Update(user)
+	// A challenge is single-use and dies after maxTwoFactorAttempts wrong
+	// codes; a code is rejected if its step is not after the last accepted one
+	if step, ok := validateTOTP(*user.TOTPSecret, input.Code, time.Now()); ok {
+		completed, err := s.userRepo.CompleteTwoFactorChallenge(user.ID, challengeID, step)
+		if err != nil {
+			return nil, err
+		}
+		if completed {
+			return s.completeLogin(user, ip, userAgent)
+		}
+	}
+	active, err := s.userRepo.FailTwoFactorChallenge(user.ID, challengeID, maxTwoFactorAttempts)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return nil, domain.NewUnauthorizedError("Invalid or expired challenge")
+	}
+	return nil, domain.NewUnauthorizedError("Invalid authentication code")
+}
 
-	return s.generateLoginResponse(user, ip, userAgent)
+// EnableTwoFactor generates a new TOTP secret for the user. It only becomes
+// active once a code is confirmed through VerifyTwoFactor.
+func (s *AuthService) EnableTwoFactor(userID uuid.UUID) (*domain.TwoFactorSetup, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, domain.NewConflictError("Two-factor authentication is already enabled")
+	}
+
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	user.TOTPSecret = &secret
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	uri := totpURI(secret, user.Email)
+	return &domain.TwoFactorSetup{
+		Secret:     secret,
+		OTPAuthURI: uri,
+		QRPayload:  uri,
+	}, nil
+}
+
+func (s *AuthService) VerifyTwoFactor(userID uuid.UUID, code string) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, domain.NewConflictError("Two-factor authentication is already enabled")
+	}
+	if user.TOTPSecret == nil {
+		return nil, domain.NewValidationError(map[string]string{
+			"code": "Two-factor setup has not been started",
+		})
+	}
+	if _, ok := validateTOTP(*user.TOTPSecret, code, time.Now()); !ok {
+		return nil, domain.NewValidationError(map[string]string{
+			"code": "Invalid authentication code",
+		})
+	}
+
+	user.TOTPEnabled = true
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	return user, nil
 }
 
 func (s *AuthService) Logout(token string) error {
@@ -125,7 +278,14 @@ func (s *AuthService) RefreshToken(refreshToken string) (*domain.LoginResponse,
 
 	hash := hashToken(refreshToken)
 	session, err := s.sessionRepo.GetByTokenHash(hash)
-	if err != nil || !session.IsValid() {
+	if err != nil {
+		return nil, domain.NewUnauthorizedError("Invalid refresh token")
+	}
+	if session.RotatedAt != nil {
+		s.refreshTokenReused(session)
+		return nil, domain.NewUnauthorizedError("Invalid refresh token")
+	}
+	if !session.IsValid() {
 		return nil, domain.NewUnauthorizedError("Invalid refresh token")
 	}
 
@@ -138,9 +298,15 @@ func (s *AuthService) RefreshToken(refreshToken string) (*domain.LoginResponse,
 		return nil, domain.NewUnauthorizedError("Account is not active")
 	}
 
-	if err := s.sessionRepo.Revoke(session.ID); err != nil {
+	// Only one refresh may win: a concurrent one with the same token is a replay
+	rotated, err := s.sessionRepo.Rotate(session.ID)
+	if err != nil {
 		return nil, err
 	}
+	if !rotated {
+		s.refreshTokenReused(session)
+		return nil, domain.NewUnauthorizedError("Invalid refresh token")
+	}
 
 	ip := ""
 	if session.IPAddress != nil {
@@ -151,30 +317,35 @@ func (s *AuthService) RefreshToken(refreshToken string) (*domain.LoginResponse,
 		userAgent = *session.UserAgent
 	}
 
-	return s.generateLoginResponse(user, ip, userAgent)
+	return s.issueTokens(user, &domain.Session{
+		UserID:          user.ID,
+		FamilyID:        session.FamilyID,
+		ParentTokenHash: &session.TokenHash,
+	}, ip, userAgent)
+}
+
+// refreshTokenReused handles a refresh token presented after it was already
+// exchanged. Either the client or an attacker holds a stolen copy, so every
+// session of the user is revoked and both have to log in again.
+func (s *AuthService) refreshTokenReused(session *domain.Session) {
+	log.Printf("[Security] Refresh token reuse detected for user %s (session %s, family %s); revoking all sessions",
+		session.UserID, session.ID, session.FamilyID)
+	if err := s.sessionRepo.RevokeAllForUser(session.UserID); err != nil {
+		log.Printf("[Security] Failed to revoke sessions of user %s after token reuse: %v", session.UserID, err)
+	}
 }
 
 func (s *AuthService) ValidateToken(tokenString string) (*domain.TokenClaims, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, domain.ErrTokenInvalid
-		}
-		return []byte(s.jwtConfig.Secret), nil
-	})
+	claims, err := s.parseClaims(tokenString)
 	if err != nil {
-		return nil, domain.ErrTokenInvalid
+		return nil, err
 	}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok || !token.Valid {
+	// Only access tokens are accepted here (2FA challenges carry a typ claim)
+	if _, ok := claims["typ"]; ok {
 		return nil, domain.ErrTokenInvalid
 	}
 
-	exp, ok := claims["exp"].(float64)
-	if !ok || time.Unix(int64(exp), 0).Before(time.Now()) {
-		return nil, domain.ErrTokenExpired
-	}
-
 	userID, err := getUUIDClaim(claims, "user_id")
 	if err != nil {
 		return nil, err
@@ -190,18 +361,40 @@ func (s *AuthService) ValidateToken(tokenString string) (*domain.TokenClaims, er
 		return nil, err
 	}
 
-	return &domain.TokenClaims{
+	result := &domain.TokenClaims{
 		UserID: userID,
 		Email:  email,
 		Role:   domain.UserRole(role),
-	}, nil
+	}
+	if raw, ok := claims["act"]; ok {
+		act, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, domain.ErrTokenInvalid
+		}
+		actorID, err := getUUIDClaim(act, "user_id")
+		if err != nil {
+			return nil, err
+		}
+		result.ActorID = &actorID
+	}
+	if _, ok := claims["sid"]; ok {
+		sessionID, err := getUUIDClaim(claims, "sid")
+		if err != nil {
+			return nil, err
+		}
+		result.SessionID = &sessionID
+	}
+	return result, nil
 }
 
 func (s *AuthService) GetCurrentUser(userID uuid.UUID) (*domain.User, error) {
 	return s.userRepo.GetByID(userID)
 }
 
-func (s *AuthService) UpdateProfile(userID uuid.UUID, input domain.UpdateProfileInput) (*domain.User, error) {
+// UpdateProfile updates the caller's name and email. Changing the email
+// requires the current password and revokes every session except the
+// caller's own (TokenClaims.SessionID; all of them when it is nil).
+func (s *AuthService) UpdateProfile(userID uuid.UUID, input domain.UpdateProfileInput, currentSession *uuid.UUID) (*domain.User, error) {
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
 		return nil, err
@@ -209,12 +402,63 @@ func (s *AuthService) UpdateProfile(userID uuid.UUID, input domain.UpdateProfile
 	if input.Name != nil {
 		user.Name = *input.Name
 	}
+
+	emailChanged := false
+	if input.Email != nil {
+		email := strings.TrimSpace(*input.Email)
+		if !strings.EqualFold(email, user.Email) {
+			if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+				return nil, domain.NewValidationError(map[string]string{
+					"email": "Invalid email address",
+				})
+			}
+			if !VerifyPassword(input.CurrentPassword, user.PasswordHash) {
+				return nil, domain.NewValidationError(map[string]string{
+					"current_password": "Current password is incorrect",
+				})
+			}
+			existing, _ := s.userRepo.GetByEmail(email)
+			if existing != nil && existing.ID != user.ID {
+				return nil, domain.NewConflictError("Email already registered")
+			}
+			user.Email = email
+			emailChanged = true
+		}
+	}
+
 	if err := s.userRepo.Update(user); err != nil {
 		return nil, err
 	}
+
+	if emailChanged {
+		if err := s.revokeOtherSessions(user.ID, currentSession); err != nil {
+			log.Printf("[Auth] Failed to revoke sessions after email change for %s: %v", user.ID, err)
+		}
+	}
 	return user, nil
 }
 
+// revokeOtherSessions revokes all active sessions of the user except those
+// of the currentSession family.
+func (s *AuthService) revokeOtherSessions(userID uuid.UUID, currentSession *uuid.UUID) error {
+	if currentSession == nil {
+		return s.sessionRepo.RevokeAllForUser(userID)
+	}
+	sessions, err := s.sessionRepo.ListByUser(userID)
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if session.FamilyID == *currentSession {
+			continue
+		}
+		if err := s.sessionRepo.Revoke(session.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *AuthService) ChangePassword(userID uuid.UUID, input domain.ChangePasswordInput) error {
 	if input.NewPassword != input.ConfirmPassword {
 		return domain.NewValidationError(map[string]string{
@@ -238,7 +482,7 @@ func (s *AuthService) ChangePassword(userID uuid.UUID, input domain.ChangePasswo
 		})
 	}
 
-	passwordHash, err := HashPassword(input.NewPassword)
+	passwordHash, err := HashPasswordWithParams(input.NewPassword, s.passwordParams)
 	if err != nil {
 		return err
 	}
@@ -247,6 +491,138 @@ func (s *AuthService) ChangePassword(userID uuid.UUID, input domain.ChangePasswo
 	return s.userRepo.Update(user)
 }
 
+// ListSessions returns the user's active sessions, flagging the one of the
+// currentSession family (TokenClaims.SessionID) as the caller's.
+func (s *AuthService) ListSessions(userID uuid.UUID, currentSession *uuid.UUID) ([]domain.Session, error) {
+	sessions, err := s.sessionRepo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if currentSession != nil {
+		for i := range sessions {
+			sessions[i].Current = sessions[i].FamilyID == *currentSession
+		}
+	}
+	return sessions, nil
+}
+
+func (s *AuthService) RevokeSession(userID, sessionID uuid.UUID) error {
+	session, err := s.sessionRepo.GetByID(sessionID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.NewNotFoundError("Session")
+		}
+		return err
+	}
+	// Other users' sessions are reported as missing rather than forbidden
+	if session.UserID != userID {
+		return domain.NewNotFoundError("Session")
+	}
+	if session.IsRevoked() {
+		return nil
+	}
+	return s.sessionRepo.Revoke(session.ID)
+}
+
+const passwordResetTTL = time.Hour
+
+// ForgotPassword issues a one-time reset token. It never reports whether the
+// email exists, so callers must always answer with the same response.
+func (s *AuthService) ForgotPassword(input domain.ForgotPasswordInput) error {
+	email := strings.TrimSpace(input.Email)
+	if email == "" {
+		return nil
+	}
+
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+	if user.Status != domain.UserStatusActive {
+		return nil
+	}
+
+	token, err := generateRandomToken()
+	if err != nil {
+		return err
+	}
+
+	reset := &domain.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(passwordResetTTL),
+	}
+	if err := s.resetRepo.Create(reset); err != nil {
+		return err
+	}
+
+	// Mailed in the background so the response time does not reveal the user
+	if s.mailer != nil {
+		sendEmail(s.mailer, user.Email, "password_reset", struct {
+			Name       string
+			Token      string
+			TTLMinutes int
+		}{user.Name, token, int(passwordResetTTL.Minutes())})
+	}
+	return nil
+}
+
+func (s *AuthService) ResetPassword(input domain.ResetPasswordInput) error {
+	if input.NewPassword != input.ConfirmPassword {
+		return domain.NewValidationError(map[string]string{
+			"confirm_password": "Passwords do not match",
+		})
+	}
+	if len(input.NewPassword) < 8 {
+		return domain.NewValidationError(map[string]string{
+			"new_password": "Password must be at least 8 characters",
+		})
+	}
+
+	invalidToken := domain.NewValidationError(map[string]string{
+		"token": "Invalid or expired reset token",
+	})
+	if strings.TrimSpace(input.Token) == "" {
+		return invalidToken
+	}
+
+	reset, err := s.resetRepo.GetByTokenHash(hashToken(input.Token))
+	if err != nil || !reset.IsValid() {
+		return invalidToken
+	}
+
+	// Consume first so concurrent requests with the same token can't both succeed
+	consumed, err := s.resetRepo.MarkUsed(reset.ID)
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return invalidToken
+	}
+
+	user, err := s.userRepo.GetByID(reset.UserID)
+	if err != nil {
+		return invalidToken
+	}
+
+	passwordHash, err := HashPasswordWithParams(input.NewPassword, s.passwordParams)
+	if err != nil {
+		return err
+	}
+	user.PasswordHash = passwordHash
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
+
+	if err := s.resetRepo.InvalidateAllForUser(user.ID); err != nil {
+		return err
+	}
+	return s.sessionRepo.RevokeAllForUser(user.ID)
+}
+
 // Admin user management
 func (s *AuthService) ListUsers(filter domain.UserFilter) ([]domain.User, int64, error) {
 	return s.userRepo.List(filter)
@@ -280,11 +656,147 @@ func (s *AuthService) DeleteUser(id uuid.UUID) error {
 	return s.userRepo.Delete(id)
 }
 
+const impersonationTokenTTL = 15 * time.Minute
+
+// Impersonate issues an access token for the target user that records actor
+// in its act claim. It comes without a refresh token, so the session ends
+// when the token expires.
+func (s *AuthService) Impersonate(actor *domain.TokenClaims, targetID uuid.UUID) (*domain.LoginResponse, error) {
+	if actor.ActorID != nil {
+		return nil, domain.NewForbiddenError("Cannot impersonate while impersonating")
+	}
+	if targetID == actor.UserID {
+		return nil, domain.NewValidationError(map[string]string{"id": "Cannot impersonate yourself"})
+	}
+	user, err := s.userRepo.GetByID(targetID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == domain.UserRoleRoot {
+		return nil, domain.NewForbiddenError("Cannot impersonate a ROOT user")
+	}
+	if user.Status != domain.UserStatusActive {
+		return nil, domain.NewValidationError(map[string]string{"id": "Account is not active"})
+	}
+
+	ttl := min(impersonationTokenTTL, s.jwtConfig.AccessTokenDuration)
+	expiresAt := time.Now().Add(ttl)
+	accessToken, err := s.signAccessToken(user, expiresAt, jwt.MapClaims{
+		"act": map[string]string{"user_id": actor.UserID.String(), "email": actor.Email},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[Security] User %s (%s) started impersonating user %s (%s) until %s",
+		actor.UserID, actor.Email, user.ID, user.Email, expiresAt.UTC().Format(time.RFC3339))
+	return &domain.LoginResponse{
+		AccessToken: accessToken,
+		ExpiresAt:   expiresAt,
+		User:        *user,
+	}, nil
+}
+
 // Internal helpers
 
+func (s *AuthService) completeLogin(user *domain.User, ip, userAgent string) (*domain.LoginResponse, error) {
+	now := time.Now()
+	user.LastLoginAt = &now
+	_ = s.userRepo.Update(user)
+
+	return s.generateLoginResponse(user, ip, userAgent)
+}
+
+const (
+	twoFactorChallengeTTL = 5 * time.Minute
+	maxTwoFactorAttempts  = 5 // wrong codes before a challenge is dropped
+)
+
+func (s *AuthService) generateTwoFactorChallenge(user *domain.User) (*domain.TwoFactorChallenge, error) {
+	challengeID := uuid.New()
+	if err := s.userRepo.StartTwoFactorChallenge(user.ID, challengeID); err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(twoFactorChallengeTTL)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": user.ID.String(),
+		"jti":     challengeID.String(),
+		"typ":     "2fa_challenge",
+		"exp":     expiresAt.Unix(),
+		"iat":     time.Now().Unix(),
+	})
+	signed, err := token.SignedString([]byte(s.jwtConfig.Secret))
+	if err != nil {
+		return nil, err
+	}
+	return &domain.TwoFactorChallenge{
+		TwoFactorRequired: true,
+		ChallengeToken:    signed,
+		ExpiresAt:         expiresAt,
+	}, nil
+}
+
+// parseTwoFactorChallenge returns the user and challenge IDs of a challenge
+// token.
+func (s *AuthService) parseTwoFactorChallenge(tokenString string) (uuid.UUID, uuid.UUID, error) {
+	claims, err := s.parseClaims(tokenString)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	if typ, _ := claims["typ"].(string); typ != "2fa_challenge" {
+		return uuid.Nil, uuid.Nil, domain.ErrTokenInvalid
+	}
+	userID, err := getUUIDClaim(claims, "user_id")
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	challengeID, err := getUUIDClaim(claims, "jti")
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return userID, challengeID, nil
+}
+
+func (s *AuthService) parseClaims(tokenString string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, domain.ErrTokenInvalid
+		}
+		return []byte(s.jwtConfig.Secret), nil
+	})
+	if err != nil {
+		return nil, domain.ErrTokenInvalid
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, domain.ErrTokenInvalid
+	}
+
+	exp, ok := claims["exp"].(float64)
+	if !ok || time.Unix(int64(exp), 0).Before(time.Now()) {
+		return nil, domain.ErrTokenExpired
+	}
+	return claims, nil
+}
+
 func (s *AuthService) generateLoginResponse(user *domain.User, ip, userAgent string) (*domain.LoginResponse, error) {
+	return s.issueTokens(user, &domain.Session{UserID: user.ID}, ip, userAgent)
+}
+
+// issueTokens signs an access token and stores session with a new refresh
+// token. session carries the rotation chain when it replaces another one.
+func (s *AuthService) issueTokens(user *domain.User, session *domain.Session, ip, userAgent string) (*domain.LoginResponse, error) {
+	// The access token names the session family, which survives rotation,
+	// so the caller's session can be told apart from the others
+	session.ID = uuid.New()
+	if session.FamilyID == uuid.Nil {
+		session.FamilyID = session.ID
+	}
+
 	expiresAt := time.Now().Add(s.jwtConfig.AccessTokenDuration)
-	accessToken, err := s.generateAccessToken(user, expiresAt)
+	accessToken, err := s.signAccessToken(user, expiresAt, jwt.MapClaims{"sid": session.FamilyID.String()})
 	if err != nil {
 		return nil, err
 	}
@@ -294,11 +806,8 @@ func (s *AuthService) generateLoginResponse(user *domain.User, ip, userAgent str
 		return nil, err
 	}
 
-	session := &domain.Session{
-		UserID:    user.ID,
-		TokenHash: hashToken(refreshToken),
-		ExpiresAt: time.Now().Add(s.jwtConfig.RefreshTokenDuration),
-	}
+	session.TokenHash = hashToken(refreshToken)
+	session.ExpiresAt = time.Now().Add(s.jwtConfig.RefreshTokenDuration)
 	if userAgent != "" {
 		session.UserAgent = &userAgent
 	}
@@ -318,43 +827,19 @@ func (s *AuthService) generateLoginResponse(user *domain.User, ip, userAgent str
 	}, nil
 }
 
-func (s *AuthService) generateAccessToken(user *domain.User, expiresAt time.Time) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+// signAccessToken signs the standard access token claims plus extra.
+func (s *AuthService) signAccessToken(user *domain.User, expiresAt time.Time, extra jwt.MapClaims) (string, error) {
+	claims := jwt.MapClaims{
 		"user_id": user.ID.String(),
 		"email":   user.Email,
 		"role":    string(user.Role),
 		"exp":     expiresAt.Unix(),
 		"iat":     time.Now().Unix(),
-	})
-	return token.SignedString([]byte(s.jwtConfig.Secret))
-}
-
-func HashPassword(password string) (string, error) {
-	salt := make([]byte, 16)
-	if _, err := rand.Read(salt); err != nil {
-		return "", err
 	}
-	hash := argon2.IDKey([]byte(password), salt, 3, 64*1024, 2, 32)
-	b64Salt := base64.StdEncoding.EncodeToString(salt)
-	b64Hash := base64.StdEncoding.EncodeToString(hash)
-	return b64Salt + "$" + b64Hash, nil
-}
-
-func VerifyPassword(password, hash string) bool {
-	parts := strings.SplitN(hash, "$", 2)
-	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
-		return false
-	}
-	salt, err := base64.StdEncoding.DecodeString(parts[0])
-	if err != nil {
-		return false
-	}
-	storedHash, err := base64.StdEncoding.DecodeString(parts[1])
-	if err != nil {
-		return false
+	for k, v := range extra {
+		claims[k] = v
 	}
-	computedHash := argon2.IDKey([]byte(password), salt, 3, 64*1024, 2, 32)
-	return subtle.ConstantTimeCompare(computedHash, storedHash) == 1
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.jwtConfig.Secret))
 }
 
 func generateRandomToken() (string, error) {
@@ -0,0 +1,324 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+	"github.com/willianpsouza/StressTestPlatform/internal/pkg/config"
+)
+
+const testPassword = "correct horse"
+
+// testPasswordConfig keeps Argon2 cheap; the tests only need valid hashes.
+var testPasswordConfig = config.PasswordConfig{Argon2Time: 1, Argon2MemoryKB: 8 * 1024, Argon2Threads: 1}
+
+type authFixture struct {
+	svc      *AuthService
+	users    *fakeUserRepo
+	sessions *fakeSessionRepo
+	resets   *fakeResetRepo
+	mailer   *fakeMailer
+	user     *domain.User
+}
+
+// newAuthFixture returns an AuthService over in-memory repositories with one
+// active user whose password is testPassword.
+func newAuthFixture(t *testing.T) *authFixture {
+	t.Helper()
+	hash, err := HashPasswordWithParams(testPassword, Argon2ParamsFromConfig(testPasswordConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &domain.User{
+		Email:        "user@example.com",
+		Name:         "User",
+		PasswordHash: hash,
+		Role:         domain.UserRoleUser,
+		Status:       domain.UserStatusActive,
+	}
+	f := &authFixture{
+		users:    newFakeUserRepo(user),
+		sessions: newFakeSessionRepo(),
+		resets:   newFakeResetRepo(),
+		mailer:   newFakeMailer(),
+		user:     user,
+	}
+	jwtConfig := config.JWTConfig{
+		Secret:               "test-secret-that-is-long-enough-for-hs256",
+		AccessTokenDuration:  15 * time.Minute,
+		RefreshTokenDuration: 24 * time.Hour,
+	}
+	f.svc = NewAuthService(jwtConfig, testPasswordConfig, f.users, f.sessions, f.resets, nil, f.mailer)
+	return f
+}
+
+func (f *authFixture) login(t *testing.T) *domain.LoginResponse {
+	t.Helper()
+	resp, _, err := f.svc.Login(domain.LoginInput{Email: f.user.Email, Password: testPassword}, "", "")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	return resp
+}
+
+// forgotPassword requests a reset and returns the token from the email.
+func (f *authFixture) forgotPassword(t *testing.T) string {
+	t.Helper()
+	if err := f.svc.ForgotPassword(domain.ForgotPasswordInput{Email: f.user.Email}); err != nil {
+		t.Fatalf("ForgotPassword: %v", err)
+	}
+	select {
+	case msg := <-f.mailer.sent:
+		_, rest, ok := strings.Cut(msg.Body, "minutes:")
+		if !ok || len(strings.Fields(rest)) == 0 {
+			t.Fatalf("no token in reset email:\n%s", msg.Body)
+		}
+		return strings.Fields(rest)[0]
+	case <-time.After(time.Second):
+		t.Fatal("reset email was not sent")
+		return ""
+	}
+}
+
+func resetInput(token, password string) domain.ResetPasswordInput {
+	return domain.ResetPasswordInput{Token: token, NewPassword: password, ConfirmPassword: password}
+}
+
+func TestForgotPasswordStoresOnlyTokenHash(t *testing.T) {
+	f := newAuthFixture(t)
+	token := f.forgotPassword(t)
+
+	reset, err := f.resets.GetByTokenHash(hashToken(token))
+	if err != nil {
+		t.Fatalf("reset for the mailed token not stored: %v", err)
+	}
+	if reset.TokenHash == token {
+		t.Error("reset stores the raw token")
+	}
+	if ttl := time.Until(reset.ExpiresAt); ttl <= 0 || ttl > passwordResetTTL {
+		t.Errorf("reset expires in %v, want within %v", ttl, passwordResetTTL)
+	}
+}
+
+func TestForgotPasswordUnknownEmail(t *testing.T) {
+	f := newAuthFixture(t)
+	if err := f.svc.ForgotPassword(domain.ForgotPasswordInput{Email: "nobody@example.com"}); err != nil {
+		t.Fatalf("ForgotPassword = %v, want nil so the email is not disclosed", err)
+	}
+	if len(f.resets.resets) != 0 {
+		t.Error("reset created for an unknown email")
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	f := newAuthFixture(t)
+	token := f.forgotPassword(t)
+
+	if err := f.svc.ResetPassword(resetInput(token, "new password")); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+	if _, _, err := f.svc.Login(domain.LoginInput{Email: f.user.Email, Password: "new password"}, "", ""); err != nil {
+		t.Errorf("Login with the new password: %v", err)
+	}
+	if _, _, err := f.svc.Login(domain.LoginInput{Email: f.user.Email, Password: testPassword}, "", ""); err == nil {
+		t.Error("Login with the old password succeeded")
+	}
+}
+
+func TestResetPasswordTokenIsSingleUse(t *testing.T) {
+	f := newAuthFixture(t)
+	token := f.forgotPassword(t)
+
+	if err := f.svc.ResetPassword(resetInput(token, "new password")); err != nil {
+		t.Fatalf("first ResetPassword: %v", err)
+	}
+	err := f.svc.ResetPassword(resetInput(token, "another password"))
+	if _, ok := validationDetails(t, err)["token"]; !ok {
+		t.Errorf("second ResetPassword = %v, want a token error", err)
+	}
+}
+
+func TestResetPasswordInvalidatesOtherTokens(t *testing.T) {
+	f := newAuthFixture(t)
+	first := f.forgotPassword(t)
+	second := f.forgotPassword(t)
+
+	if err := f.svc.ResetPassword(resetInput(second, "new password")); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+	err := f.svc.ResetPassword(resetInput(first, "another password"))
+	if _, ok := validationDetails(t, err)["token"]; !ok {
+		t.Errorf("ResetPassword with an older token = %v, want a token error", err)
+	}
+}
+
+func TestResetPasswordExpiredToken(t *testing.T) {
+	f := newAuthFixture(t)
+	token := f.forgotPassword(t)
+
+	reset, _ := f.resets.GetByTokenHash(hashToken(token))
+	reset.ExpiresAt = time.Now().Add(-time.Minute)
+	f.resets.resets[reset.ID] = *reset
+
+	err := f.svc.ResetPassword(resetInput(token, "new password"))
+	if _, ok := validationDetails(t, err)["token"]; !ok {
+		t.Errorf("ResetPassword = %v, want a token error", err)
+	}
+	if reset, _ := f.resets.GetByTokenHash(hashToken(token)); reset.UsedAt != nil {
+		t.Error("expired token was consumed")
+	}
+}
+
+func TestResetPasswordRevokesSessions(t *testing.T) {
+	f := newAuthFixture(t)
+	first := f.login(t)
+	second := f.login(t)
+	token := f.forgotPassword(t)
+
+	if err := f.svc.ResetPassword(resetInput(token, "new password")); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+	if active := f.sessions.active(f.user.ID); len(active) != 0 {
+		t.Errorf("%d sessions still active after reset", len(active))
+	}
+	for _, resp := range []*domain.LoginResponse{first, second} {
+		if _, err := f.svc.RefreshToken(resp.RefreshToken); err == nil {
+			t.Error("refresh token issued before the reset still works")
+		}
+	}
+}
+
+func TestResetPasswordValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		input domain.ResetPasswordInput
+		field string
+	}{
+		{"mismatch", domain.ResetPasswordInput{Token: "t", NewPassword: "new password", ConfirmPassword: "other password"}, "confirm_password"},
+		{"too short", resetInput("t", "short"), "new_password"},
+		{"missing token", resetInput(" ", "new password"), "token"},
+		{"unknown token", resetInput("not-a-token", "new password"), "token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newAuthFixture(t)
+			err := f.svc.ResetPassword(tt.input)
+			if _, ok := validationDetails(t, err)[tt.field]; !ok {
+				t.Errorf("ResetPassword = %v, want a %s error", err, tt.field)
+			}
+		})
+	}
+}
+
+func TestRefreshTokenRotates(t *testing.T) {
+	f := newAuthFixture(t)
+	first := f.login(t)
+
+	second, err := f.svc.RefreshToken(first.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Error("refresh returned the same token")
+	}
+	if _, err := f.svc.RefreshToken(second.RefreshToken); err != nil {
+		t.Errorf("RefreshToken with the rotated token: %v", err)
+	}
+}
+
+func TestRefreshTokenReplayRevokesAllSessions(t *testing.T) {
+	f := newAuthFixture(t)
+	stolen := f.login(t)
+	other := f.login(t)
+
+	rotated, err := f.svc.RefreshToken(stolen.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+
+	if _, err := f.svc.RefreshToken(stolen.RefreshToken); err == nil {
+		t.Fatal("replayed refresh token was accepted")
+	}
+	if active := f.sessions.active(f.user.ID); len(active) != 0 {
+		t.Errorf("%d sessions still active after the replay", len(active))
+	}
+	for name, token := range map[string]string{"rotated": rotated.RefreshToken, "other login": other.RefreshToken} {
+		if _, err := f.svc.RefreshToken(token); err == nil {
+			t.Errorf("%s refresh token still works after the replay", name)
+		}
+	}
+}
+
+func TestListSessionsMarksCurrentFamily(t *testing.T) {
+	f := newAuthFixture(t)
+	first := f.login(t)
+	f.login(t)
+
+	// Rotation keeps the family, so the refreshed session is still current
+	refreshed, err := f.svc.RefreshToken(first.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	claims, err := f.svc.ValidateToken(refreshed.AccessToken)
+	if err != nil || claims.SessionID == nil {
+		t.Fatalf("ValidateToken = %+v, %v; want a session ID", claims, err)
+	}
+
+	sessions, err := f.svc.ListSessions(f.user.ID, claims.SessionID)
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("got %d sessions, want 2", len(sessions))
+	}
+	current := 0
+	for _, s := range sessions {
+		if s.Current {
+			current++
+			if s.TokenHash != hashToken(refreshed.RefreshToken) {
+				t.Error("the other login is flagged as current")
+			}
+		}
+	}
+	if current != 1 {
+		t.Errorf("%d sessions flagged as current, want 1", current)
+	}
+}
+
+func TestRevokeSessionLeavesOtherFamilies(t *testing.T) {
+	f := newAuthFixture(t)
+	revoked := f.login(t)
+	kept := f.login(t)
+
+	session, _ := f.sessions.GetByTokenHash(hashToken(revoked.RefreshToken))
+	if err := f.svc.RevokeSession(f.user.ID, session.ID); err != nil {
+		t.Fatalf("RevokeSession: %v", err)
+	}
+	if _, err := f.svc.RefreshToken(revoked.RefreshToken); err == nil {
+		t.Error("revoked session can still refresh")
+	}
+	if _, err := f.svc.RefreshToken(kept.RefreshToken); err != nil {
+		t.Errorf("other session was affected: %v", err)
+	}
+	if active := f.sessions.active(f.user.ID); len(active) != 1 {
+		t.Errorf("%d sessions active, want 1", len(active))
+	}
+}
+
+func TestRevokeSessionOfAnotherUser(t *testing.T) {
+	f := newAuthFixture(t)
+	resp := f.login(t)
+	session, _ := f.sessions.GetByTokenHash(hashToken(resp.RefreshToken))
+
+	other := &domain.User{Email: "other@example.com", Role: domain.UserRoleUser, Status: domain.UserStatusActive}
+	f.users.Create(other)
+
+	if err := f.svc.RevokeSession(other.ID, session.ID); errorCode(err) != "NOT_FOUND" {
+		t.Errorf("RevokeSession = %v, want NOT_FOUND", err)
+	}
+	if _, err := f.svc.RefreshToken(resp.RefreshToken); err != nil {
+		t.Errorf("session was revoked by another user: %v", err)
+	}
+}
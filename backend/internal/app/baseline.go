@@ -0,0 +1,81 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+// baselineTrendStat is the summary-export stat compared with the baseline;
+// k6 is always asked for it when the test has a baseline.
+const baselineTrendStat = "p(95)"
+
+// compareWithBaseline checks execution against baseline. p95 regresses when
+// it grows by more than p95TolerancePct percent, the error rate when it
+// grows by more than errorRateTolerance percentage points.
+func compareWithBaseline(baseline, execution *domain.TestExecution, p95TolerancePct, errorRateTolerance float64) (bool, *domain.BaselineComparison) {
+	c := &domain.BaselineComparison{
+		BaselineExecutionID: baseline.ID,
+		P95Ms:               summaryP95(execution.SummaryExport),
+		BaselineP95Ms:       summaryP95(baseline.SummaryExport),
+		ErrorRate:           summaryErrorRate(execution.MetricsSummary),
+		BaselineErrorRate:   summaryErrorRate(baseline.MetricsSummary),
+	}
+
+	if c.P95Ms != nil && c.BaselineP95Ms != nil && *c.BaselineP95Ms > 0 {
+		p95, base := *c.P95Ms, *c.BaselineP95Ms
+		if growth := (p95/base - 1) * 100; growth > p95TolerancePct {
+			c.Regressions = append(c.Regressions, fmt.Sprintf("p95 %.2fms vs %.2fms (+%.1f%%, tolerance %g%%)",
+				p95, base, growth, p95TolerancePct))
+		}
+	}
+	if c.ErrorRate != nil && c.BaselineErrorRate != nil {
+		rate, base := *c.ErrorRate, *c.BaselineErrorRate
+		if rate-base > errorRateTolerance {
+			c.Regressions = append(c.Regressions, fmt.Sprintf("error rate %.2f%% vs %.2f%% (tolerance %g points)",
+				rate, base, errorRateTolerance))
+		}
+	}
+	return len(c.Regressions) > 0, c
+}
+
+// summaryP95 reads the p95 of http_req_duration from a k6 summary export.
+func summaryP95(summary domain.JSONMap) *float64 {
+	metrics, _ := summary["metrics"].(map[string]interface{})
+	values, _ := metrics["http_req_duration"].(map[string]interface{})
+	if v, ok := summaryStat(values, baselineTrendStat); ok {
+		return &v
+	}
+	return nil
+}
+
+func summaryErrorRate(summary domain.JSONMap) *float64 {
+	if v, ok := summary["error_rate"].(float64); ok {
+		return &v
+	}
+	return nil
+}
+
+// checkBaseline compares a completed execution with its test's baseline,
+// setting Regressed and BaselineComparison. A baseline that cannot be
+// loaded (e.g. deleted) leaves the execution uncompared.
+func (r *K6Runner) checkBaseline(test *domain.Test, execution *domain.TestExecution, logger *slog.Logger) {
+	if test.BaselineExecutionID == nil || *test.BaselineExecutionID == execution.ID ||
+		execution.Status != domain.TestStatusCompleted {
+		return
+	}
+	baseline, err := r.execRepo.GetByID(*test.BaselineExecutionID)
+	if err != nil {
+		logger.Warn("Failed to load baseline execution", "baseline_id", *test.BaselineExecutionID, "error", err)
+		return
+	}
+
+	regressed, comparison := compareWithBaseline(baseline, execution,
+		r.k6Config.RegressionP95TolerancePct, r.k6Config.RegressionErrorRateTolerance)
+	execution.Regressed = &regressed
+	execution.BaselineComparison = comparison
+	if regressed {
+		logger.Warn("Execution regressed against baseline", "baseline_id", baseline.ID, "regressions", comparison.Regressions)
+	}
+}
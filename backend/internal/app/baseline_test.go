@@ -0,0 +1,109 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+// baselineExecution builds an execution whose summaries hold the given p95
+// and error rate; nil leaves the value out.
+func baselineExecution(p95, errorRate *float64) *domain.TestExecution {
+	e := &domain.TestExecution{ID: uuid.New(), SummaryExport: domain.JSONMap{}, MetricsSummary: domain.JSONMap{}}
+	if p95 != nil {
+		e.SummaryExport["metrics"] = map[string]interface{}{
+			"http_req_duration": map[string]interface{}{baselineTrendStat: *p95},
+		}
+	}
+	if errorRate != nil {
+		e.MetricsSummary["error_rate"] = *errorRate
+	}
+	return e
+}
+
+func TestCompareWithBaseline(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name            string
+		baseline        *domain.TestExecution
+		execution       *domain.TestExecution
+		wantRegressed   bool
+		wantRegressions []string // prefixes, in order
+	}{
+		{
+			name:      "within tolerance",
+			baseline:  baselineExecution(f(100), f(1)),
+			execution: baselineExecution(f(110), f(2)),
+		},
+		{
+			name:      "exactly at tolerance",
+			baseline:  baselineExecution(f(100), f(1)),
+			execution: baselineExecution(f(120), f(3)),
+		},
+		{
+			name:      "faster and fewer errors",
+			baseline:  baselineExecution(f(100), f(5)),
+			execution: baselineExecution(f(50), f(0)),
+		},
+		{
+			name:            "p95 regression",
+			baseline:        baselineExecution(f(100), f(1)),
+			execution:       baselineExecution(f(121), f(1)),
+			wantRegressed:   true,
+			wantRegressions: []string{"p95 121.00ms vs 100.00ms (+21.0%"},
+		},
+		{
+			name:            "error rate regression",
+			baseline:        baselineExecution(f(100), f(1)),
+			execution:       baselineExecution(f(100), f(3.5)),
+			wantRegressed:   true,
+			wantRegressions: []string{"error rate 3.50% vs 1.00%"},
+		},
+		{
+			name:            "both regress",
+			baseline:        baselineExecution(f(100), f(0)),
+			execution:       baselineExecution(f(300), f(10)),
+			wantRegressed:   true,
+			wantRegressions: []string{"p95 ", "error rate "},
+		},
+		{
+			name:      "zero baseline p95 is not compared",
+			baseline:  baselineExecution(f(0), nil),
+			execution: baselineExecution(f(500), nil),
+		},
+		{
+			name:      "missing baseline values",
+			baseline:  baselineExecution(nil, nil),
+			execution: baselineExecution(f(500), f(50)),
+		},
+		{
+			name:      "missing execution values",
+			baseline:  baselineExecution(f(100), f(1)),
+			execution: baselineExecution(nil, nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			regressed, c := compareWithBaseline(tt.baseline, tt.execution, 20, 2)
+			if regressed != tt.wantRegressed {
+				t.Errorf("regressed = %v, want %v (%v)", regressed, tt.wantRegressed, c.Regressions)
+			}
+			if c.BaselineExecutionID != tt.baseline.ID {
+				t.Errorf("BaselineExecutionID = %s, want %s", c.BaselineExecutionID, tt.baseline.ID)
+			}
+			if len(c.Regressions) != len(tt.wantRegressions) {
+				t.Fatalf("regressions = %q, want %d", c.Regressions, len(tt.wantRegressions))
+			}
+			for i, prefix := range tt.wantRegressions {
+				if !strings.HasPrefix(c.Regressions[i], prefix) {
+					t.Errorf("regression %d = %q, want prefix %q", i, c.Regressions[i], prefix)
+				}
+			}
+		})
+	}
+}
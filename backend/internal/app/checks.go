@@ -0,0 +1,53 @@
+package app
+
+import (
+	"math"
+	"sort"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+type checkKey struct {
+	group string
+	name  string
+}
+
+// checkTally counts passes and fails of the k6 checks seen in the CSV, where
+// each "checks" row carries the check name and 1 (pass) or 0 (fail).
+type checkTally map[checkKey]*domain.CheckResult
+
+func (t checkTally) add(group, name string, passed bool) {
+	key := checkKey{group: group, name: name}
+	res, ok := t[key]
+	if !ok {
+		res = &domain.CheckResult{Name: name, Group: group}
+		t[key] = res
+	}
+	if passed {
+		res.Passes++
+	} else {
+		res.Fails++
+	}
+}
+
+// results returns the checks ordered by group and name, or nil when the
+// script has none.
+func (t checkTally) results() domain.CheckResults {
+	if len(t) == 0 {
+		return nil
+	}
+	results := make(domain.CheckResults, 0, len(t))
+	for _, res := range t {
+		if total := res.Passes + res.Fails; total > 0 {
+			res.Rate = math.Round(float64(res.Passes)/float64(total)*10000) / 10000
+		}
+		results = append(results, *res)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Group != results[j].Group {
+			return results[i].Group < results[j].Group
+		}
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
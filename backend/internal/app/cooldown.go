@@ -0,0 +1,51 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+// validateCooldown accepts an empty string (no cooldown) or a non-negative Go duration.
+func validateCooldown(cooldown string) error {
+	if cooldown == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(cooldown)
+	if err != nil || d < 0 {
+		return domain.NewValidationError(map[string]string{
+			"cooldown": "Must be a non-negative duration (e.g. 30s, 5m)",
+		})
+	}
+	return nil
+}
+
+// cooldownUntil returns when the test becomes eligible to run again, or nil if
+// it has no cooldown or the cooldown since its last completed run has elapsed.
+func cooldownUntil(execRepo domain.ExecutionRepository, test *domain.Test) (*time.Time, error) {
+	if test.Cooldown == "" {
+		return nil, nil
+	}
+	cooldown, err := time.ParseDuration(test.Cooldown)
+	if err != nil || cooldown <= 0 {
+		return nil, nil
+	}
+
+	lastCompleted, err := execRepo.GetLastCompletedAt(test.ID)
+	if err != nil || lastCompleted == nil {
+		return nil, err
+	}
+
+	eligibleAt := lastCompleted.Add(cooldown)
+	if time.Now().Before(eligibleAt) {
+		return &eligibleAt, nil
+	}
+	return nil, nil
+}
+
+func newCooldownError(eligibleAt time.Time) error {
+	at := eligibleAt.UTC().Format(time.RFC3339)
+	return domain.NewConflictError(fmt.Sprintf("Test is in cooldown, next eligible at %s", at)).
+		WithDetails(map[string]string{"next_eligible_at": at})
+}
@@ -21,6 +21,11 @@ func (s *DomainService) Create(userID uuid.UUID, input domain.CreateDomainInput)
 		})
 	}
 
+	tags, err := normalizeTags(input.Tags)
+	if err != nil {
+		return nil, err
+	}
+
 	existing, _ := s.domainRepo.GetByUserAndName(userID, input.Name)
 	if existing != nil {
 		return nil, domain.NewConflictError("Domain with this name already exists")
@@ -30,6 +35,7 @@ func (s *DomainService) Create(userID uuid.UUID, input domain.CreateDomainInput)
 		UserID:      userID,
 		Name:        input.Name,
 		Description: input.Description,
+		Tags:        tags,
 	}
 
 	if err := s.domainRepo.Create(d); err != nil {
@@ -70,6 +76,21 @@ func (s *DomainService) Update(id uuid.UUID, userID uuid.UUID, isRoot bool, inpu
 	if input.Description != nil {
 		d.Description = input.Description
 	}
+	if input.Tags != nil {
+		tags, err := normalizeTags(input.Tags)
+		if err != nil {
+			return nil, err
+		}
+		d.Tags = tags
+	}
+	if input.MaxVUs != nil || input.MaxDuration != nil || input.MaxConcurrent != nil {
+		if !isRoot {
+			return nil, domain.NewForbiddenError("Only ROOT can change domain limits")
+		}
+		if err := applyDomainLimits(d, input); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := s.domainRepo.Update(d); err != nil {
 		return nil, err
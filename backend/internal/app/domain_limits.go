@@ -0,0 +1,91 @@
+package app
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+	"github.com/willianpsouza/StressTestPlatform/internal/pkg/config"
+)
+
+// k6Limits are the caps for runs of a test: the overrides ROOT granted its
+// domain where set, the global K6 config otherwise.
+type k6Limits struct {
+	MaxVUs        int
+	MaxDuration   time.Duration
+	MaxConcurrent int // per user
+}
+
+func domainK6Limits(cfg config.K6Config, d *domain.Domain) k6Limits {
+	limits := k6Limits{
+		MaxVUs:        cfg.MaxVUs,
+		MaxDuration:   cfg.MaxDuration,
+		MaxConcurrent: cfg.MaxConcurrent,
+	}
+	if d == nil {
+		return limits
+	}
+	if d.MaxVUs != nil {
+		limits.MaxVUs = *d.MaxVUs
+	}
+	if d.MaxDuration != nil {
+		if dur, err := time.ParseDuration(*d.MaxDuration); err == nil {
+			limits.MaxDuration = dur
+		}
+	}
+	if d.MaxConcurrent != nil {
+		limits.MaxConcurrent = *d.MaxConcurrent
+	}
+	return limits
+}
+
+// loadK6Limits returns the limits for tests in domainID. When the domain
+// cannot be loaded the global limits are returned along with the error.
+func loadK6Limits(repo domain.DomainRepository, cfg config.K6Config, domainID uuid.UUID) (k6Limits, error) {
+	d, err := repo.GetByID(domainID)
+	if err != nil {
+		return domainK6Limits(cfg, nil), err
+	}
+	return domainK6Limits(cfg, d), nil
+}
+
+// applyDomainLimits validates the limit overrides in input and sets them on d.
+// 0 and "" clear an override.
+func applyDomainLimits(d *domain.Domain, input domain.UpdateDomainInput) error {
+	errs := map[string]string{}
+	if input.MaxVUs != nil {
+		switch {
+		case *input.MaxVUs < 0:
+			errs["max_vus"] = "Must be positive, or 0 to use the global limit"
+		case *input.MaxVUs == 0:
+			d.MaxVUs = nil
+		default:
+			d.MaxVUs = input.MaxVUs
+		}
+	}
+	if input.MaxDuration != nil {
+		if *input.MaxDuration == "" {
+			d.MaxDuration = nil
+		} else if dur, err := time.ParseDuration(*input.MaxDuration); err != nil || dur <= 0 {
+			errs["max_duration"] = "Must be a positive duration (e.g. 10m), or empty to use the global limit"
+		} else {
+			normalized := dur.String()
+			d.MaxDuration = &normalized
+		}
+	}
+	if input.MaxConcurrent != nil {
+		switch {
+		case *input.MaxConcurrent < 0:
+			errs["max_concurrent"] = "Must be positive, or 0 to use the global limit"
+		case *input.MaxConcurrent == 0:
+			d.MaxConcurrent = nil
+		default:
+			d.MaxConcurrent = input.MaxConcurrent
+		}
+	}
+	if len(errs) > 0 {
+		return domain.NewValidationError(errs)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package app
+
+import (
+	"bytes"
+	"log"
+	"text/template"
+	"time"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+	"github.com/willianpsouza/StressTestPlatform/internal/pkg/config"
+)
+
+// emailTemplates holds a "<name>.subject" and "<name>.body" pair per email.
+var emailTemplates = template.Must(template.New("email").Parse(`
+{{define "execution_finished.subject"}}[StressTest] {{.TestName}}: {{.Status}}{{if .Regressions}} (regressed){{end}}{{end}}
+{{define "execution_finished.body"}}The execution of "{{.TestName}}" finished with status {{.Status}}.
+
+VUs:       {{.VUs}}
+Duration:  {{.Duration}}
+Started:   {{.StartedAt}}
+Completed: {{.CompletedAt}}
+{{if .ErrorMessage}}Error:     {{.ErrorMessage}}
+{{end}}{{if .Regressions}}
+Regressed against the baseline:{{range .Regressions}}
+  {{.}}{{end}}
+{{end}}{{if .URL}}
+{{.URL}}
+{{end}}{{end}}
+
+{{define "threshold_failed.subject"}}[StressTest] {{.TestName}}: thresholds failed{{end}}
+{{define "threshold_failed.body"}}The execution of "{{.TestName}}" finished with status {{.Status}}, but breached its thresholds:
+{{range .FailedThresholds}}
+  {{.}}{{end}}
+
+VUs:       {{.VUs}}
+Duration:  {{.Duration}}
+Started:   {{.StartedAt}}
+Completed: {{.CompletedAt}}
+{{if .Regressions}}
+Regressed against the baseline:{{range .Regressions}}
+  {{.}}{{end}}
+{{end}}{{if .URL}}
+{{.URL}}
+{{end}}{{end}}
+
+{{define "password_reset.subject"}}[StressTest] Password reset{{end}}
+{{define "password_reset.body"}}Hello {{.Name}},
+
+A password reset was requested for your account. Use this token within {{.TTLMinutes}} minutes:
+
+{{.Token}}
+
+If you did not request it, ignore this email.
+{{end}}
+`))
+
+// renderEmail executes the named subject/body template pair.
+func renderEmail(name string, data any) (subject, body string, err error) {
+	var s, b bytes.Buffer
+	if err := emailTemplates.ExecuteTemplate(&s, name+".subject", data); err != nil {
+		return "", "", err
+	}
+	if err := emailTemplates.ExecuteTemplate(&b, name+".body", data); err != nil {
+		return "", "", err
+	}
+	return s.String(), b.String(), nil
+}
+
+// sendEmail renders and sends the email in the background, logging failures.
+func sendEmail(mailer domain.Mailer, to, name string, data any) {
+	subject, body, err := renderEmail(name, data)
+	if err != nil {
+		log.Printf("[Email] Failed to render %s: %v", name, err)
+		return
+	}
+	go func() {
+		if err := mailer.Send(domain.EmailMessage{To: []string{to}, Subject: subject, Body: body}); err != nil {
+			log.Printf("[Email] Failed to send %s to %s: %v", name, to, err)
+		}
+	}()
+}
+
+type executionEmail struct {
+	TestName         string
+	Status           domain.TestStatus
+	VUs              int
+	Duration         string
+	StartedAt        string
+	CompletedAt      string
+	ErrorMessage     string
+	FailedThresholds []string
+	Regressions      []string // against the test's baseline
+	URL              string
+}
+
+// EmailNotifier mails the owner of an execution when it finishes, for every
+// run or only failed ones depending on SMTP_NOTIFY_ON.
+type EmailNotifier struct {
+	mailer    domain.Mailer
+	userRepo  domain.UserRepository
+	notifyOn  string
+	publicURL string
+}
+
+func NewEmailNotifier(mailer domain.Mailer, userRepo domain.UserRepository, notifyOn, publicURL string) *EmailNotifier {
+	return &EmailNotifier{
+		mailer:    mailer,
+		userRepo:  userRepo,
+		notifyOn:  notifyOn,
+		publicURL: publicURL,
+	}
+}
+
+func (n *EmailNotifier) Notify(test *domain.Test, execution *domain.TestExecution) {
+	thresholdsFailed := execution.ThresholdsPassed != nil && !*execution.ThresholdsPassed
+	regressed := execution.Regressed != nil && *execution.Regressed
+	failed := thresholdsFailed || regressed ||
+		execution.Status == domain.TestStatusFailed || execution.Status == domain.TestStatusTimeout
+
+	switch n.notifyOn {
+	case config.EmailNotifyAll:
+	case config.EmailNotifyFailures:
+		if !failed {
+			return
+		}
+	default:
+		return
+	}
+
+	data := executionEmail{
+		TestName:    test.Name,
+		Status:      execution.Status,
+		VUs:         execution.VUs,
+		Duration:    execution.Duration,
+		StartedAt:   formatEmailTime(execution.StartedAt),
+		CompletedAt: formatEmailTime(execution.CompletedAt),
+	}
+	if execution.EffectiveVUs != nil {
+		data.VUs = *execution.EffectiveVUs
+	}
+	if execution.EffectiveDuration != nil {
+		data.Duration = *execution.EffectiveDuration
+	}
+	if execution.ErrorMessage != nil {
+		data.ErrorMessage = *execution.ErrorMessage
+	}
+	if regressed && execution.BaselineComparison != nil {
+		data.Regressions = execution.BaselineComparison.Regressions
+	}
+	if n.publicURL != "" {
+		data.URL = n.publicURL + "/executions/" + execution.ID.String()
+	}
+
+	name := "execution_finished"
+	if thresholdsFailed {
+		name = "threshold_failed"
+		for _, result := range execution.ThresholdResults {
+			if !result.Passed {
+				data.FailedThresholds = append(data.FailedThresholds, result.Metric+": "+result.Expression)
+			}
+		}
+	}
+
+	go func() {
+		user, err := n.userRepo.GetByID(execution.UserID)
+		if err != nil {
+			log.Printf("[Email] Failed to load owner of execution %s: %v", execution.ID, err)
+			return
+		}
+		sendEmail(n.mailer, user.Email, name, data)
+	}()
+}
+
+func formatEmailTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC1123)
+}
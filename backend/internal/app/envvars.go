@@ -0,0 +1,74 @@
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+const (
+	maxEnvVars       = 50
+	maxEnvKeyLen     = 128
+	maxEnvValueLen   = 4096
+	maxEnvTotalBytes = 32 * 1024
+)
+
+var envKeyPattern = regexp.MustCompile(`^[A-Z_][A-Z0-9_]*$`)
+
+// reservedEnvKeys are set by the platform itself.
+var reservedEnvKeys = map[string]bool{
+	"TARGETS": true,
+}
+
+func validateEnvVars(env domain.EnvVars) error {
+	if len(env) == 0 {
+		return nil
+	}
+	if len(env) > maxEnvVars {
+		return domain.NewValidationError(map[string]string{
+			"env": fmt.Sprintf("At most %d environment variables are allowed", maxEnvVars),
+		})
+	}
+
+	errs := map[string]string{}
+	total := 0
+	for key, value := range env {
+		field := "env." + key
+		switch {
+		case !envKeyPattern.MatchString(key):
+			errs[field] = "Key must match [A-Z_][A-Z0-9_]*"
+		case len(key) > maxEnvKeyLen:
+			errs[field] = fmt.Sprintf("Key must be at most %d characters", maxEnvKeyLen)
+		case reservedEnvKeys[key]:
+			errs[field] = "Key is reserved"
+		case len(value) > maxEnvValueLen:
+			errs[field] = fmt.Sprintf("Value must be at most %d bytes", maxEnvValueLen)
+		}
+		total += len(key) + len(value)
+	}
+	if total > maxEnvTotalBytes {
+		errs["env"] = fmt.Sprintf("Environment must be at most %d bytes in total", maxEnvTotalBytes)
+	}
+
+	if len(errs) > 0 {
+		return domain.NewValidationError(errs)
+	}
+	return nil
+}
+
+// envArgs renders env as repeated --env flags, in key order for stable argv.
+func envArgs(env domain.EnvVars) []string {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	args := make([]string, 0, len(keys)*2)
+	for _, k := range keys {
+		args = append(args, "--env", k+"="+env[k])
+	}
+	return args
+}
@@ -0,0 +1,127 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+const (
+	maxErrorSamples          = 20   // per execution
+	maxErrorSampleBodyBytes  = 4096 // per sample
+	errorSampleMarker        = "STP_ERROR_SAMPLE "
+	maxErrorSampleMethodSize = 16
+)
+
+// errorCaptureWrapper re-exports the test script and wraps the k6/http
+// request functions so the first failing responses of each VU are written to
+// stderr as marked JSON lines. Scripts that use named imports from k6/http
+// (import { get } from 'k6/http') bypass the wrapper.
+const errorCaptureWrapper = `import http from 'k6/http';
+import * as script from %[1]s;
+export * from %[1]s;
+
+const successCodes = %[2]s;
+const maxSamples = %[3]d;
+const maxBody = %[4]d;
+let captured = 0;
+
+function capture(res) {
+  if (captured >= maxSamples || !res || successCodes.includes(res.status)) return;
+  captured++;
+  const body = typeof res.body === 'string' ? res.body : '';
+  console.error(%[5]s + JSON.stringify({
+    method: res.request ? res.request.method : '',
+    url: res.url,
+    status: res.status,
+    body: body.slice(0, maxBody),
+    truncated: body.length > maxBody,
+  }));
+}
+
+for (const name of ['get', 'post', 'put', 'patch', 'del', 'head', 'options', 'request']) {
+  const fn = http[name];
+  if (typeof fn === 'function') {
+    http[name] = function () {
+      const res = fn.apply(http, arguments);
+      capture(res);
+      return res;
+    };
+  }
+}
+
+export default function (data) {
+  if (typeof script.default === 'function') return script.default(data);
+}
+`
+
+// writeErrorCaptureWrapper writes the wrapper for scriptPath to path. Failures
+// are judged against the test's success_status_codes.
+func writeErrorCaptureWrapper(path, scriptPath string, successCodes []int) error {
+	abs, err := filepath.Abs(scriptPath)
+	if err != nil {
+		return err
+	}
+	if len(successCodes) == 0 {
+		successCodes = DefaultSuccessStatusCodes
+	}
+
+	// JSON string and array literals are valid JavaScript
+	scriptURL, _ := json.Marshal((&url.URL{Scheme: "file", Path: abs}).String())
+	codes, _ := json.Marshal(successCodes)
+	marker, _ := json.Marshal(errorSampleMarker)
+
+	src := fmt.Sprintf(errorCaptureWrapper, scriptURL, codes, maxErrorSamples, maxErrorSampleBodyBytes, marker)
+	return os.WriteFile(path, []byte(src), 0o600)
+}
+
+// parseErrorSamples extracts the samples logged by the wrapper from k6's
+// stderr, keeping at most maxErrorSamples across all VUs and attempts. k6
+// prints console output as logfmt (msg="...") unless the log format is raw.
+func parseErrorSamples(stderr string) []domain.ErrorSample {
+	var samples []domain.ErrorSample
+	for _, line := range strings.Split(stderr, "\n") {
+		if len(samples) >= maxErrorSamples {
+			break
+		}
+		idx := strings.Index(line, errorSampleMarker)
+		if idx < 0 {
+			continue
+		}
+
+		payload := line[idx+len(errorSampleMarker):]
+		if msgIdx := strings.Index(line, `msg="`); msgIdx >= 0 && msgIdx < idx {
+			quoted, err := strconv.QuotedPrefix(line[msgIdx+len("msg="):])
+			if err != nil {
+				continue
+			}
+			msg, err := strconv.Unquote(quoted)
+			if err != nil {
+				continue
+			}
+			payload = strings.TrimPrefix(msg, errorSampleMarker)
+		}
+
+		var sample domain.ErrorSample
+		if err := json.Unmarshal([]byte(payload), &sample); err != nil {
+			continue
+		}
+		// PostgreSQL text rejects NUL and invalid UTF-8 left by the byte cut
+		sample.Body = strings.ReplaceAll(sample.Body, "\x00", "")
+		if len(sample.Body) > maxErrorSampleBodyBytes {
+			sample.Body = strings.ToValidUTF8(sample.Body[:maxErrorSampleBodyBytes], "")
+			sample.Truncated = true
+		}
+		if len(sample.Method) > maxErrorSampleMethodSize {
+			sample.Method = sample.Method[:maxErrorSampleMethodSize]
+		}
+		samples = append(samples, sample)
+	}
+	return samples
+}
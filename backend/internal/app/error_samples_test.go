@@ -0,0 +1,99 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+func TestParseErrorSamples(t *testing.T) {
+	raw := errorSampleMarker + `{"method":"GET","url":"http://api/x","status":500,"body":"boom","truncated":false}`
+	logfmt := `time="2026-01-01T00:00:00Z" level=error msg=` + strconv.Quote(raw) + ` source=console`
+	long := strings.Repeat("a", maxErrorSampleBodyBytes+10)
+	// a two-byte rune straddling the cut is dropped rather than split
+	straddle := strings.Repeat("a", maxErrorSampleBodyBytes-1) + "é"
+
+	manySamples := make([]string, maxErrorSamples+5)
+	for i := range manySamples {
+		manySamples[i] = fmt.Sprintf(`%s{"status":%d}`, errorSampleMarker, 500+i)
+	}
+
+	tests := []struct {
+		name   string
+		stderr string
+		want   []domain.ErrorSample
+		count  int
+	}{
+		{
+			name:   "raw line",
+			stderr: raw,
+			want:   []domain.ErrorSample{{Method: "GET", URL: "http://api/x", Status: 500, Body: "boom"}},
+		},
+		{
+			name:   "logfmt line",
+			stderr: "running\n" + logfmt + "\ndone",
+			want:   []domain.ErrorSample{{Method: "GET", URL: "http://api/x", Status: 500, Body: "boom"}},
+		},
+		{
+			name:   "no samples",
+			stderr: "level=error msg=\"some other error\"\n",
+		},
+		{
+			name:   "invalid json skipped",
+			stderr: errorSampleMarker + "{not json\n" + raw,
+			want:   []domain.ErrorSample{{Method: "GET", URL: "http://api/x", Status: 500, Body: "boom"}},
+		},
+		{
+			name:   "broken logfmt quoting skipped",
+			stderr: `level=error msg="` + errorSampleMarker + `{"status":500}`,
+		},
+		{
+			name:   "nul bytes removed",
+			stderr: errorSampleMarker + `{"status":502,"body":"a\u0000b"}`,
+			want:   []domain.ErrorSample{{Status: 502, Body: "ab"}},
+		},
+		{
+			name:   "oversized body truncated",
+			stderr: errorSampleMarker + `{"status":500,"body":"` + long + `"}`,
+			want:   []domain.ErrorSample{{Status: 500, Body: long[:maxErrorSampleBodyBytes], Truncated: true}},
+		},
+		{
+			name:   "truncation keeps utf-8 valid",
+			stderr: errorSampleMarker + `{"status":500,"body":"` + straddle + `"}`,
+			want:   []domain.ErrorSample{{Status: 500, Body: straddle[:maxErrorSampleBodyBytes-1], Truncated: true}},
+		},
+		{
+			name:   "method capped",
+			stderr: errorSampleMarker + `{"method":"` + strings.Repeat("X", 40) + `","status":400}`,
+			want:   []domain.ErrorSample{{Method: strings.Repeat("X", maxErrorSampleMethodSize), Status: 400}},
+		},
+		{
+			name:   "at most maxErrorSamples",
+			stderr: strings.Join(manySamples, "\n"),
+			count:  maxErrorSamples,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseErrorSamples(tt.stderr)
+			if tt.count > 0 {
+				if len(got) != tt.count {
+					t.Fatalf("got %d samples, want %d", len(got), tt.count)
+				}
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d samples, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("sample %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+// validationDetails returns the field errors of a validation error, failing
+// the test for any other error.
+func validationDetails(t *testing.T, err error) map[string]string {
+	t.Helper()
+	var appErr *domain.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "VALIDATION_ERROR" {
+		t.Fatalf("error = %v, want a validation error", err)
+	}
+	return appErr.Details
+}
+
+// errorCode returns the code of an AppError, or "" for any other error.
+func errorCode(err error) string {
+	var appErr *domain.AppError
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+	return ""
+}
@@ -1,16 +1,33 @@
 package app
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
 	"github.com/google/uuid"
 
 	"github.com/willianpsouza/StressTestPlatform/internal/domain"
 )
 
+const (
+	maxExecutionNotesLen  = 2000
+	maxTimeseriesInterval = 3600
+)
+
 type ExecutionService struct {
-	execRepo   domain.ExecutionRepository
-	testRepo   domain.TestRepository
-	metricRepo domain.MetricRepository
-	runner     *K6Runner
+	execRepo       domain.ExecutionRepository
+	testRepo       domain.TestRepository
+	metricRepo     domain.MetricRepository
+	runner         *K6Runner
+	starts         *StartLimiter
+	idempotency    domain.IdempotencyStore
+	idempotencyTTL time.Duration
 }
 
 func NewExecutionService(
@@ -18,12 +35,18 @@ func NewExecutionService(
 	testRepo domain.TestRepository,
 	metricRepo domain.MetricRepository,
 	runner *K6Runner,
+	starts *StartLimiter,
+	idempotency domain.IdempotencyStore,
+	idempotencyTTL time.Duration,
 ) *ExecutionService {
 	return &ExecutionService{
-		execRepo:   execRepo,
-		testRepo:   testRepo,
-		metricRepo: metricRepo,
-		runner:     runner,
+		execRepo:       execRepo,
+		testRepo:       testRepo,
+		metricRepo:     metricRepo,
+		runner:         runner,
+		starts:         starts,
+		idempotency:    idempotency,
+		idempotencyTTL: idempotencyTTL,
 	}
 }
 
@@ -46,12 +69,75 @@ func (s *ExecutionService) Create(userID uuid.UUID, isRoot bool, input domain.Cr
 		duration = test.DefaultDuration
 	}
 
+	stages := input.Stages
+	if len(stages) == 0 && input.VUs <= 0 && input.Duration == "" {
+		stages = test.DefaultStages
+	}
+	if len(stages) > 0 {
+		if err := s.runner.ValidateStages(test, stages); err != nil {
+			return nil, err
+		}
+		// Report the ramp's peak and total length as the run's vus/duration
+		var total time.Duration
+		vus = 0
+		for _, stage := range stages {
+			d, _ := time.ParseDuration(stage.Duration)
+			total += d
+			vus = max(vus, stage.Target)
+		}
+		duration = total.String()
+	}
+
+	targets, err := normalizeTargets(input.Targets)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateEnvVars(input.Env); err != nil {
+		return nil, err
+	}
+	if input.MaxRetries != nil || input.RetryBackoff != nil {
+		maxRetries, backoff := test.MaxRetries, test.RetryBackoff
+		if input.MaxRetries != nil {
+			maxRetries = *input.MaxRetries
+		}
+		if input.RetryBackoff != nil {
+			backoff = *input.RetryBackoff
+		}
+		if err := validateRetryPolicy(maxRetries, backoff); err != nil {
+			return nil, err
+		}
+	}
+
+	if !input.IgnoreCooldown {
+		eligibleAt, err := cooldownUntil(s.execRepo, test)
+		if err != nil {
+			return nil, err
+		}
+		if eligibleAt != nil {
+			return nil, newCooldownError(*eligibleAt)
+		}
+	}
+
+	// Refuse a run the limits would reject before persisting it
+	if err := s.runner.CheckCapacity(userID, test); err != nil {
+		return nil, err
+	}
+	if err := s.starts.Take(userID); err != nil {
+		return nil, err
+	}
+
 	exec := &domain.TestExecution{
-		TestID:   input.TestID,
-		UserID:   userID,
-		VUs:      vus,
-		Duration: duration,
-		Status:   domain.TestStatusPending,
+		TestID:        input.TestID,
+		UserID:        userID,
+		VUs:           vus,
+		Duration:      duration,
+		Targets:       targets,
+		Env:           input.Env,
+		Stages:        stages,
+		Status:        domain.TestStatusPending,
+		MaxRetries:    input.MaxRetries,
+		RetryBackoff:  input.RetryBackoff,
+		CaptureErrors: input.CaptureErrors,
 	}
 
 	if err := s.execRepo.Create(exec); err != nil {
@@ -60,11 +146,26 @@ func (s *ExecutionService) Create(userID uuid.UUID, isRoot bool, input domain.Cr
 
 	// Start execution
 	if err := s.runner.Run(exec); err != nil {
+		if domain.IsTooManyRequests(err) {
+			// Another run took the slot since CheckCapacity: remove the row
+			// and give the start back, as if the request had been refused
+			// up front. Subscribers that saw it PENDING get a CANCELLED event.
+			if err := s.execRepo.Discard(exec.ID); err != nil {
+				log.Printf("[Execution] Failed to discard refused execution %s: %v", exec.ID, err)
+			}
+			s.starts.Refund(userID)
+			exec.Status = domain.TestStatusCancelled
+			s.runner.publish(exec)
+			return nil, err
+		}
 		// Mark as failed if we can't start
 		exec.Status = domain.TestStatusFailed
 		errMsg := err.Error()
 		exec.ErrorMessage = &errMsg
-		s.execRepo.Update(exec)
+		if err := s.execRepo.Update(exec); err != nil {
+			log.Printf("[Execution] Failed to mark execution %s as failed: %v", exec.ID, err)
+		}
+		s.runner.publish(exec)
 		return exec, nil
 	}
 
@@ -82,6 +183,45 @@ func (s *ExecutionService) GetByID(id uuid.UUID, userID uuid.UUID, isRoot bool)
 	return exec, nil
 }
 
+// SubscribeLogs checks access and attaches to the live k6 output. The
+// subscription is nil when the execution is not streaming; callers then fall
+// back to the stored stdout/stderr of the returned execution.
+func (s *ExecutionService) SubscribeLogs(id uuid.UUID, userID uuid.UUID, isRoot bool) (*domain.TestExecution, *LogSubscription, error) {
+	exec, err := s.GetByID(id, userID, isRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+	return exec, s.runner.SubscribeLogs(id), nil
+}
+
+// SubscribeEvents streams status changes of the user's executions, or of all
+// executions for ROOT, until ctx is done.
+func (s *ExecutionService) SubscribeEvents(ctx context.Context, userID uuid.UUID, isRoot bool) (<-chan domain.ExecutionEvent, error) {
+	events, err := s.runner.SubscribeEvents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if isRoot {
+		return events, nil
+	}
+
+	own := make(chan domain.ExecutionEvent)
+	go func() {
+		defer close(own)
+		for event := range events {
+			if event.UserID != userID {
+				continue
+			}
+			select {
+			case own <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return own, nil
+}
+
 func (s *ExecutionService) Cancel(id uuid.UUID, userID uuid.UUID, isRoot bool) error {
 	exec, err := s.execRepo.GetByID(id)
 	if err != nil {
@@ -115,12 +255,21 @@ func (s *ExecutionService) Delete(id uuid.UUID, userID uuid.UUID, isRoot bool) e
 		})
 	}
 
-	// Delete associated metrics first
-	s.metricRepo.DeleteByExecution(exec.ID)
-
+	// Soft delete: metrics stay until the purger removes the execution
 	return s.execRepo.Delete(id)
 }
 
+// Restore brings back a soft-deleted execution that has not been purged yet.
+func (s *ExecutionService) Restore(id uuid.UUID) (*domain.TestExecution, error) {
+	if err := s.execRepo.Restore(id); err != nil {
+		if errors.Is(err, domain.ErrExecutionNotFound) {
+			return nil, domain.NewNotFoundError("Deleted execution")
+		}
+		return nil, err
+	}
+	return s.execRepo.GetByID(id)
+}
+
 func (s *ExecutionService) DeleteByTestID(testID uuid.UUID, userID uuid.UUID, isRoot bool) (int64, error) {
 	test, err := s.testRepo.GetByID(testID)
 	if err != nil {
@@ -130,31 +279,182 @@ func (s *ExecutionService) DeleteByTestID(testID uuid.UUID, userID uuid.UUID, is
 		return 0, domain.NewForbiddenError("Access denied")
 	}
 
-	// Get all finished executions for this test to delete their metrics
-	filter := domain.ExecutionFilter{
-		TestID:     &testID,
-		Pagination: domain.Pagination{Page: 1, PageSize: 10000},
+	return s.execRepo.DeleteByTestID(testID)
+}
+
+// MetricStorage reports how much metric data the test keeps, with the same
+// ownership check as DeleteByTestID.
+func (s *ExecutionService) MetricStorage(testID uuid.UUID, userID uuid.UUID, isRoot bool) (*domain.MetricStorageStats, error) {
+	test, err := s.testRepo.GetByID(testID)
+	if err != nil {
+		return nil, err
+	}
+	if !isRoot && test.UserID != userID {
+		return nil, domain.NewForbiddenError("Access denied")
+	}
+
+	return s.metricRepo.StorageStats(testID)
+}
+
+// BulkDelete soft-deletes finished executions matching the filter, or with
+// dryRun only counts them. Non-ROOT users only ever match their own executions.
+func (s *ExecutionService) BulkDelete(userID uuid.UUID, isRoot bool, filter domain.ExecutionDeleteFilter, dryRun bool) (int64, error) {
+	if filter.TestID == nil && filter.Status == nil && filter.OlderThan == nil {
+		return 0, domain.NewValidationError(map[string]string{
+			"filter": "At least one of test_id, status or older_than is required",
+		})
+	}
+	if filter.Status != nil {
+		switch *filter.Status {
+		case domain.TestStatusCompleted, domain.TestStatusFailed, domain.TestStatusCancelled, domain.TestStatusTimeout:
+		default:
+			return 0, domain.NewValidationError(map[string]string{
+				"status": "Must be COMPLETED, FAILED, CANCELLED or TIMEOUT",
+			})
+		}
 	}
+
+	filter.UserID = nil
 	if !isRoot {
 		filter.UserID = &userID
 	}
-	execs, _, err := s.execRepo.List(filter)
+	return s.execRepo.DeleteByFilter(filter, dryRun)
+}
+
+// SetNotes replaces the analyst notes of an execution. Blank notes clear them.
+func (s *ExecutionService) SetNotes(id uuid.UUID, userID uuid.UUID, isRoot bool, input domain.UpdateExecutionNotesInput) (*domain.TestExecution, error) {
+	exec, err := s.GetByID(id, userID, isRoot)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	for _, e := range execs {
-		if e.Status != domain.TestStatusRunning && e.Status != domain.TestStatusPending {
-			s.metricRepo.DeleteByExecution(e.ID)
-		}
+
+	notes := strings.TrimSpace(input.Notes)
+	if utf8.RuneCountInString(notes) > maxExecutionNotesLen {
+		return nil, domain.NewValidationError(map[string]string{
+			"notes": fmt.Sprintf("Notes must be at most %d characters", maxExecutionNotesLen),
+		})
 	}
 
-	return s.execRepo.DeleteByTestID(testID)
+	exec.Notes = nil
+	if notes != "" {
+		exec.Notes = &notes
+	}
+	if err := s.execRepo.UpdateNotes(exec.ID, exec.Notes); err != nil {
+		return nil, err
+	}
+	return exec, nil
+}
+
+// SetBaseline makes a completed execution the baseline that later runs of its
+// test are compared against, replacing any previous one.
+func (s *ExecutionService) SetBaseline(id uuid.UUID, userID uuid.UUID, isRoot bool) (*domain.Test, error) {
+	exec, test, err := s.baselineTarget(id, userID, isRoot)
+	if err != nil {
+		return nil, err
+	}
+	if exec.Status != domain.TestStatusCompleted || exec.MetricsSummary == nil {
+		return nil, domain.NewValidationError(map[string]string{
+			"id": "Only a COMPLETED execution with metrics can be the baseline",
+		})
+	}
+	if err := s.testRepo.SetBaseline(test.ID, &exec.ID); err != nil {
+		return nil, err
+	}
+	test.BaselineExecutionID = &exec.ID
+	return test, nil
+}
+
+// ClearBaseline stops comparing runs of the execution's test, if the
+// execution is its baseline.
+func (s *ExecutionService) ClearBaseline(id uuid.UUID, userID uuid.UUID, isRoot bool) (*domain.Test, error) {
+	exec, test, err := s.baselineTarget(id, userID, isRoot)
+	if err != nil {
+		return nil, err
+	}
+	if test.BaselineExecutionID == nil || *test.BaselineExecutionID != exec.ID {
+		return nil, domain.NewValidationError(map[string]string{
+			"id": "Execution is not the baseline of its test",
+		})
+	}
+	if err := s.testRepo.SetBaseline(test.ID, nil); err != nil {
+		return nil, err
+	}
+	test.BaselineExecutionID = nil
+	return test, nil
+}
+
+// baselineTarget loads an execution and its test; both must belong to the
+// user unless ROOT.
+func (s *ExecutionService) baselineTarget(id uuid.UUID, userID uuid.UUID, isRoot bool) (*domain.TestExecution, *domain.Test, error) {
+	exec, err := s.GetByID(id, userID, isRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+	test, err := s.testRepo.GetByID(exec.TestID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isRoot && test.UserID != userID {
+		return nil, nil, domain.NewForbiddenError("Access denied")
+	}
+	return exec, test, nil
+}
+
+// Timeseries returns the execution's traffic in buckets of intervalSeconds.
+func (s *ExecutionService) Timeseries(id uuid.UUID, userID uuid.UUID, isRoot bool, intervalSeconds int) ([]domain.ExecutionTimeseriesPoint, error) {
+	if intervalSeconds < 1 || intervalSeconds > maxTimeseriesInterval {
+		return nil, domain.NewValidationError(map[string]string{
+			"interval": fmt.Sprintf("Must be between 1 and %d seconds", maxTimeseriesInterval),
+		})
+	}
+	exec, err := s.GetByID(id, userID, isRoot)
+	if err != nil {
+		return nil, err
+	}
+	return s.metricRepo.GetExecutionTimeseries(exec.ID, intervalSeconds)
+}
+
+// ErrorSamples returns the failing responses captured by a capture_errors run.
+func (s *ExecutionService) ErrorSamples(id uuid.UUID, userID uuid.UUID, isRoot bool) ([]domain.ErrorSample, error) {
+	exec, err := s.GetByID(id, userID, isRoot)
+	if err != nil {
+		return nil, err
+	}
+	return s.execRepo.ListErrorSamples(exec.ID)
 }
 
 func (s *ExecutionService) List(filter domain.ExecutionFilter) ([]domain.TestExecution, int64, error) {
+	switch filter.Sort {
+	case "", domain.ExecutionSortCreatedAt, domain.ExecutionSortDuration, domain.ExecutionSortStatus:
+	default:
+		return nil, 0, domain.NewValidationError(map[string]string{
+			"sort": "Must be created_at, duration or status",
+		})
+	}
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return nil, 0, domain.NewValidationError(map[string]string{
+			"to": "Must not be before from",
+		})
+	}
 	return s.execRepo.List(filter)
 }
 
+// ListByTest lists the executions of a test, after the same ownership check
+// as DeleteByTestID.
+func (s *ExecutionService) ListByTest(testID uuid.UUID, userID uuid.UUID, isRoot bool, filter domain.ExecutionFilter) ([]domain.TestExecution, int64, error) {
+	test, err := s.testRepo.GetByID(testID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !isRoot && test.UserID != userID {
+		return nil, 0, domain.NewForbiddenError("Access denied")
+	}
+
+	filter.TestID = &testID
+	filter.UserID = nil
+	return s.List(filter)
+}
+
 func (s *ExecutionService) RecalculateMetrics(id uuid.UUID, userID uuid.UUID, isRoot bool) (*domain.TestExecution, error) {
 	exec, err := s.execRepo.GetByID(id)
 	if err != nil {
@@ -185,3 +485,62 @@ func (s *ExecutionService) RecalculateMetrics(id uuid.UUID, userID uuid.UUID, is
 func (s *ExecutionService) GetStats() (map[string]interface{}, error) {
 	return s.execRepo.GetStats()
 }
+
+// AggregationLag is how long the most recently aggregated execution waited
+// between k6 exiting and its metrics being aggregated (nil before any).
+func (s *ExecutionService) AggregationLag() (*time.Duration, error) {
+	lag, ok, err := s.execRepo.LastAggregationLag()
+	if err != nil || !ok {
+		return nil, err
+	}
+	return &lag, nil
+}
+
+// UserUsage is the capacity consumed by one user's executions in the range.
+func (s *ExecutionService) UserUsage(filter domain.UsageFilter) (*domain.UsageStats, error) {
+	if err := validateUsageRange(filter); err != nil {
+		return nil, err
+	}
+	stats, err := s.execRepo.Usage(filter)
+	if err != nil {
+		return nil, err
+	}
+	roundUsage(stats)
+	return stats, nil
+}
+
+// UsageReport is the capacity consumed by all users in the range, in total
+// and per user.
+func (s *ExecutionService) UsageReport(filter domain.UsageFilter) (*domain.UsageReport, error) {
+	filter.UserID = nil
+	if err := validateUsageRange(filter); err != nil {
+		return nil, err
+	}
+	totals, err := s.execRepo.Usage(filter)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.execRepo.UsageByUser(filter)
+	if err != nil {
+		return nil, err
+	}
+	roundUsage(totals)
+	for i := range users {
+		roundUsage(&users[i])
+	}
+	return &domain.UsageReport{From: filter.From, To: filter.To, Totals: *totals, Users: users}, nil
+}
+
+func validateUsageRange(filter domain.UsageFilter) error {
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return domain.NewValidationError(map[string]string{
+			"to": "Must not be before from",
+		})
+	}
+	return nil
+}
+
+func roundUsage(stats *domain.UsageStats) {
+	stats.VUMinutes = math.Round(stats.VUMinutes*100) / 100
+	stats.AvgErrorRate = math.Round(stats.AvgErrorRate*100) / 100
+}
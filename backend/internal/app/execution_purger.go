@@ -0,0 +1,73 @@
+package app
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+// ExecutionPurger permanently removes executions, and their metrics, once
+// they have been soft-deleted for longer than the retention window.
+type ExecutionPurger struct {
+	execRepo  domain.ExecutionRepository
+	retention time.Duration
+	interval  time.Duration
+	ticker    *time.Ticker
+	done      chan struct{}
+	stopOnce  sync.Once
+}
+
+func NewExecutionPurger(execRepo domain.ExecutionRepository, retention, interval time.Duration) *ExecutionPurger {
+	if retention <= 0 {
+		retention = 7 * 24 * time.Hour
+	}
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &ExecutionPurger{
+		execRepo:  execRepo,
+		retention: retention,
+		interval:  interval,
+		done:      make(chan struct{}),
+	}
+}
+
+func (p *ExecutionPurger) Start() {
+	p.ticker = time.NewTicker(p.interval)
+	log.Printf("[Purger] Started (every %s, retention %s)", p.interval, p.retention)
+
+	go func() {
+		p.purge()
+		for {
+			select {
+			case <-p.ticker.C:
+				p.purge()
+			case <-p.done:
+				return
+			}
+		}
+	}()
+}
+
+func (p *ExecutionPurger) Stop() {
+	p.stopOnce.Do(func() {
+		if p.ticker != nil {
+			p.ticker.Stop()
+		}
+		close(p.done)
+		log.Println("[Purger] Stopped")
+	})
+}
+
+func (p *ExecutionPurger) purge() {
+	removed, err := p.execRepo.PurgeDeleted(time.Now().Add(-p.retention))
+	if err != nil {
+		log.Printf("[Purger] Failed to purge deleted executions: %v", err)
+		return
+	}
+	if removed > 0 {
+		log.Printf("[Purger] Permanently removed %d deleted executions", removed)
+	}
+}
@@ -0,0 +1,136 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+	"github.com/willianpsouza/StressTestPlatform/internal/pkg/config"
+)
+
+// passingK6 stands in for a k6 run that completes right away.
+const passingK6 = "exit 0"
+
+type executionFixture struct {
+	*runnerFixture
+	svc     *ExecutionService
+	limiter *fakeRateLimiter
+}
+
+// newExecutionFixture returns an ExecutionService over a runner fixture,
+// allowing startsPerMinute starts per user (0 for no limit).
+func newExecutionFixture(t *testing.T, cfg config.K6Config, script string, startsPerMinute int) *executionFixture {
+	t.Helper()
+	f := &executionFixture{runnerFixture: newRunnerFixture(t, cfg, script), limiter: newFakeRateLimiter(20 * time.Second)}
+	starts := NewStartLimiter(f.limiter, startsPerMinute)
+	f.svc = NewExecutionService(f.execs, newFakeTestRepo(f.test), nil, f.runner, starts, nil, 0)
+	return f
+}
+
+func (f *executionFixture) create(userID uuid.UUID) (*domain.TestExecution, error) {
+	return f.svc.Create(userID, false, domain.CreateExecutionInput{TestID: f.test.ID, VUs: 1, Duration: "1m"})
+}
+
+func TestCreateExecutionAtCapacity(t *testing.T) {
+	f := newExecutionFixture(t, config.K6Config{MaxConcurrent: 1}, blockingK6, 10)
+	owner := f.test.UserID
+	f.occupy(owner, f.test.ID, time.Minute)
+
+	_, err := f.create(owner)
+	if appErr := tooManyRequests(t, err); appErr.RetryAfter <= 0 {
+		t.Errorf("RetryAfter = %v, want the time until a slot frees", appErr.RetryAfter)
+	}
+	if n := f.execs.count(); n != 0 {
+		t.Errorf("%d executions stored for a refused run", n)
+	}
+	if n := f.limiter.count(owner.String()); n != 0 {
+		t.Errorf("refused run used %d starts", n)
+	}
+}
+
+func TestCreateExecutionLosesSlotAfterCheck(t *testing.T) {
+	f := newExecutionFixture(t, config.K6Config{MaxConcurrent: 1}, blockingK6, 10)
+	owner := f.test.UserID
+
+	// CheckCapacity loads the domain first and Run second: another run takes
+	// the only slot in between
+	lookups := 0
+	f.domains.onGet = func() {
+		if lookups++; lookups == 2 {
+			f.occupy(owner, f.test.ID, time.Minute)
+		}
+	}
+
+	_, err := f.create(owner)
+	tooManyRequests(t, err)
+	if len(f.execs.discarded) != 1 {
+		t.Fatalf("discarded %v, want the created execution", f.execs.discarded)
+	}
+	discarded := f.execs.discarded[0]
+	if n := f.execs.count(); n != 0 {
+		t.Errorf("%d executions left stored", n)
+	}
+	if n := f.limiter.count(owner.String()); n != 0 {
+		t.Errorf("start not refunded: %d taken", n)
+	}
+	statuses := f.events.statuses(discarded)
+	if len(statuses) == 0 || statuses[len(statuses)-1] != domain.TestStatusCancelled {
+		t.Errorf("published %v, want a final CANCELLED", statuses)
+	}
+}
+
+func TestCreateExecutionStartLimit(t *testing.T) {
+	f := newExecutionFixture(t, config.K6Config{MaxConcurrent: 5}, passingK6, 1)
+	owner := f.test.UserID
+
+	if _, err := f.create(owner); err != nil {
+		t.Fatalf("first Create: %v", err)
+	}
+	_, err := f.create(owner)
+	if appErr := tooManyRequests(t, err); appErr.RetryAfter != 20*time.Second {
+		t.Errorf("RetryAfter = %v, want the limiter's 20s", appErr.RetryAfter)
+	}
+	if n := f.execs.count(); n != 1 {
+		t.Errorf("%d executions stored, want only the first", n)
+	}
+}
+
+func TestCreateExecutionOwnership(t *testing.T) {
+	tests := []struct {
+		name    string
+		caller  func(f *executionFixture) uuid.UUID
+		isRoot  bool
+		wantErr string
+	}{
+		{"owner", func(f *executionFixture) uuid.UUID { return f.test.UserID }, false, ""},
+		{"root runs another user's test", func(*executionFixture) uuid.UUID { return uuid.New() }, true, ""},
+		{"regular user runs another user's test", func(*executionFixture) uuid.UUID { return uuid.New() }, false, "FORBIDDEN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newExecutionFixture(t, config.K6Config{MaxConcurrent: 5}, passingK6, 0)
+			caller := tt.caller(f)
+
+			exec, err := f.svc.Create(caller, tt.isRoot, domain.CreateExecutionInput{TestID: f.test.ID})
+			if tt.wantErr != "" {
+				if errorCode(err) != tt.wantErr {
+					t.Fatalf("Create = %v, want %s", err, tt.wantErr)
+				}
+				if n := f.execs.count(); n != 0 {
+					t.Errorf("%d executions stored", n)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			// The run is attributed to whoever started it; the test keeps its owner
+			if exec.UserID != caller || exec.TestID != f.test.ID {
+				t.Errorf("execution user %s test %s, want %s and %s", exec.UserID, exec.TestID, caller, f.test.ID)
+			}
+			f.waitForStatus(t, exec, domain.TestStatusCompleted)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package app
+
+import (
+	"regexp"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+// k6 exit codes, see https://github.com/grafana/k6/blob/master/errext/exitcodes/codes.go
+const (
+	k6ExitThresholdsFailed = 99
+	k6ExitSetupTimeout     = 100
+	k6ExitTeardownTimeout  = 101
+	k6ExitGenericTimeout   = 102
+	k6ExitInvalidConfig    = 104
+	k6ExitScriptException  = 107
+	k6ExitScriptAborted    = 108
+)
+
+// scriptErrorPattern matches JavaScript errors k6 prints when the script
+// fails to compile or throws outside a VU iteration.
+var scriptErrorPattern = regexp.MustCompile(`\b(SyntaxError|ReferenceError|TypeError|GoError)\b`)
+
+// classifyExit derives why a failed run stopped. timedOut is the platform's
+// own max duration; oom covers both the cgroup OOM killer and the Go runtime
+// running out of memory. A negative code means k6 did not exit on its own.
+func classifyExit(code int, stderr string, timedOut, oom bool) domain.ExitReason {
+	switch {
+	case oom || outOfMemory(stderr):
+		return domain.ExitReasonOOM
+	case timedOut:
+		return domain.ExitReasonTimeout
+	}
+
+	switch code {
+	case k6ExitThresholdsFailed:
+		return domain.ExitReasonThresholdFailed
+	case k6ExitSetupTimeout, k6ExitTeardownTimeout, k6ExitGenericTimeout:
+		return domain.ExitReasonTimeout
+	case k6ExitInvalidConfig, k6ExitScriptException, k6ExitScriptAborted:
+		return domain.ExitReasonScriptError
+	}
+	if code > 0 && scriptErrorPattern.MatchString(stderr) {
+		return domain.ExitReasonScriptError
+	}
+	return domain.ExitReasonUnknown
+}
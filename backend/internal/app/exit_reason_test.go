@@ -0,0 +1,42 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+func TestClassifyExit(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     int
+		stderr   string
+		timedOut bool
+		oom      bool
+		want     domain.ExitReason
+	}{
+		{name: "thresholds failed", code: 99, want: domain.ExitReasonThresholdFailed},
+		{name: "setup timeout", code: 100, want: domain.ExitReasonTimeout},
+		{name: "teardown timeout", code: 101, want: domain.ExitReasonTimeout},
+		{name: "generic timeout", code: 102, want: domain.ExitReasonTimeout},
+		{name: "invalid config", code: 104, want: domain.ExitReasonScriptError},
+		{name: "script exception", code: 107, want: domain.ExitReasonScriptError},
+		{name: "script aborted", code: 108, want: domain.ExitReasonScriptError},
+		{name: "platform timeout", code: -1, timedOut: true, want: domain.ExitReasonTimeout},
+		{name: "oom killed", code: -1, oom: true, want: domain.ExitReasonOOM},
+		{name: "oom wins over timeout", code: -1, timedOut: true, oom: true, want: domain.ExitReasonOOM},
+		{name: "go runtime oom", code: 2, stderr: "fatal error: runtime: out of memory", want: domain.ExitReasonOOM},
+		{name: "syntax error in stderr", code: 1, stderr: "SyntaxError: Unexpected token", want: domain.ExitReasonScriptError},
+		{name: "script error word inside identifier", code: 1, stderr: "MyTypeErrorHandler failed", want: domain.ExitReasonUnknown},
+		{name: "unknown code", code: 1, stderr: "something went wrong", want: domain.ExitReasonUnknown},
+		{name: "killed without reason", code: -1, stderr: "SyntaxError", want: domain.ExitReasonUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyExit(tt.code, tt.stderr, tt.timedOut, tt.oom); got != tt.want {
+				t.Errorf("classifyExit(%d, %q, %v, %v) = %s, want %s", tt.code, tt.stderr, tt.timedOut, tt.oom, got, tt.want)
+			}
+		})
+	}
+}
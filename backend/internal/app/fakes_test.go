@@ -0,0 +1,563 @@
+package app
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+// In-memory repositories for service tests. They hand out copies, like the
+// postgres adapters, so a service only changes what it saves.
+
+type fakeUserRepo struct {
+	mu    sync.Mutex
+	users map[uuid.UUID]domain.User
+}
+
+func newFakeUserRepo(users ...*domain.User) *fakeUserRepo {
+	r := &fakeUserRepo{users: map[uuid.UUID]domain.User{}}
+	for _, u := range users {
+		r.Create(u)
+	}
+	return r
+}
+
+func (r *fakeUserRepo) Create(user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if user.ID == uuid.Nil {
+		user.ID = uuid.New()
+	}
+	r.users[user.ID] = *user
+	return nil
+}
+
+func (r *fakeUserRepo) GetByID(id uuid.UUID) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.users[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	return &u, nil
+}
+
+func (r *fakeUserRepo) GetByEmail(email string) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range r.users {
+		if u.Email == email {
+			return &u, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func (r *fakeUserRepo) Update(user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[user.ID]; !ok {
+		return domain.ErrUserNotFound
+	}
+	r.users[user.ID] = *user
+	return nil
+}
+
+func (r *fakeUserRepo) Delete(id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.users, id)
+	return nil
+}
+
+func (r *fakeUserRepo) List(domain.UserFilter) ([]domain.User, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var users []domain.User
+	for _, u := range r.users {
+		users = append(users, u)
+	}
+	return users, int64(len(users)), nil
+}
+
+func (r *fakeUserRepo) StartTwoFactorChallenge(userID, challengeID uuid.UUID) error {
+	return nil
+}
+
+func (r *fakeUserRepo) CompleteTwoFactorChallenge(userID, challengeID uuid.UUID, step int64) (bool, error) {
+	return false, nil
+}
+
+func (r *fakeUserRepo) FailTwoFactorChallenge(userID, challengeID uuid.UUID, maxAttempts int) (bool, error) {
+	return false, nil
+}
+
+type fakeSessionRepo struct {
+	mu       sync.Mutex
+	sessions map[uuid.UUID]domain.Session
+}
+
+func newFakeSessionRepo() *fakeSessionRepo {
+	return &fakeSessionRepo{sessions: map[uuid.UUID]domain.Session{}}
+}
+
+func (r *fakeSessionRepo) Create(session *domain.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if session.ID == uuid.Nil {
+		session.ID = uuid.New()
+	}
+	if session.FamilyID == uuid.Nil {
+		session.FamilyID = session.ID
+	}
+	session.CreatedAt = time.Now()
+	r.sessions[session.ID] = *session
+	return nil
+}
+
+func (r *fakeSessionRepo) GetByID(id uuid.UUID) (*domain.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.sessions[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &s, nil
+}
+
+func (r *fakeSessionRepo) GetByTokenHash(hash string) (*domain.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.sessions {
+		if s.TokenHash == hash {
+			return &s, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *fakeSessionRepo) ListByUser(userID uuid.UUID) ([]domain.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var sessions []domain.Session
+	for _, s := range r.sessions {
+		if s.UserID == userID && s.IsValid() {
+			sessions = append(sessions, s)
+		}
+	}
+	return sessions, nil
+}
+
+func (r *fakeSessionRepo) Revoke(id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s, ok := r.sessions[id]; ok {
+		now := time.Now()
+		s.RevokedAt = &now
+		r.sessions[id] = s
+	}
+	return nil
+}
+
+func (r *fakeSessionRepo) Rotate(id uuid.UUID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.sessions[id]
+	if !ok || s.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	s.RevokedAt, s.RotatedAt = &now, &now
+	r.sessions[id] = s
+	return true, nil
+}
+
+func (r *fakeSessionRepo) RevokeAllForUser(userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for id, s := range r.sessions {
+		if s.UserID == userID && s.RevokedAt == nil {
+			s.RevokedAt = &now
+			r.sessions[id] = s
+		}
+	}
+	return nil
+}
+
+func (r *fakeSessionRepo) CleanExpired() (int64, error) {
+	return 0, nil
+}
+
+// active returns the IDs of the user's sessions that are still valid.
+func (r *fakeSessionRepo) active(userID uuid.UUID) map[uuid.UUID]bool {
+	sessions, _ := r.ListByUser(userID)
+	ids := map[uuid.UUID]bool{}
+	for _, s := range sessions {
+		ids[s.ID] = true
+	}
+	return ids
+}
+
+type fakeResetRepo struct {
+	mu     sync.Mutex
+	resets map[uuid.UUID]domain.PasswordReset
+}
+
+func newFakeResetRepo() *fakeResetRepo {
+	return &fakeResetRepo{resets: map[uuid.UUID]domain.PasswordReset{}}
+}
+
+func (r *fakeResetRepo) Create(reset *domain.PasswordReset) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if reset.ID == uuid.Nil {
+		reset.ID = uuid.New()
+	}
+	reset.CreatedAt = time.Now()
+	r.resets[reset.ID] = *reset
+	return nil
+}
+
+func (r *fakeResetRepo) GetByTokenHash(hash string) (*domain.PasswordReset, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, reset := range r.resets {
+		if reset.TokenHash == hash {
+			return &reset, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *fakeResetRepo) MarkUsed(id uuid.UUID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reset, ok := r.resets[id]
+	if !ok || reset.UsedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	reset.UsedAt = &now
+	r.resets[id] = reset
+	return true, nil
+}
+
+func (r *fakeResetRepo) InvalidateAllForUser(userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for id, reset := range r.resets {
+		if reset.UserID == userID && reset.UsedAt == nil {
+			reset.UsedAt = &now
+			r.resets[id] = reset
+		}
+	}
+	return nil
+}
+
+// fakeMailer hands every sent message to a channel.
+type fakeMailer struct {
+	sent chan domain.EmailMessage
+}
+
+func newFakeMailer() *fakeMailer {
+	return &fakeMailer{sent: make(chan domain.EmailMessage, 10)}
+}
+
+func (m *fakeMailer) Send(msg domain.EmailMessage) error {
+	m.sent <- msg
+	return nil
+}
+
+type fakeAPIKeyRepo struct {
+	mu   sync.Mutex
+	keys map[uuid.UUID]domain.APIKey
+}
+
+func newFakeAPIKeyRepo() *fakeAPIKeyRepo {
+	return &fakeAPIKeyRepo{keys: map[uuid.UUID]domain.APIKey{}}
+}
+
+func (r *fakeAPIKeyRepo) Create(key *domain.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if key.ID == uuid.Nil {
+		key.ID = uuid.New()
+	}
+	key.CreatedAt = time.Now()
+	r.keys[key.ID] = *key
+	return nil
+}
+
+func (r *fakeAPIKeyRepo) GetByID(id uuid.UUID) (*domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key, ok := r.keys[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &key, nil
+}
+
+func (r *fakeAPIKeyRepo) GetByHash(hash string) (*domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, key := range r.keys {
+		if key.KeyHash == hash {
+			return &key, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *fakeAPIKeyRepo) ListByUser(userID uuid.UUID) ([]domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var keys []domain.APIKey
+	for _, key := range r.keys {
+		if key.UserID == userID {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (r *fakeAPIKeyRepo) Revoke(id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if key, ok := r.keys[id]; ok && key.RevokedAt == nil {
+		now := time.Now()
+		key.RevokedAt = &now
+		r.keys[id] = key
+	}
+	return nil
+}
+
+func (r *fakeAPIKeyRepo) TouchLastUsed(id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if key, ok := r.keys[id]; ok {
+		now := time.Now()
+		key.LastUsedAt = &now
+		r.keys[id] = key
+	}
+	return nil
+}
+
+// fakeExecRepo implements the part of ExecutionRepository the runner and
+// ExecutionService.Create use; the embedded interface panics on the rest.
+type fakeExecRepo struct {
+	domain.ExecutionRepository
+	mu        sync.Mutex
+	execs     map[uuid.UUID]domain.TestExecution
+	discarded []uuid.UUID
+}
+
+func newFakeExecRepo() *fakeExecRepo {
+	return &fakeExecRepo{execs: map[uuid.UUID]domain.TestExecution{}}
+}
+
+func (r *fakeExecRepo) Create(exec *domain.TestExecution) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if exec.ID == uuid.Nil {
+		exec.ID = uuid.New()
+	}
+	exec.CreatedAt = time.Now()
+	r.execs[exec.ID] = *exec
+	return nil
+}
+
+func (r *fakeExecRepo) GetByID(id uuid.UUID) (*domain.TestExecution, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	exec, ok := r.execs[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &exec, nil
+}
+
+func (r *fakeExecRepo) Update(exec *domain.TestExecution) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.execs[exec.ID] = *exec
+	return nil
+}
+
+func (r *fakeExecRepo) Discard(id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.execs, id)
+	r.discarded = append(r.discarded, id)
+	return nil
+}
+
+func (r *fakeExecRepo) GetLastCompletedAt(uuid.UUID) (*time.Time, error) {
+	return nil, nil
+}
+
+func (r *fakeExecRepo) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.execs)
+}
+
+// status returns the stored status of the execution, "" if there is none.
+func (r *fakeExecRepo) status(id uuid.UUID) domain.TestStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.execs[id].Status
+}
+
+type fakeTestRepo struct {
+	domain.TestRepository
+	tests map[uuid.UUID]*domain.Test
+}
+
+func newFakeTestRepo(tests ...*domain.Test) *fakeTestRepo {
+	r := &fakeTestRepo{tests: map[uuid.UUID]*domain.Test{}}
+	for _, test := range tests {
+		r.tests[test.ID] = test
+	}
+	return r
+}
+
+func (r *fakeTestRepo) GetByID(id uuid.UUID) (*domain.Test, error) {
+	test, ok := r.tests[id]
+	if !ok {
+		return nil, domain.NewNotFoundError("Test")
+	}
+	copied := *test
+	return &copied, nil
+}
+
+// fakeDomainRepo serves domains by ID. onGet, when set, runs before each
+// lookup so tests can change the runner's state between two of them.
+type fakeDomainRepo struct {
+	domain.DomainRepository
+	domains map[uuid.UUID]*domain.Domain
+	onGet   func()
+}
+
+func (r *fakeDomainRepo) GetByID(id uuid.UUID) (*domain.Domain, error) {
+	if r.onGet != nil {
+		r.onGet()
+	}
+	d, ok := r.domains[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	copied := *d
+	return &copied, nil
+}
+
+type fakeEventBus struct {
+	domain.ExecutionEventBus
+	mu     sync.Mutex
+	events []domain.ExecutionEvent
+}
+
+func (b *fakeEventBus) Publish(event domain.ExecutionEvent) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.events = append(b.events, event)
+	return nil
+}
+
+// statuses returns the statuses published for the execution, in order.
+func (b *fakeEventBus) statuses(execID uuid.UUID) []domain.TestStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var statuses []domain.TestStatus
+	for _, e := range b.events {
+		if e.ExecutionID == execID {
+			statuses = append(statuses, e.Status)
+		}
+	}
+	return statuses
+}
+
+// fakeRateLimiter allows limit takes per key; the window never resets.
+type fakeRateLimiter struct {
+	mu         sync.Mutex
+	taken      map[string]int
+	retryAfter time.Duration
+}
+
+func newFakeRateLimiter(retryAfter time.Duration) *fakeRateLimiter {
+	return &fakeRateLimiter{taken: map[string]int{}, retryAfter: retryAfter}
+}
+
+func (l *fakeRateLimiter) Take(key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.taken[key] >= limit {
+		return false, l.retryAfter, nil
+	}
+	l.taken[key]++
+	return true, 0, nil
+}
+
+func (l *fakeRateLimiter) Refund(key string, limit int, window time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.taken[key] > 0 {
+		l.taken[key]--
+	}
+	return nil
+}
+
+func (l *fakeRateLimiter) count(key string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.taken[key]
+}
+
+type fakeScheduleRepo struct {
+	domain.ScheduleRepository
+	schedules []domain.Schedule
+	polls     int // GetDueSchedules calls
+}
+
+func (r *fakeScheduleRepo) Create(schedule *domain.Schedule) error {
+	schedule.ID = uuid.New()
+	r.schedules = append(r.schedules, *schedule)
+	return nil
+}
+
+func (r *fakeScheduleRepo) GetDueSchedules() ([]domain.Schedule, error) {
+	r.polls++
+	return nil, nil
+}
+
+// fakeLeaderLock behaves like the Redis lock: one owner at a time until it
+// releases the lock or stops renewing it past the TTL.
+type fakeLeaderLock struct {
+	mu        sync.Mutex
+	owner     string
+	expiresAt time.Time
+}
+
+func (l *fakeLeaderLock) Acquire(owner string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.owner != "" && l.owner != owner && time.Now().Before(l.expiresAt) {
+		return false, nil
+	}
+	l.owner, l.expiresAt = owner, time.Now().Add(ttl)
+	return true, nil
+}
+
+func (l *fakeLeaderLock) Release(owner string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.owner == owner {
+		l.owner = ""
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+
+	"github.com/google/uuid"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+const maxIdempotencyKeyLen = 255
+
+// CreateIdempotent is Create for a request carrying an Idempotency-Key. The
+// first request with a key creates the execution; repeats within the TTL get
+// that execution back (replayed) instead of starting another one. A repeat
+// with a different input is a 422 rather than a replay. Keys are scoped per
+// user, and a request that fails frees its key for a retry. If the store is
+// unavailable the key is ignored rather than failing the run.
+func (s *ExecutionService) CreateIdempotent(userID uuid.UUID, isRoot bool, key string, input domain.CreateExecutionInput) (exec *domain.TestExecution, replayed bool, err error) {
+	if key == "" || s.idempotency == nil {
+		exec, err = s.Create(userID, isRoot, input)
+		return exec, false, err
+	}
+	if err := validateIdempotencyKey(key); err != nil {
+		return nil, false, err
+	}
+
+	fingerprint, err := idempotencyFingerprint(input)
+	if err != nil {
+		return nil, false, err
+	}
+	storeKey := userID.String() + ":" + key
+	reserved, id, storedFingerprint, err := s.idempotency.Reserve(storeKey, fingerprint, s.idempotencyTTL)
+	if err != nil {
+		log.Printf("[Idempotency] Reserve failed for user %s, ignoring the key: %v", userID, err)
+		exec, err = s.Create(userID, isRoot, input)
+		return exec, false, err
+	}
+	if !reserved {
+		if storedFingerprint != fingerprint {
+			return nil, false, domain.NewValidationError(map[string]string{
+				"Idempotency-Key": "Already used with a different request",
+			})
+		}
+		if id == "" {
+			return nil, false, domain.NewConflictError("A request with this Idempotency-Key is still in progress")
+		}
+		execID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, false, domain.NewConflictError("Idempotency-Key is already in use")
+		}
+		exec, err = s.execRepo.GetByID(execID)
+		if err != nil {
+			return nil, false, err
+		}
+		return exec, true, nil
+	}
+
+	exec, err = s.Create(userID, isRoot, input)
+	if err != nil {
+		if relErr := s.idempotency.Release(storeKey); relErr != nil {
+			log.Printf("[Idempotency] Failed to release key for user %s: %v", userID, relErr)
+		}
+		return nil, false, err
+	}
+	if err := s.idempotency.Complete(storeKey, fingerprint, exec.ID.String(), s.idempotencyTTL); err != nil {
+		log.Printf("[Idempotency] Failed to store execution %s for its key: %v", exec.ID, err)
+	}
+	return exec, false, nil
+}
+
+func validateIdempotencyKey(key string) error {
+	valid := len(key) <= maxIdempotencyKeyLen
+	for i := 0; valid && i < len(key); i++ {
+		valid = key[i] > ' ' && key[i] < 0x7f
+	}
+	if !valid {
+		return domain.NewValidationError(map[string]string{
+			"Idempotency-Key": "Must be up to 255 printable ASCII characters without spaces",
+		})
+	}
+	return nil
+}
+
+// idempotencyFingerprint hashes the request input, so a key reused with a
+// different body is told apart from a retry. encoding/json writes struct
+// fields in order and map keys sorted, so equal inputs hash the same.
+func idempotencyFingerprint(input domain.CreateExecutionInput) (string, error) {
+	b, err := json.Marshal(input)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+func TestValidateIdempotencyKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		wantErr bool
+	}{
+		{name: "uuid", key: uuid.NewString()},
+		{name: "printable punctuation", key: "order-42_retry:1/~"},
+		{name: "max length", key: strings.Repeat("k", maxIdempotencyKeyLen)},
+		{name: "too long", key: strings.Repeat("k", maxIdempotencyKeyLen+1), wantErr: true},
+		{name: "space", key: "order 42", wantErr: true},
+		{name: "tab", key: "order\t42", wantErr: true},
+		{name: "newline", key: "order\n42", wantErr: true},
+		{name: "del", key: "order\x7f", wantErr: true},
+		{name: "non-ascii", key: "pedido-ç", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateIdempotencyKey(tt.key)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if details := validationDetails(t, err); details["Idempotency-Key"] == "" {
+				t.Errorf("missing Idempotency-Key detail: %v", details)
+			}
+		})
+	}
+}
+
+func TestIdempotencyFingerprint(t *testing.T) {
+	testID := uuid.New()
+	base := func() domain.CreateExecutionInput {
+		return domain.CreateExecutionInput{
+			TestID:   testID,
+			VUs:      10,
+			Duration: "30s",
+			Env:      domain.EnvVars{"A": "1", "B": "2", "C": "3"},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(in *domain.CreateExecutionInput)
+		same   bool
+	}{
+		{name: "identical", modify: func(in *domain.CreateExecutionInput) {}, same: true},
+		{name: "same env built in another order", modify: func(in *domain.CreateExecutionInput) {
+			in.Env = domain.EnvVars{}
+			in.Env["C"] = "3"
+			in.Env["B"] = "2"
+			in.Env["A"] = "1"
+		}, same: true},
+		{name: "different vus", modify: func(in *domain.CreateExecutionInput) { in.VUs = 11 }},
+		{name: "different env value", modify: func(in *domain.CreateExecutionInput) { in.Env["A"] = "x" }},
+		{name: "different test", modify: func(in *domain.CreateExecutionInput) { in.TestID = uuid.New() }},
+		{name: "ignore cooldown", modify: func(in *domain.CreateExecutionInput) { in.IgnoreCooldown = true }},
+	}
+
+	want, err := idempotencyFingerprint(base())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := base()
+			tt.modify(&in)
+			got, err := idempotencyFingerprint(in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (got == want) != tt.same {
+				t.Errorf("fingerprint equal = %v, want %v", got == want, tt.same)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/pkg/config"
+)
+
+// reservedK6Flags are set by the runner on every "k6 run"; overriding them
+// from K6_EXTRA_ARGS would break metric import or the VU/duration limits.
+var reservedK6Flags = []string{
+	"--out", "--summary-export", "--summary-trend-stats",
+	"--vus", "--duration", "--stage", "--iterations", "--config",
+}
+
+// ValidateK6Config resolves K6_BINARY_PATH (a bare name is looked up in PATH)
+// to an executable and checks K6_EXTRA_ARGS. It is called once at startup;
+// the resolved path is stored back into cfg.
+func ValidateK6Config(cfg *config.K6Config) error {
+	binary := cfg.BinaryPath
+	if binary == "" {
+		binary = "k6"
+	}
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return fmt.Errorf("k6 binary %q: %w", binary, err)
+	}
+	cfg.BinaryPath = path
+
+	return validateK6ExtraArgs(cfg.ExtraArgs)
+}
+
+// validateK6ExtraArgs only accepts long flags, with values attached as
+// --flag=value, so no argument can be taken as a flag value or script path
+// and the runner's own flags cannot be overridden.
+func validateK6ExtraArgs(args []string) error {
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "--") || arg == "--" {
+			return fmt.Errorf("K6_EXTRA_ARGS: %q must be a long flag (--flag or --flag=value)", arg)
+		}
+		name, _, _ := strings.Cut(arg, "=")
+		for _, reserved := range reservedK6Flags {
+			if name == reserved {
+				return fmt.Errorf("K6_EXTRA_ARGS: %s is set by the platform and cannot be overridden", name)
+			}
+		}
+	}
+	return nil
+}
+
+// k6Binary is the executable to run, "k6" from PATH when not configured.
+func k6Binary(cfg config.K6Config) string {
+	if cfg.BinaryPath == "" {
+		return "k6"
+	}
+	return cfg.BinaryPath
+}
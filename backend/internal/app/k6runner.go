@@ -4,12 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -24,24 +26,94 @@ import (
 type K6Runner struct {
 	mu         sync.Mutex
 	running    map[uuid.UUID]map[uuid.UUID]context.CancelFunc // userID -> execID -> cancel
+	total      int                                            // running executions across all users
+	perTest    map[uuid.UUID]int                              // testID -> running executions
+	queues     map[uuid.UUID][]*queuedRun                     // userID -> FIFO of PENDING runs
+	slots      map[uuid.UUID]runSlot                          // execID -> running execution, for Retry-After hints
+	cooldowns  map[uuid.UUID]*time.Timer                      // userID -> pending dispatch of a cooling-down queue head
 	execRepo   domain.ExecutionRepository
 	testRepo   domain.TestRepository
+	domainRepo domain.DomainRepository
 	metricRepo domain.MetricRepository
+	events     domain.ExecutionEventBus
 	k6Config   config.K6Config
+	logs       *LogBroker
+	notifier   Notifier
+	logger     *slog.Logger
+}
+
+// runSlot is a running execution and when it is expected to free its slot.
+type runSlot struct {
+	userID uuid.UUID
+	testID uuid.UUID
+	endsAt time.Time
+}
+
+type queuedRun struct {
+	execution *domain.TestExecution
+	test      *domain.Test
+	limits    k6Limits
 }
 
 func NewK6Runner(
 	execRepo domain.ExecutionRepository,
 	testRepo domain.TestRepository,
+	domainRepo domain.DomainRepository,
 	metricRepo domain.MetricRepository,
+	events domain.ExecutionEventBus,
+	notifier Notifier,
 	k6Config config.K6Config,
+	logger *slog.Logger,
 ) *K6Runner {
+	if k6Config.CancelGracePeriod <= 0 {
+		k6Config.CancelGracePeriod = 10 * time.Second
+	}
 	return &K6Runner{
 		running:    make(map[uuid.UUID]map[uuid.UUID]context.CancelFunc),
+		perTest:    make(map[uuid.UUID]int),
+		queues:     make(map[uuid.UUID][]*queuedRun),
+		slots:      make(map[uuid.UUID]runSlot),
+		cooldowns:  make(map[uuid.UUID]*time.Timer),
 		execRepo:   execRepo,
 		testRepo:   testRepo,
+		domainRepo: domainRepo,
 		metricRepo: metricRepo,
+		events:     events,
 		k6Config:   k6Config,
+		logs:       NewLogBroker(),
+		notifier:   notifier,
+		logger:     logger.With("component", "k6"),
+	}
+}
+
+// SubscribeLogs attaches to the live output of a running execution.
+func (r *K6Runner) SubscribeLogs(execID uuid.UUID) *LogSubscription {
+	return r.logs.Subscribe(execID)
+}
+
+// SubscribeEvents streams status changes of every execution until ctx is done.
+func (r *K6Runner) SubscribeEvents(ctx context.Context) (<-chan domain.ExecutionEvent, error) {
+	if r.events == nil {
+		return nil, fmt.Errorf("execution events are not available")
+	}
+	return r.events.Subscribe(ctx)
+}
+
+// publish announces the execution's current status. Failures are only logged:
+// clients fall back to polling.
+func (r *K6Runner) publish(execution *domain.TestExecution) {
+	if r.events == nil {
+		return
+	}
+	err := r.events.Publish(domain.ExecutionEvent{
+		ExecutionID: execution.ID,
+		TestID:      execution.TestID,
+		UserID:      execution.UserID,
+		Status:      execution.Status,
+		Timestamp:   time.Now(),
+	})
+	if err != nil {
+		r.logger.Warn("Failed to publish execution event", "execution_id", execution.ID, "error", err)
 	}
 }
 
@@ -51,102 +123,421 @@ func (r *K6Runner) CountRunning(userID uuid.UUID) int {
 	return len(r.running[userID])
 }
 
-func (r *K6Runner) Run(execution *domain.TestExecution) error {
-	// Check concurrency limit (short lock, map read only)
+// Load reports the executions running across all users and those waiting in
+// the per-user queues.
+func (r *K6Runner) Load() (running, queued int) {
 	r.mu.Lock()
-	if len(r.running[execution.UserID]) >= r.k6Config.MaxConcurrent {
-		r.mu.Unlock()
-		return domain.NewTooManyRequestsError(
-			fmt.Sprintf("Maximum %d concurrent tests per user", r.k6Config.MaxConcurrent),
-		)
+	defer r.mu.Unlock()
+	for _, queue := range r.queues {
+		queued += len(queue)
 	}
-	r.mu.Unlock()
+	return r.total, queued
+}
 
-	// I/O and parsing outside the lock
+// ValidateStages checks a ramping profile against the VU limit of the test's
+// domain.
+func (r *K6Runner) ValidateStages(test *domain.Test, stages domain.Stages) error {
+	return validateStages(stages, r.limitsFor(test).MaxVUs)
+}
+
+// limitsFor returns the caps for runs of the test, falling back to the global
+// config when its domain cannot be loaded.
+func (r *K6Runner) limitsFor(test *domain.Test) k6Limits {
+	limits, err := loadK6Limits(r.domainRepo, r.k6Config, test.DomainID)
+	if err != nil {
+		r.logger.Warn("Failed to load domain limits, using global limits", "domain_id", test.DomainID, "error", err)
+	}
+	return limits
+}
+
+// Run starts the execution, or queues it (leaving it PENDING) when a
+// concurrency limit is reached and queueing is enabled.
+func (r *K6Runner) Run(execution *domain.TestExecution) error {
+	r.publish(execution)
+
+	// I/O outside the lock
 	test, err := r.testRepo.GetByID(execution.TestID)
 	if err != nil {
 		return err
 	}
+	limits := r.limitsFor(test)
+
+	// Check and register under lock (prevents race between check and register)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	limitErr := r.limitErrorLocked(execution.UserID, execution.TestID, limits)
+	if limitErr != nil || len(r.queues[execution.UserID]) > 0 {
+		if err := r.queueErrorLocked(execution.UserID, limitErr); err != nil {
+			return err
+		}
+		r.queues[execution.UserID] = append(r.queues[execution.UserID], &queuedRun{execution: execution, test: test, limits: limits})
+		r.logger.Info("Queued execution", "execution_id", execution.ID,
+			"user_id", execution.UserID, "position", len(r.queues[execution.UserID]))
+		return nil
+	}
+
+	r.startLocked(execution, test, limits)
+	return nil
+}
+
+// CheckCapacity reports whether Run would accept a new run of the test by
+// userID right now, so callers can refuse it before persisting anything. Run
+// still has the final say.
+func (r *K6Runner) CheckCapacity(userID uuid.UUID, test *domain.Test) error {
+	limits := r.limitsFor(test)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	limitErr := r.limitErrorLocked(userID, test.ID, limits)
+	if limitErr != nil || len(r.queues[userID]) > 0 {
+		return r.queueErrorLocked(userID, limitErr)
+	}
+	return nil
+}
 
+// queueErrorLocked returns why a run that cannot start right away is refused:
+// limitErr when queueing is disabled, a 429 when the user's queue is full,
+// nil when it can be queued. r.mu must be held.
+func (r *K6Runner) queueErrorLocked(userID uuid.UUID, limitErr error) error {
+	if !r.k6Config.QueueEnabled {
+		return limitErr
+	}
+	if len(r.queues[userID]) >= r.k6Config.MaxQueueDepth {
+		return domain.NewTooManyRequestsError(
+			fmt.Sprintf("Execution queue is full (%d pending runs per user)", r.k6Config.MaxQueueDepth),
+		).WithRetryAfter(r.retryAfterLocked(func(slot runSlot) bool { return slot.userID == userID }))
+	}
+	return nil
+}
+
+// limitErrorLocked returns the 429 for the first concurrency limit a new run
+// of testID by userID would exceed, or nil if it can start. The per-user limit
+// comes from the test's domain. The error's Retry-After is when the first run
+// holding that limit is expected to end. r.mu must be held.
+func (r *K6Runner) limitErrorLocked(userID, testID uuid.UUID, limits k6Limits) error {
+	if len(r.running[userID]) >= limits.MaxConcurrent {
+		return domain.NewTooManyRequestsError(
+			fmt.Sprintf("Maximum %d concurrent tests per user", limits.MaxConcurrent),
+		).WithRetryAfter(r.retryAfterLocked(func(slot runSlot) bool { return slot.userID == userID }))
+	}
+	if r.k6Config.MaxGlobal > 0 && r.total >= r.k6Config.MaxGlobal {
+		return domain.NewTooManyRequestsError(
+			fmt.Sprintf("Maximum %d concurrent tests on the platform", r.k6Config.MaxGlobal),
+		).WithRetryAfter(r.retryAfterLocked(func(runSlot) bool { return true }))
+	}
+	if r.k6Config.MaxPerTest > 0 && r.perTest[testID] >= r.k6Config.MaxPerTest {
+		return domain.NewTooManyRequestsError(
+			fmt.Sprintf("Maximum %d concurrent runs of the same test", r.k6Config.MaxPerTest),
+		).WithRetryAfter(r.retryAfterLocked(func(slot runSlot) bool { return slot.testID == testID }))
+	}
+	return nil
+}
+
+// retryAfterLocked estimates when the first running execution matching match
+// ends, from its start time and effective duration. Runs past their expected
+// end (retries, slow teardown) count as ending within a second. r.mu must be
+// held.
+func (r *K6Runner) retryAfterLocked(match func(runSlot) bool) time.Duration {
+	var first time.Time
+	for _, slot := range r.slots {
+		if match(slot) && (first.IsZero() || slot.endsAt.Before(first)) {
+			first = slot.endsAt
+		}
+	}
+	if first.IsZero() {
+		return 0
+	}
+	return max(time.Until(first), time.Second)
+}
+
+// startLocked registers the execution and launches k6 with its VUs and
+// duration capped to limits. r.mu must be held.
+func (r *K6Runner) startLocked(execution *domain.TestExecution, test *domain.Test, limits k6Limits) {
 	vus := execution.VUs
-	if vus > r.k6Config.MaxVUs {
-		vus = r.k6Config.MaxVUs
+	if vus > limits.MaxVUs {
+		vus = limits.MaxVUs
 	}
 
 	dur, err := time.ParseDuration(execution.Duration)
 	if err != nil {
 		dur = 30 * time.Second
 	}
-	if dur > r.k6Config.MaxDuration {
-		dur = r.k6Config.MaxDuration
+	if dur > limits.MaxDuration {
+		dur = limits.MaxDuration
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), dur+30*time.Second)
-
-	// Re-check and register under lock (prevents race between check and register)
-	r.mu.Lock()
-	if len(r.running[execution.UserID]) >= r.k6Config.MaxConcurrent {
-		r.mu.Unlock()
-		cancel()
-		return domain.NewTooManyRequestsError(
-			fmt.Sprintf("Maximum %d concurrent tests per user", r.k6Config.MaxConcurrent),
-		)
+	if len(execution.Stages) > 0 {
+		var peak int
+		execution.Stages, dur, peak = capStages(execution.Stages, limits.MaxDuration)
+		vus = min(peak, limits.MaxVUs)
 	}
+
+	// Recorded with the RUNNING update so the row shows the load that ran
+	effectiveDur := dur.String()
+	execution.EffectiveVUs = &vus
+	execution.EffectiveDuration = &effectiveDur
+	requestedDur, err := time.ParseDuration(execution.Duration)
+	execution.Capped = vus < execution.VUs || (err == nil && dur < requestedDur)
+
+	// Each attempt gets the full duration plus grace, and retries add their backoff
+	maxRetries, backoff := retryPolicy(execution, test)
+	timeout := time.Duration(maxRetries+1)*(dur+30*time.Second) +
+		backoff*time.Duration(maxRetries*(maxRetries+1)/2)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+
 	if r.running[execution.UserID] == nil {
 		r.running[execution.UserID] = make(map[uuid.UUID]context.CancelFunc)
 	}
 	r.running[execution.UserID][execution.ID] = cancel
-	r.mu.Unlock()
+	r.total++
+	r.perTest[execution.TestID]++
+	r.slots[execution.ID] = runSlot{userID: execution.UserID, testID: execution.TestID, endsAt: time.Now().Add(dur)}
 
 	go r.execute(ctx, cancel, execution, test, vus, dur)
+}
 
-	return nil
+// dispatchAll offers freed slots to every user with queued runs, starting
+// with userID whose run just finished.
+func (r *K6Runner) dispatchAll(userID uuid.UUID) {
+	r.dispatch(userID)
+
+	r.mu.Lock()
+	users := make([]uuid.UUID, 0, len(r.queues))
+	for id := range r.queues {
+		if id != userID {
+			users = append(users, id)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, id := range users {
+		r.dispatch(id)
+	}
+}
+
+// dispatch starts queued runs for the user while slots are free. A run whose
+// test is still cooling down or at its limit blocks the queue until it can go.
+func (r *K6Runner) dispatch(userID uuid.UUID) {
+	for {
+		r.mu.Lock()
+		queue := r.queues[userID]
+		if len(queue) == 0 || r.limitErrorLocked(userID, queue[0].test.ID, queue[0].limits) != nil {
+			r.mu.Unlock()
+			return
+		}
+		next := queue[0]
+		r.mu.Unlock()
+
+		eligibleAt, err := cooldownUntil(r.execRepo, next.test)
+		if err != nil {
+			r.logger.Error("Failed to check cooldown for queued execution", "execution_id", next.execution.ID, "error", err)
+		} else if eligibleAt != nil {
+			r.mu.Lock()
+			if queue := r.queues[userID]; len(queue) > 0 && queue[0] == next {
+				r.waitCooldownLocked(userID, *eligibleAt)
+			}
+			r.mu.Unlock()
+			return
+		}
+
+		r.mu.Lock()
+		queue = r.queues[userID]
+		if len(queue) == 0 || queue[0] != next || r.limitErrorLocked(userID, next.test.ID, next.limits) != nil {
+			r.mu.Unlock()
+			continue
+		}
+		if len(queue) == 1 {
+			delete(r.queues, userID)
+		} else {
+			r.queues[userID] = queue[1:]
+		}
+		r.stopCooldownLocked(userID)
+		r.logger.Info("Dispatching queued execution", "execution_id", next.execution.ID)
+		r.startLocked(next.execution, next.test, next.limits)
+		r.mu.Unlock()
+	}
+}
+
+// waitCooldownLocked arms the user's single cooldown timer to dispatch their
+// queue at eligibleAt, replacing any pending one. r.mu must be held.
+func (r *K6Runner) waitCooldownLocked(userID uuid.UUID, eligibleAt time.Time) {
+	r.stopCooldownLocked(userID)
+	var timer *time.Timer
+	timer = time.AfterFunc(time.Until(eligibleAt), func() {
+		r.mu.Lock()
+		if r.cooldowns[userID] != timer {
+			r.mu.Unlock()
+			return // stopped or replaced after it fired
+		}
+		delete(r.cooldowns, userID)
+		r.mu.Unlock()
+		r.dispatch(userID)
+	})
+	r.cooldowns[userID] = timer
+}
+
+// stopCooldownLocked drops the user's cooldown timer, once the queue head it
+// waits for has changed. r.mu must be held.
+func (r *K6Runner) stopCooldownLocked(userID uuid.UUID) {
+	if timer := r.cooldowns[userID]; timer != nil {
+		timer.Stop()
+		delete(r.cooldowns, userID)
+	}
 }
 
 func (r *K6Runner) Cancel(userID, execID uuid.UUID) bool {
 	r.mu.Lock()
-	defer r.mu.Unlock()
 
 	if userExecs, ok := r.running[userID]; ok {
 		if cancel, ok := userExecs[execID]; ok {
 			cancel()
+			r.mu.Unlock()
 			return true
 		}
 	}
-	return false
+
+	var cancelled *domain.TestExecution
+	headChanged := false
+	queue := r.queues[userID]
+	for i, q := range queue {
+		if q.execution.ID == execID {
+			cancelled = q.execution
+			r.queues[userID] = append(queue[:i:i], queue[i+1:]...)
+			if len(r.queues[userID]) == 0 {
+				delete(r.queues, userID)
+			}
+			if i == 0 {
+				// The cooldown timer waited for this run; the new head
+				// gets its own from dispatch
+				r.stopCooldownLocked(userID)
+				headChanged = true
+			}
+			break
+		}
+	}
+	r.mu.Unlock()
+
+	if cancelled == nil {
+		return false
+	}
+	if headChanged {
+		go r.dispatch(userID)
+	}
+
+	now := time.Now()
+	errMsg := "Test was cancelled while queued"
+	cancelled.Status = domain.TestStatusCancelled
+	cancelled.CompletedAt = &now
+	cancelled.ErrorMessage = &errMsg
+	if err := r.execRepo.Update(cancelled); err != nil {
+		r.logger.Error("Failed to update cancelled execution", "execution_id", execID, "error", err)
+	}
+	r.publish(cancelled)
+	return true
 }
 
 func (r *K6Runner) execute(ctx context.Context, cancel context.CancelFunc, execution *domain.TestExecution, test *domain.Test, vus int, dur time.Duration) {
 	defer cancel()
-	defer r.cleanup(execution.UserID, execution.ID)
+	defer r.cleanup(execution.UserID, execution.TestID, execution.ID)
+
+	logger := r.logger.With("execution_id", execution.ID)
 
 	// Mark as RUNNING
 	now := time.Now()
 	execution.Status = domain.TestStatusRunning
 	execution.StartedAt = &now
 	r.execRepo.Update(execution)
+	r.publish(execution)
 
 	// CSV output file
 	csvPath := filepath.Join(os.TempDir(), fmt.Sprintf("k6-%s.csv", execution.ID))
 	defer os.Remove(csvPath)
+	summaryPath := filepath.Join(os.TempDir(), fmt.Sprintf("k6-%s-summary.json", execution.ID))
+	defer os.Remove(summaryPath)
+
+	scriptPath := test.ScriptPath
+	if execution.CaptureErrors {
+		wrapperPath := filepath.Join(os.TempDir(), fmt.Sprintf("k6-%s-capture.js", execution.ID))
+		if err := writeErrorCaptureWrapper(wrapperPath, test.ScriptPath, test.SuccessStatusCodes); err != nil {
+			logger.Warn("Error capture unavailable, running the script directly", "error", err)
+		} else {
+			scriptPath = wrapperPath
+			defer os.Remove(wrapperPath)
+		}
+	}
 
-	// Build K6 command — output to CSV
-	cmd := exec.CommandContext(ctx, "k6", "run",
-		"--vus", strconv.Itoa(vus),
-		"--duration", dur.String(),
-		"--out", "csv="+csvPath,
-		"--summary-trend-stats", "avg,min,med,max,p(90),p(95),p(99)",
-		test.ScriptPath,
-	)
-
+	// Keep the full output for the execution record and tee it to live subscribers
 	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	liveOut, liveErr := r.logs.Open(execution.ID)
+	defer r.logs.Close(execution.ID)
+	outWriter := io.MultiWriter(&stdout, liveOut)
+	errWriter := io.MultiWriter(&stderr, liveErr)
+
+	logger.Info("Starting execution", "test_id", test.ID, "test", test.Name, "vus", vus, "duration", dur.String())
+
+	// Resource limits: Go runtime env vars always, plus a cgroup when configured
+	limits := resourceLimitsFor(test, r.k6Config)
+	var cgroup *runCgroup
+	if limits.enabled() {
+		logger.Info("Execution resource limits", "limits", limits.String())
+		if r.k6Config.CgroupPath != "" {
+			cg, err := newRunCgroup(r.k6Config.CgroupPath, execution.ID, limits)
+			if err != nil {
+				logger.Warn("Cgroup limits unavailable, using env limits only", "error", err)
+			} else {
+				cgroup = cg
+				defer cgroup.remove()
+			}
+		}
+	}
+
+	// Run k6, retrying transient failures. Timeouts, cancellations and
+	// threshold failures are final.
+	maxRetries, backoff := retryPolicy(execution, test)
+	var err error
+	memoryExceeded := false
+	for attempt := 1; ; attempt++ {
+		execution.Attempts = attempt
+		if maxRetries > 0 {
+			fmt.Fprintf(outWriter, "=== attempt %d/%d ===\n", attempt, maxRetries+1)
+		}
+
+		// Build K6 command — output to CSV
+		cmd := exec.CommandContext(ctx, k6Binary(r.k6Config), k6RunArgs(execution, test, scriptPath, vus, dur, csvPath, summaryPath, r.k6Config.ExtraArgs)...)
+		cmd.Stdout = outWriter
+		cmd.Stderr = errWriter
+		// On cancel/timeout send SIGINT so k6 stops the test and writes its
+		// summary and CSV; it is killed only if still running after the grace period
+		cmd.Cancel = func() error { return cmd.Process.Signal(os.Interrupt) }
+		cmd.WaitDelay = r.k6Config.CancelGracePeriod
+		if env := limits.env(); len(env) > 0 {
+			cmd.Env = append(os.Environ(), env...)
+		}
+		if cgroup != nil {
+			cgroup.attach(cmd)
+		}
+		err = cmd.Run()
+		liveOut.Flush()
+		liveErr.Flush()
 
-	log.Printf("[K6] Starting execution %s for test %s (vus=%d, duration=%s)",
-		execution.ID, test.Name, vus, dur)
+		memoryExceeded = err != nil && limits.MemoryMB > 0 &&
+			((cgroup != nil && cgroup.oomKilled()) || outOfMemory(stderr.String()))
+		if err == nil || ctx.Err() != nil || memoryExceeded || attempt > maxRetries || !retryableRunError(err) {
+			break
+		}
 
-	err := cmd.Run()
+		wait := backoff * time.Duration(attempt)
+		logger.Warn("Execution attempt failed, retrying", "attempt", attempt, "error", err, "retry_in", wait.String())
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
 
 	completedAt := time.Now()
 	execution.CompletedAt = &completedAt
@@ -156,6 +547,16 @@ func (r *K6Runner) execute(ctx context.Context, cancel context.CancelFunc, execu
 	execution.Stdout = &stdoutStr
 	execution.Stderr = &stderrStr
 
+	if scriptPath != test.ScriptPath {
+		if samples := parseErrorSamples(stderrStr); len(samples) > 0 {
+			if err := r.execRepo.AddErrorSamples(execution.ID, samples); err != nil {
+				logger.Error("Failed to store error samples", "error", err)
+			} else {
+				logger.Info("Stored error samples", "samples", len(samples))
+			}
+		}
+	}
+
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			execution.Status = domain.TestStatusTimeout
@@ -168,57 +569,152 @@ func (r *K6Runner) execute(ctx context.Context, cancel context.CancelFunc, execu
 		} else {
 			execution.Status = domain.TestStatusFailed
 			errMsg := err.Error()
+			if memoryExceeded {
+				errMsg = limits.memoryExceededMessage()
+			}
 			execution.ErrorMessage = &errMsg
 		}
 
+		code := -1
 		if exitErr, ok := err.(*exec.ExitError); ok {
-			code := exitErr.ExitCode()
+			code = exitErr.ExitCode()
 			execution.ExitCode = &code
 		}
+		if execution.Status != domain.TestStatusCancelled {
+			reason := classifyExit(code, stderrStr, execution.Status == domain.TestStatusTimeout, memoryExceeded)
+			execution.ExitReason = &reason
+		}
 	} else {
 		execution.Status = domain.TestStatusCompleted
 		code := 0
 		execution.ExitCode = &code
 	}
 
+	// k6 writes the end-of-test summary even when thresholds fail. It is read
+	// before the CSV import because it tells the custom metric types apart.
+	if summary, sumErr := readSummaryExport(summaryPath); sumErr != nil {
+		if !os.IsNotExist(sumErr) {
+			logger.Error("Failed to read summary export", "error", sumErr)
+		}
+	} else {
+		execution.SummaryExport = summary
+		if err := r.execRepo.UpdateSummaryExport(execution.ID, summary); err != nil {
+			logger.Error("Failed to store summary export", "error", err)
+		}
+		if len(test.Thresholds) > 0 {
+			passed, results := evaluateThresholds(test.Thresholds, summary)
+			execution.ThresholdsPassed = &passed
+			execution.ThresholdResults = results
+		}
+	}
+
 	// Import CSV metrics into PostgreSQL (even if test failed, partial data may exist)
 	if _, statErr := os.Stat(csvPath); statErr == nil {
-		imported, importErr := r.importCSVMetrics(csvPath, execution.ID, test.ID)
+		imported, checks, importErr := r.importCSVMetrics(csvPath, execution.ID, test, metricTypesFromSummary(execution.SummaryExport))
+		execution.CheckResults = checks
 		if importErr != nil {
-			log.Printf("[K6] Failed to import CSV metrics for execution %s: %v", execution.ID, importErr)
+			logger.Error("Failed to import CSV metrics", "error", importErr)
 		} else {
-			log.Printf("[K6] Imported %d metric rows for execution %s", imported, execution.ID)
+			logger.Info("Imported metric rows", "rows", imported)
 		}
 
 		// Compute and persist metrics summary (must run before aggregation since it reads raw data)
 		if summary, sumErr := r.metricRepo.ComputeExecutionSummary(execution.ID); sumErr != nil {
-			log.Printf("[K6] Failed to compute metrics summary for execution %s: %v", execution.ID, sumErr)
+			logger.Error("Failed to compute metrics summary", "error", sumErr)
 		} else {
 			execution.MetricsSummary = summary
 		}
 
 		// Aggregate metrics into k6_metrics_aggregated and clean up raw data
 		if aggErr := r.metricRepo.AggregateAndCleanup(execution.ID); aggErr != nil {
-			log.Printf("[K6] Failed to aggregate metrics for execution %s: %v", execution.ID, aggErr)
+			logger.Error("Failed to aggregate metrics", "error", aggErr)
 		} else {
-			log.Printf("[K6] Aggregated and cleaned up raw metrics for execution %s", execution.ID)
+			aggregatedAt := time.Now()
+			execution.AggregatedAt = &aggregatedAt
+			logger.Info("Aggregated and cleaned up raw metrics", "lag", aggregatedAt.Sub(*execution.CompletedAt).Round(time.Millisecond))
 		}
 	}
 
+	r.checkBaseline(test, execution, logger)
+
 	if err := r.execRepo.Update(execution); err != nil {
-		log.Printf("[K6] Failed to update execution %s: %v", execution.ID, err)
+		logger.Error("Failed to update execution", "error", err)
+	}
+	r.publish(execution)
+
+	logger.Info("Execution finished", "status", execution.Status)
+
+	r.notifier.Notify(test, execution)
+}
+
+// k6RunArgs builds the argv for "k6 run". Values are passed as separate argv
+// elements and never go through a shell. extraArgs (K6_EXTRA_ARGS, checked by
+// validateK6ExtraArgs) come right after "run". scriptPath is the test script,
+// or the error-capture wrapper around it.
+func k6RunArgs(execution *domain.TestExecution, test *domain.Test, scriptPath string, vus int, dur time.Duration, csvPath, summaryPath string, extraArgs []string) []string {
+	args := append([]string{"run"}, extraArgs...)
+	if len(execution.Stages) > 0 {
+		args = append(args, stageArgs(execution.Stages)...)
+	} else {
+		args = append(args, "--vus", strconv.Itoa(vus), "--duration", dur.String())
+	}
+	args = append(args,
+		"--out", "csv="+csvPath,
+		"--summary-export", summaryPath,
+		"--summary-trend-stats", summaryTrendStats(test),
+	)
+	args = append(args, envArgs(execution.Env)...)
+	if len(execution.Targets) > 0 {
+		targetsJSON, _ := json.Marshal(execution.Targets)
+		args = append(args, "--env", "TARGETS="+string(targetsJSON))
+	}
+	return append(args, scriptPath)
+}
+
+// summaryTrendStats returns the stats k6 exports for trend metrics (the
+// test's own or the defaults), plus any extra percentiles the test's
+// thresholds or baseline comparison need.
+func summaryTrendStats(test *domain.Test) string {
+	stats := slices.Clone(defaultSummaryTrendStats)
+	if len(test.SummaryTrendStats) > 0 {
+		stats = slices.Clone(test.SummaryTrendStats)
+	}
+	extra := thresholdTrendStats(test.Thresholds)
+	if test.BaselineExecutionID != nil {
+		extra = append(extra, baselineTrendStat)
+	}
+	for _, s := range extra {
+		if !slices.Contains(stats, s) {
+			stats = append(stats, s)
+		}
 	}
+	return strings.Join(stats, ",")
+}
 
-	log.Printf("[K6] Execution %s finished with status %s", execution.ID, execution.Status)
+// readSummaryExport loads the JSON written by k6 --summary-export.
+func readSummaryExport(path string) (domain.JSONMap, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var summary domain.JSONMap
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return nil, fmt.Errorf("parse summary export: %w", err)
+	}
+	return summary, nil
 }
 
 // importCSVMetrics parses the K6 CSV output and bulk inserts into PostgreSQL.
 // K6 CSV columns: metric_name,timestamp,metric_value,check,error,error_code,
 // expected_response,group,method,name,proto,scenario,service,status,subproto,tls_version,url,extra_tags
-func (r *K6Runner) importCSVMetrics(csvPath string, executionID, testID uuid.UUID) (int, error) {
+// Each row is tagged with its metric type (see classifyMetric); an optional
+// metric_type column overrides the inferred one. The pass/fail counts of the
+// "checks" rows are returned per check, including on a partial import.
+// URLs are normalized (see normalizeMetricURL) when the test asks for it.
+func (r *K6Runner) importCSVMetrics(csvPath string, executionID uuid.UUID, test *domain.Test, types map[string]domain.MetricType) (int, domain.CheckResults, error) {
 	f, err := os.Open(csvPath)
 	if err != nil {
-		return 0, fmt.Errorf("open csv: %w", err)
+		return 0, nil, fmt.Errorf("open csv: %w", err)
 	}
 	defer f.Close()
 
@@ -229,7 +725,7 @@ func (r *K6Runner) importCSVMetrics(csvPath string, executionID, testID uuid.UUI
 	// Read header
 	header, err := reader.Read()
 	if err != nil {
-		return 0, fmt.Errorf("read csv header: %w", err)
+		return 0, nil, fmt.Errorf("read csv header: %w", err)
 	}
 
 	// Map column names to indices
@@ -241,12 +737,13 @@ func (r *K6Runner) importCSVMetrics(csvPath string, executionID, testID uuid.UUI
 	// Validate required columns
 	for _, col := range []string{"metric_name", "timestamp", "metric_value"} {
 		if _, ok := colIdx[col]; !ok {
-			return 0, fmt.Errorf("missing required column: %s", col)
+			return 0, nil, fmt.Errorf("missing required column: %s", col)
 		}
 	}
 
 	var metrics []domain.K6Metric
 	total := 0
+	checks := checkTally{}
 
 	for {
 		record, err := reader.Read()
@@ -278,8 +775,9 @@ func (r *K6Runner) importCSVMetrics(csvPath string, executionID, testID uuid.UUI
 
 		m := domain.K6Metric{
 			ExecutionID: executionID,
-			TestID:      testID,
+			TestID:      test.ID,
 			MetricName:  metricName,
+			MetricType:  classifyMetric(metricName, getCol(record, colIdx, "metric_type"), types),
 			Timestamp:   ts,
 			MetricValue: val,
 		}
@@ -291,18 +789,26 @@ func (r *K6Runner) importCSVMetrics(csvPath string, executionID, testID uuid.UUI
 			m.Status = &v
 		}
 		if v := getCol(record, colIdx, "url"); v != "" {
+			if test.NormalizeURLs {
+				v = normalizeMetricURL(v)
+			}
 			m.URL = &v
 		}
 		if v := getCol(record, colIdx, "scenario"); v != "" {
 			m.Scenario = &v
 		}
+		if metricName == "checks" {
+			if name := getCol(record, colIdx, "check"); name != "" {
+				checks.add(getCol(record, colIdx, "group"), name, val > 0)
+			}
+		}
 
 		metrics = append(metrics, m)
 
 		// Flush in batches of 1000 to avoid memory buildup
 		if len(metrics) >= 1000 {
 			if err := r.metricRepo.BulkInsert(metrics); err != nil {
-				return total, fmt.Errorf("bulk insert batch: %w", err)
+				return total, checks.results(), fmt.Errorf("bulk insert batch: %w", err)
 			}
 			total += len(metrics)
 			metrics = metrics[:0]
@@ -312,12 +818,45 @@ func (r *K6Runner) importCSVMetrics(csvPath string, executionID, testID uuid.UUI
 	// Flush remaining
 	if len(metrics) > 0 {
 		if err := r.metricRepo.BulkInsert(metrics); err != nil {
-			return total, fmt.Errorf("bulk insert final batch: %w", err)
+			return total, checks.results(), fmt.Errorf("bulk insert final batch: %w", err)
 		}
 		total += len(metrics)
 	}
 
-	return total, nil
+	return total, checks.results(), nil
+}
+
+// normalizeMetricURL drops the query string and fragment and replaces numeric
+// and UUID path segments with ":id", so /users/1?x=y and /users/2 aggregate
+// as /users/:id. Error samples keep the raw URL: they are captured from the
+// k6 output, not from the metric rows.
+func normalizeMetricURL(raw string) string {
+	if i := strings.IndexAny(raw, "?#"); i >= 0 {
+		raw = raw[:i]
+	}
+	segments := strings.Split(raw, "/")
+	for i, seg := range segments {
+		if isIDSegment(seg) {
+			segments[i] = ":id"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func isIDSegment(seg string) bool {
+	if seg == "" {
+		return false
+	}
+	if len(seg) == 36 {
+		_, err := uuid.Parse(seg)
+		return err == nil
+	}
+	for _, c := range seg {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
 }
 
 func getCol(record []string, colIdx map[string]int, name string) string {
@@ -345,25 +884,35 @@ func parseK6Timestamp(s string) (time.Time, error) {
 	return time.Parse(time.RFC3339, s)
 }
 
-func (r *K6Runner) cleanup(userID, execID uuid.UUID) {
+func (r *K6Runner) cleanup(userID, testID, execID uuid.UUID) {
 	r.mu.Lock()
-	defer r.mu.Unlock()
-
 	if userExecs, ok := r.running[userID]; ok {
-		delete(userExecs, execID)
+		if _, ok := userExecs[execID]; ok {
+			delete(userExecs, execID)
+			delete(r.slots, execID)
+			r.total--
+			r.perTest[testID]--
+			if r.perTest[testID] <= 0 {
+				delete(r.perTest, testID)
+			}
+		}
 		if len(userExecs) == 0 {
 			delete(r.running, userID)
 		}
 	}
+	r.mu.Unlock()
+
+	// A slot just freed up
+	r.dispatchAll(userID)
 }
 
 func (r *K6Runner) RecoverOrphans() {
 	count, err := r.execRepo.MarkOrphansAsFailed()
 	if err != nil {
-		log.Printf("[K6] Failed to recover orphan executions: %v", err)
+		r.logger.Error("Failed to recover orphan executions", "error", err)
 		return
 	}
 	if count > 0 {
-		log.Printf("[K6] Recovered %d orphan executions (marked as FAILED)", count)
+		r.logger.Info("Recovered orphan executions (marked as FAILED)", "count", count)
 	}
 }
@@ -0,0 +1,316 @@
+package app
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+	"github.com/willianpsouza/StressTestPlatform/internal/pkg/config"
+)
+
+// blockingK6 stands in for a k6 run that lasts until it is cancelled.
+const blockingK6 = "exec sleep 60"
+
+type runnerFixture struct {
+	runner  *K6Runner
+	execs   *fakeExecRepo
+	domains *fakeDomainRepo
+	events  *fakeEventBus
+	test    *domain.Test
+}
+
+// newRunnerFixture returns a runner for one test whose k6 binary is a shell
+// script running script.
+func newRunnerFixture(t *testing.T, cfg config.K6Config, script string) *runnerFixture {
+	t.Helper()
+	dir := t.TempDir()
+	cfg.BinaryPath = filepath.Join(dir, "k6")
+	if err := os.WriteFile(cfg.BinaryPath, []byte("#!/bin/sh\n"+script+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.MaxVUs == 0 {
+		cfg.MaxVUs = 100
+	}
+	if cfg.MaxDuration == 0 {
+		cfg.MaxDuration = time.Hour
+	}
+
+	d := &domain.Domain{ID: uuid.New(), Name: "example.com"}
+	test := &domain.Test{ID: uuid.New(), DomainID: d.ID, UserID: uuid.New(), Name: "load", ScriptPath: filepath.Join(dir, "script.js")}
+	f := &runnerFixture{
+		execs:   newFakeExecRepo(),
+		domains: &fakeDomainRepo{domains: map[uuid.UUID]*domain.Domain{d.ID: d}},
+		events:  &fakeEventBus{},
+		test:    test,
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	f.runner = NewK6Runner(f.execs, newFakeTestRepo(test), f.domains, nil, f.events, Notifiers(nil), cfg, logger)
+	t.Cleanup(f.stopAll)
+	return f
+}
+
+// newExecution stores a PENDING one-minute run of the fixture's test.
+func (f *runnerFixture) newExecution(userID uuid.UUID) *domain.TestExecution {
+	exec := &domain.TestExecution{TestID: f.test.ID, UserID: userID, VUs: 1, Duration: "1m", Status: domain.TestStatusPending}
+	f.execs.Create(exec)
+	return exec
+}
+
+// occupy registers a running execution without starting k6, as startLocked
+// would, expected to end after endsIn. Cancelling it frees the slot.
+func (f *runnerFixture) occupy(userID, testID uuid.UUID, endsIn time.Duration) {
+	r := f.runner
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	execID := uuid.New()
+	if r.running[userID] == nil {
+		r.running[userID] = make(map[uuid.UUID]context.CancelFunc)
+	}
+	r.running[userID][execID] = func() { go r.cleanup(userID, testID, execID) }
+	r.total++
+	r.perTest[testID]++
+	r.slots[execID] = runSlot{userID: userID, testID: testID, endsAt: time.Now().Add(endsIn)}
+}
+
+// stopAll drops the queues and cancels every run, then waits for them to end.
+func (f *runnerFixture) stopAll() {
+	r := f.runner
+	r.mu.Lock()
+	r.queues = make(map[uuid.UUID][]*queuedRun)
+	for _, execs := range r.running {
+		for _, cancel := range execs {
+			cancel()
+		}
+	}
+	r.mu.Unlock()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		r.mu.Lock()
+		running := len(r.slots)
+		r.mu.Unlock()
+		if running == 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// waitForStatus waits until the stored execution has status.
+func (f *runnerFixture) waitForStatus(t *testing.T, exec *domain.TestExecution, status domain.TestStatus) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for f.execs.status(exec.ID) != status {
+		if time.Now().After(deadline) {
+			t.Fatalf("execution status = %s, want %s", f.execs.status(exec.ID), status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// tooManyRequests returns the 429 err is, failing the test for anything else.
+func tooManyRequests(t *testing.T, err error) *domain.AppError {
+	t.Helper()
+	if !domain.IsTooManyRequests(err) {
+		t.Fatalf("error = %v, want a 429", err)
+	}
+	return err.(*domain.AppError)
+}
+
+func TestK6RunnerLimits(t *testing.T) {
+	userID, otherTest := uuid.New(), uuid.New()
+	type slot struct {
+		user, test uuid.UUID
+	}
+
+	tests := []struct {
+		name        string
+		cfg         config.K6Config
+		domainLimit int // the domain's MaxConcurrent override, 0 for none
+		running     func(testID uuid.UUID) []slot
+		wantErr     string
+	}{
+		{
+			name:    "under every limit",
+			cfg:     config.K6Config{MaxConcurrent: 2, MaxGlobal: 3, MaxPerTest: 2},
+			running: func(testID uuid.UUID) []slot { return []slot{{userID, otherTest}, {uuid.New(), testID}} },
+		},
+		{
+			name:    "per user",
+			cfg:     config.K6Config{MaxConcurrent: 1},
+			running: func(testID uuid.UUID) []slot { return []slot{{userID, otherTest}} },
+			wantErr: "Maximum 1 concurrent tests per user",
+		},
+		{
+			name:        "domain raises the per-user limit",
+			cfg:         config.K6Config{MaxConcurrent: 1},
+			domainLimit: 3,
+			running:     func(testID uuid.UUID) []slot { return []slot{{userID, otherTest}, {userID, otherTest}} },
+		},
+		{
+			name:        "domain lowers the per-user limit",
+			cfg:         config.K6Config{MaxConcurrent: 5},
+			domainLimit: 1,
+			running:     func(testID uuid.UUID) []slot { return []slot{{userID, otherTest}} },
+			wantErr:     "Maximum 1 concurrent tests per user",
+		},
+		{
+			name: "global across users",
+			cfg:  config.K6Config{MaxConcurrent: 5, MaxGlobal: 2},
+			running: func(testID uuid.UUID) []slot {
+				return []slot{{uuid.New(), otherTest}, {uuid.New(), otherTest}}
+			},
+			wantErr: "Maximum 2 concurrent tests on the platform",
+		},
+		{
+			name: "global unlimited",
+			cfg:  config.K6Config{MaxConcurrent: 5},
+			running: func(testID uuid.UUID) []slot {
+				return []slot{{uuid.New(), otherTest}, {uuid.New(), otherTest}, {uuid.New(), otherTest}}
+			},
+		},
+		{
+			name:    "same test by another user",
+			cfg:     config.K6Config{MaxConcurrent: 5, MaxPerTest: 1},
+			running: func(testID uuid.UUID) []slot { return []slot{{uuid.New(), testID}} },
+			wantErr: "Maximum 1 concurrent runs of the same test",
+		},
+		{
+			name: "queueing accepts a run over the limit",
+			cfg:  config.K6Config{MaxConcurrent: 1, QueueEnabled: true, MaxQueueDepth: 1},
+			running: func(testID uuid.UUID) []slot {
+				return []slot{{userID, otherTest}}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newRunnerFixture(t, tt.cfg, blockingK6)
+			if tt.domainLimit > 0 {
+				f.domains.domains[f.test.DomainID].MaxConcurrent = &tt.domainLimit
+			}
+			for _, s := range tt.running(f.test.ID) {
+				f.occupy(s.user, s.test, time.Minute)
+			}
+
+			err := f.runner.CheckCapacity(userID, f.test)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("CheckCapacity = %v, want nil", err)
+				}
+				return
+			}
+			appErr := tooManyRequests(t, err)
+			if appErr.Message != tt.wantErr {
+				t.Errorf("message = %q, want %q", appErr.Message, tt.wantErr)
+			}
+			if appErr.RetryAfter < 50*time.Second || appErr.RetryAfter > time.Minute {
+				t.Errorf("RetryAfter = %v, want the time until the running slot ends", appErr.RetryAfter)
+			}
+		})
+	}
+}
+
+func TestK6RunnerQueuesInOrder(t *testing.T) {
+	f := newRunnerFixture(t, config.K6Config{MaxConcurrent: 1, QueueEnabled: true, MaxQueueDepth: 2}, blockingK6)
+	userID := uuid.New()
+
+	runs := []*domain.TestExecution{f.newExecution(userID), f.newExecution(userID), f.newExecution(userID)}
+	for _, exec := range runs {
+		if err := f.runner.Run(exec); err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+	}
+	f.waitForStatus(t, runs[0], domain.TestStatusRunning)
+	if running, queued := f.runner.Load(); running != 1 || queued != 2 {
+		t.Fatalf("Load = %d running, %d queued; want 1, 2", running, queued)
+	}
+
+	err := f.runner.Run(f.newExecution(userID))
+	if appErr := tooManyRequests(t, err); !strings.Contains(appErr.Message, "queue is full") || appErr.RetryAfter <= 0 {
+		t.Errorf("Run with a full queue = %v (Retry-After %v)", appErr, appErr.RetryAfter)
+	}
+
+	// Each freed slot goes to the oldest queued run
+	for i := 1; i < len(runs); i++ {
+		f.runner.Cancel(userID, runs[i-1].ID)
+		f.waitForStatus(t, runs[i-1], domain.TestStatusCancelled)
+		f.waitForStatus(t, runs[i], domain.TestStatusRunning)
+		for _, later := range runs[i+1:] {
+			if status := f.execs.status(later.ID); status != domain.TestStatusPending {
+				t.Errorf("run queued later is %s, want PENDING", status)
+			}
+		}
+	}
+}
+
+func TestK6RunnerCancelQueued(t *testing.T) {
+	f := newRunnerFixture(t, config.K6Config{MaxConcurrent: 1, QueueEnabled: true, MaxQueueDepth: 1}, blockingK6)
+	userID := uuid.New()
+	f.occupy(userID, f.test.ID, time.Minute)
+
+	queued := f.newExecution(userID)
+	if err := f.runner.Run(queued); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !f.runner.Cancel(userID, queued.ID) {
+		t.Fatal("Cancel = false for a queued run")
+	}
+	if status := f.execs.status(queued.ID); status != domain.TestStatusCancelled {
+		t.Errorf("status = %s, want CANCELLED", status)
+	}
+	statuses := f.events.statuses(queued.ID)
+	if len(statuses) == 0 || statuses[len(statuses)-1] != domain.TestStatusCancelled {
+		t.Errorf("published %v, want a final CANCELLED", statuses)
+	}
+	if _, n := f.runner.Load(); n != 0 {
+		t.Errorf("%d runs still queued", n)
+	}
+}
+
+func TestK6RunnerRejectsWithoutQueue(t *testing.T) {
+	f := newRunnerFixture(t, config.K6Config{MaxConcurrent: 1}, blockingK6)
+	userID := uuid.New()
+	f.occupy(userID, f.test.ID, time.Minute)
+
+	tooManyRequests(t, f.runner.Run(f.newExecution(userID)))
+	if _, queued := f.runner.Load(); queued != 0 {
+		t.Errorf("%d runs queued with queueing disabled", queued)
+	}
+}
+
+func TestK6RunnerGlobalLimitAcrossUsers(t *testing.T) {
+	f := newRunnerFixture(t, config.K6Config{MaxConcurrent: 1, MaxGlobal: 2}, blockingK6)
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+
+	firstRun := f.newExecution(first)
+	for _, exec := range []*domain.TestExecution{firstRun, f.newExecution(second)} {
+		if err := f.runner.Run(exec); err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+	}
+
+	err := f.runner.Run(f.newExecution(third))
+	if appErr := tooManyRequests(t, err); appErr.Message != "Maximum 2 concurrent tests on the platform" {
+		t.Errorf("message = %q, want the global limit", appErr.Message)
+	}
+
+	f.runner.Cancel(first, firstRun.ID)
+	f.waitForStatus(t, firstRun, domain.TestStatusCancelled)
+	deadline := time.Now().Add(5 * time.Second)
+	for f.runner.CheckCapacity(third, f.test) != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("freed slot not available to another user")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,165 @@
+package app
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+const (
+	logBacklogLines   = 1000 // lines kept per execution for late subscribers
+	logSubscriberBuf  = 256
+	logMaxPartialLine = 64 * 1024
+)
+
+// LogLine is a single line of k6 output.
+type LogLine struct {
+	Stream string `json:"stream"` // "stdout" or "stderr"
+	Text   string `json:"text"`
+}
+
+// LogBroker fans out live k6 output to subscribers, keyed by execution ID.
+type LogBroker struct {
+	mu      sync.Mutex
+	streams map[uuid.UUID]*logStream
+}
+
+type logStream struct {
+	mu          sync.Mutex
+	backlog     []LogLine
+	start       int // ring buffer head once backlog is full
+	subscribers map[chan LogLine]struct{}
+	closed      bool
+}
+
+func NewLogBroker() *LogBroker {
+	return &LogBroker{streams: make(map[uuid.UUID]*logStream)}
+}
+
+// Open registers a stream for an execution and returns writers for stdout and stderr.
+func (b *LogBroker) Open(execID uuid.UUID) (stdout, stderr *lineWriter) {
+	s := &logStream{subscribers: make(map[chan LogLine]struct{})}
+	b.mu.Lock()
+	b.streams[execID] = s
+	b.mu.Unlock()
+	return &lineWriter{stream: s, name: "stdout"}, &lineWriter{stream: s, name: "stderr"}
+}
+
+// Close ends the stream: subscriber channels are closed and the backlog dropped.
+func (b *LogBroker) Close(execID uuid.UUID) {
+	b.mu.Lock()
+	s, ok := b.streams[execID]
+	delete(b.streams, execID)
+	b.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for ch := range s.subscribers {
+		close(ch)
+	}
+	s.subscribers = nil
+}
+
+// LogSubscription holds the lines emitted before subscribing and a channel for
+// new ones. Lines is closed when the stream ends.
+type LogSubscription struct {
+	Backlog []LogLine
+	Lines   <-chan LogLine
+	stream  *logStream
+	ch      chan LogLine
+}
+
+// Unsubscribe detaches from the stream; safe to call after the stream ended.
+func (sub *LogSubscription) Unsubscribe() {
+	sub.stream.mu.Lock()
+	defer sub.stream.mu.Unlock()
+	if _, ok := sub.stream.subscribers[sub.ch]; ok {
+		delete(sub.stream.subscribers, sub.ch)
+		close(sub.ch)
+	}
+}
+
+// Subscribe returns nil when the execution has no live stream (not started
+// yet, already finished, or running on another instance).
+func (b *LogBroker) Subscribe(execID uuid.UUID) *LogSubscription {
+	b.mu.Lock()
+	s, ok := b.streams[execID]
+	b.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+
+	backlog := make([]LogLine, 0, len(s.backlog))
+	backlog = append(backlog, s.backlog[s.start:]...)
+	backlog = append(backlog, s.backlog[:s.start]...)
+
+	ch := make(chan LogLine, logSubscriberBuf)
+	s.subscribers[ch] = struct{}{}
+
+	return &LogSubscription{Backlog: backlog, Lines: ch, stream: s, ch: ch}
+}
+
+func (s *logStream) publish(line LogLine) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+
+	if len(s.backlog) < logBacklogLines {
+		s.backlog = append(s.backlog, line)
+	} else {
+		s.backlog[s.start] = line
+		s.start = (s.start + 1) % logBacklogLines
+	}
+
+	for ch := range s.subscribers {
+		select {
+		case ch <- line:
+		default:
+			// Slow subscriber: drop the line rather than block k6
+		}
+	}
+}
+
+// lineWriter splits k6 output into lines and publishes each one.
+type lineWriter struct {
+	stream  *logStream
+	name    string
+	partial []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		w.stream.publish(LogLine{Stream: w.name, Text: string(bytes.TrimRight(w.partial[:i], "\r"))})
+		w.partial = w.partial[i+1:]
+	}
+	if len(w.partial) > logMaxPartialLine {
+		w.Flush()
+	}
+	return len(p), nil
+}
+
+// Flush publishes any trailing output that did not end with a newline.
+func (w *lineWriter) Flush() {
+	if len(w.partial) > 0 {
+		w.stream.publish(LogLine{Stream: w.name, Text: string(w.partial)})
+		w.partial = nil
+	}
+}
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+// metricTypesFromSummary reads the metric types out of a k6 --summary-export
+// document. The export has no explicit type, so it is inferred from the
+// statistics k6 writes for each kind of metric.
+func metricTypesFromSummary(summary domain.JSONMap) map[string]domain.MetricType {
+	metrics, _ := summary["metrics"].(map[string]interface{})
+	types := make(map[string]domain.MetricType, len(metrics))
+	for name, raw := range metrics {
+		values, _ := raw.(map[string]interface{})
+		switch {
+		case has(values, "passes"):
+			types[name] = domain.MetricTypeRate
+		case has(values, "count"):
+			types[name] = domain.MetricTypeCounter
+		case has(values, "avg"):
+			types[name] = domain.MetricTypeTrend
+		case has(values, "value"):
+			types[name] = domain.MetricTypeGauge
+		}
+	}
+	return types
+}
+
+func has(values map[string]interface{}, key string) bool {
+	_, ok := values[key]
+	return ok
+}
+
+// classifyMetric picks the type of a CSV metric: an explicit metric_type
+// column wins, then the summary export, the k6 built-ins and finally the
+// metric name. Unrecognised custom metrics are treated as Trends.
+func classifyMetric(name, column string, fromSummary map[string]domain.MetricType) domain.MetricType {
+	if t := domain.MetricType(strings.ToLower(column)); t.IsValid() {
+		return t
+	}
+	if t, ok := fromSummary[name]; ok {
+		return t
+	}
+	if t, ok := domain.BuiltinMetrics[name]; ok {
+		return t
+	}
+
+	lower := strings.ToLower(name)
+	switch {
+	case hasAnySuffix(lower, "rate", "ratio"):
+		return domain.MetricTypeRate
+	case hasAnySuffix(lower, "count", "counter", "total", "errors"):
+		return domain.MetricTypeCounter
+	case hasAnySuffix(lower, "gauge"):
+		return domain.MetricTypeGauge
+	}
+	return domain.MetricTypeTrend
+}
+
+func hasAnySuffix(s string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package app
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+// MetricsPurge reports what a purge of aggregated metrics removed.
+type MetricsPurge struct {
+	Before         time.Time `json:"before"`
+	AggregatedRows int64     `json:"aggregated_rows"`
+	Executions     int64     `json:"executions"`
+}
+
+// MetricsPurger keeps k6_metrics_aggregated bounded: aggregated rows of
+// executions older than the retention window are removed, together with
+// soft-deleted executions from that period.
+type MetricsPurger struct {
+	metricRepo domain.MetricRepository
+	execRepo   domain.ExecutionRepository
+	retention  time.Duration
+	interval   time.Duration
+	ticker     *time.Ticker
+	done       chan struct{}
+	stopOnce   sync.Once
+}
+
+// NewMetricsPurger returns a purger; a retention <= 0 disables the background
+// job but still allows manual purges.
+func NewMetricsPurger(metricRepo domain.MetricRepository, execRepo domain.ExecutionRepository, retention, interval time.Duration) *MetricsPurger {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &MetricsPurger{
+		metricRepo: metricRepo,
+		execRepo:   execRepo,
+		retention:  retention,
+		interval:   interval,
+		done:       make(chan struct{}),
+	}
+}
+
+func (p *MetricsPurger) Start() {
+	if p.retention <= 0 {
+		log.Println("[MetricsPurger] Disabled (METRICS_AGG_RETENTION_DAYS=0)")
+		return
+	}
+	p.ticker = time.NewTicker(p.interval)
+	log.Printf("[MetricsPurger] Started (every %s, retention %s)", p.interval, p.retention)
+
+	go func() {
+		p.purge()
+		for {
+			select {
+			case <-p.ticker.C:
+				p.purge()
+			case <-p.done:
+				return
+			}
+		}
+	}()
+}
+
+func (p *MetricsPurger) Stop() {
+	p.stopOnce.Do(func() {
+		if p.ticker != nil {
+			p.ticker.Stop()
+		}
+		close(p.done)
+		log.Println("[MetricsPurger] Stopped")
+	})
+}
+
+// PurgeOlderThan removes aggregated metrics of executions started more than
+// age ago. A zero age uses the configured retention.
+func (p *MetricsPurger) PurgeOlderThan(age time.Duration) (*MetricsPurge, error) {
+	if age == 0 {
+		age = p.retention
+	}
+	if age <= 0 {
+		return nil, domain.NewValidationError(map[string]string{
+			"older_than": "Must be a positive number of days",
+		})
+	}
+
+	before := time.Now().Add(-age)
+	rows, err := p.metricRepo.PurgeAggregated(before)
+	if err != nil {
+		return nil, err
+	}
+	execs, err := p.execRepo.PurgeDeleted(before)
+	if err != nil {
+		return nil, err
+	}
+	return &MetricsPurge{Before: before, AggregatedRows: rows, Executions: execs}, nil
+}
+
+func (p *MetricsPurger) purge() {
+	result, err := p.PurgeOlderThan(p.retention)
+	if err != nil {
+		log.Printf("[MetricsPurger] Failed to purge aggregated metrics: %v", err)
+		return
+	}
+	if result.AggregatedRows > 0 || result.Executions > 0 {
+		log.Printf("[MetricsPurger] Removed %d aggregated rows and %d deleted executions older than %s",
+			result.AggregatedRows, result.Executions, result.Before.Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,18 @@
+package app
+
+import "github.com/willianpsouza/StressTestPlatform/internal/domain"
+
+// Notifier is told when an execution finishes. Notify must not block: the
+// delivery happens in the background.
+type Notifier interface {
+	Notify(test *domain.Test, execution *domain.TestExecution)
+}
+
+// Notifiers dispatches each event to every notifier in turn.
+type Notifiers []Notifier
+
+func (ns Notifiers) Notify(test *domain.Test, execution *domain.TestExecution) {
+	for _, n := range ns {
+		n.Notify(test, execution)
+	}
+}
@@ -0,0 +1,131 @@
+package app
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/pkg/config"
+)
+
+// Argon2Params are the Argon2id cost parameters encoded into each hash.
+type Argon2Params struct {
+	Time    uint32
+	Memory  uint32 // KiB
+	Threads uint8
+}
+
+const (
+	argon2SaltLen = 16
+	argon2KeyLen  = 32
+)
+
+var DefaultArgon2Params = Argon2Params{Time: 3, Memory: 64 * 1024, Threads: 2}
+
+func Argon2ParamsFromConfig(cfg config.PasswordConfig) Argon2Params {
+	p := DefaultArgon2Params
+	if cfg.Argon2Time > 0 {
+		p.Time = uint32(cfg.Argon2Time)
+	}
+	if cfg.Argon2MemoryKB > 0 {
+		p.Memory = uint32(cfg.Argon2MemoryKB)
+	}
+	if cfg.Argon2Threads > 0 && cfg.Argon2Threads <= 255 {
+		p.Threads = uint8(cfg.Argon2Threads)
+	}
+	return p
+}
+
+// HashPassword hashes with the default parameters.
+func HashPassword(password string) (string, error) {
+	return HashPasswordWithParams(password, DefaultArgon2Params)
+}
+
+// HashPasswordWithParams returns a PHC string:
+// $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<hash>
+func HashPasswordWithParams(password string, p Argon2Params) (string, error) {
+	salt := make([]byte, argon2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	hash := argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, argon2KeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, p.Memory, p.Time, p.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(hash),
+	), nil
+}
+
+// VerifyPassword accepts both PHC strings and the legacy "salt$hash" format.
+func VerifyPassword(password, encoded string) bool {
+	p, salt, storedHash, err := decodePasswordHash(encoded)
+	if err != nil {
+		return false
+	}
+	computedHash := argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, uint32(len(storedHash)))
+	return subtle.ConstantTimeCompare(computedHash, storedHash) == 1
+}
+
+// passwordNeedsRehash reports whether encoded is legacy or uses parameters
+// other than target.
+func passwordNeedsRehash(encoded string, target Argon2Params) bool {
+	if !strings.HasPrefix(encoded, "$argon2id$") {
+		return true
+	}
+	p, _, _, err := decodePasswordHash(encoded)
+	return err != nil || p != target
+}
+
+func decodePasswordHash(encoded string) (Argon2Params, []byte, []byte, error) {
+	if !strings.HasPrefix(encoded, "$") {
+		return decodeLegacyPasswordHash(encoded)
+	}
+
+	// "", "argon2id", "v=19", "m=..,t=..,p=..", salt, hash
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return Argon2Params{}, nil, nil, fmt.Errorf("unsupported password hash format")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return Argon2Params{}, nil, nil, fmt.Errorf("unsupported argon2 version")
+	}
+
+	var p Argon2Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Time, &p.Threads); err != nil {
+		return Argon2Params{}, nil, nil, fmt.Errorf("invalid argon2 parameters: %w", err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return Argon2Params{}, nil, nil, err
+	}
+	hash, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(hash) == 0 {
+		return Argon2Params{}, nil, nil, fmt.Errorf("invalid password hash")
+	}
+	return p, salt, hash, nil
+}
+
+// decodeLegacyPasswordHash reads the original "salt$hash" format, which was
+// always produced with DefaultArgon2Params.
+func decodeLegacyPasswordHash(encoded string) (Argon2Params, []byte, []byte, error) {
+	parts := strings.SplitN(encoded, "$", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return Argon2Params{}, nil, nil, fmt.Errorf("invalid password hash")
+	}
+	salt, err := base64.StdEncoding.DecodeString(parts[0])
+	if err != nil {
+		return Argon2Params{}, nil, nil, err
+	}
+	hash, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return Argon2Params{}, nil, nil, err
+	}
+	return DefaultArgon2Params, salt, hash, nil
+}
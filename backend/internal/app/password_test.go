@@ -0,0 +1,140 @@
+package app
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/argon2"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+// legacyHash builds a hash in the original "salt$hash" format.
+func legacyHash(password string) string {
+	salt := []byte("0123456789abcdef")
+	p := DefaultArgon2Params
+	hash := argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, argon2KeyLen)
+	return base64.StdEncoding.EncodeToString(salt) + "$" + base64.StdEncoding.EncodeToString(hash)
+}
+
+func TestVerifyPassword(t *testing.T) {
+	params := Argon2ParamsFromConfig(testPasswordConfig)
+	phc, err := HashPasswordWithParams(testPassword, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy := legacyHash(testPassword)
+
+	tests := []struct {
+		name     string
+		password string
+		encoded  string
+		want     bool
+	}{
+		{"phc round trip", testPassword, phc, true},
+		{"phc wrong password", "wrong password", phc, false},
+		{"legacy", testPassword, legacy, true},
+		{"legacy wrong password", "wrong password", legacy, false},
+		{"empty hash", testPassword, "", false},
+		{"other algorithm", testPassword, strings.Replace(phc, "$argon2id$", "$argon2i$", 1), false},
+		{"other version", testPassword, strings.Replace(phc, "$v=19$", "$v=16$", 1), false},
+		{"malformed parameters", testPassword, strings.Replace(phc, "m=", "x=", 1), false},
+		{"truncated", testPassword, phc[:strings.LastIndex(phc, "$")], false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyPassword(tt.password, tt.encoded); got != tt.want {
+				t.Errorf("VerifyPassword = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashPasswordEncodesParams(t *testing.T) {
+	params := Argon2Params{Time: 2, Memory: 16 * 1024, Threads: 1}
+	encoded, err := HashPasswordWithParams(testPassword, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(encoded, "$argon2id$v=19$m=16384,t=2,p=1$") {
+		t.Errorf("hash %q does not encode the parameters", encoded)
+	}
+	if other, _ := HashPasswordWithParams(testPassword, params); other == encoded {
+		t.Error("two hashes of the same password are equal; salt not random")
+	}
+}
+
+func TestPasswordNeedsRehash(t *testing.T) {
+	params := Argon2ParamsFromConfig(testPasswordConfig)
+	current, _ := HashPasswordWithParams(testPassword, params)
+	weaker, _ := HashPasswordWithParams(testPassword, Argon2Params{Time: 1, Memory: 4 * 1024, Threads: 1})
+
+	tests := []struct {
+		name    string
+		encoded string
+		want    bool
+	}{
+		{"current params", current, false},
+		{"other params", weaker, true},
+		{"legacy", legacyHash(testPassword), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := passwordNeedsRehash(tt.encoded, params); got != tt.want {
+				t.Errorf("passwordNeedsRehash = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoginRehashesPassword(t *testing.T) {
+	tests := []struct {
+		name string
+		hash string
+	}{
+		{"legacy", legacyHash(testPassword)},
+		{"outdated params", mustHash(t, Argon2Params{Time: 1, Memory: 4 * 1024, Threads: 1})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newAuthFixture(t)
+			f.user.PasswordHash = tt.hash
+			f.users.Update(f.user)
+
+			f.login(t)
+
+			stored, _ := f.users.GetByID(f.user.ID)
+			if passwordNeedsRehash(stored.PasswordHash, f.svc.passwordParams) {
+				t.Errorf("stored hash %q was not upgraded", stored.PasswordHash)
+			}
+			if !VerifyPassword(testPassword, stored.PasswordHash) {
+				t.Error("upgraded hash does not verify")
+			}
+		})
+	}
+}
+
+func TestLoginWrongPasswordKeepsHash(t *testing.T) {
+	f := newAuthFixture(t)
+	legacy := legacyHash(testPassword)
+	f.user.PasswordHash = legacy
+	f.users.Update(f.user)
+
+	_, _, err := f.svc.Login(domain.LoginInput{Email: f.user.Email, Password: "wrong password"}, "", "")
+	if errorCode(err) != "UNAUTHORIZED" {
+		t.Errorf("Login = %v, want UNAUTHORIZED", err)
+	}
+	if stored, _ := f.users.GetByID(f.user.ID); stored.PasswordHash != legacy {
+		t.Error("hash was rewritten after a failed login")
+	}
+}
+
+func mustHash(t *testing.T, p Argon2Params) string {
+	t.Helper()
+	hash, err := HashPasswordWithParams(testPassword, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash
+}
@@ -0,0 +1,97 @@
+package app
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+	"github.com/willianpsouza/StressTestPlatform/internal/pkg/config"
+)
+
+const (
+	minMemoryLimitMB = 64
+	maxCPULimit      = 256
+)
+
+// resourceLimits caps a single k6 run. Zero values mean unlimited.
+type resourceLimits struct {
+	MemoryMB int
+	CPUs     float64
+}
+
+// validateResourceLimits checks per-test overrides; nil keeps the configured
+// default. Overrides may not exceed a configured default.
+func validateResourceLimits(memoryMB *int, cpus *float64, cfg config.K6Config) error {
+	if memoryMB != nil {
+		if *memoryMB < minMemoryLimitMB {
+			return domain.NewValidationError(map[string]string{
+				"max_memory_mb": fmt.Sprintf("Must be at least %d", minMemoryLimitMB),
+			})
+		}
+		if cfg.MaxMemoryMB > 0 && *memoryMB > cfg.MaxMemoryMB {
+			return domain.NewValidationError(map[string]string{
+				"max_memory_mb": fmt.Sprintf("Must be at most %d", cfg.MaxMemoryMB),
+			})
+		}
+	}
+	if cpus != nil {
+		if *cpus <= 0 || *cpus > maxCPULimit {
+			return domain.NewValidationError(map[string]string{
+				"max_cpus": fmt.Sprintf("Must be greater than 0 and at most %d", maxCPULimit),
+			})
+		}
+		if cfg.MaxCPUs > 0 && *cpus > cfg.MaxCPUs {
+			return domain.NewValidationError(map[string]string{
+				"max_cpus": fmt.Sprintf("Must be at most %g", cfg.MaxCPUs),
+			})
+		}
+	}
+	return nil
+}
+
+// resourceLimitsFor resolves the limits of a run: the test's overrides, or
+// the configured defaults.
+func resourceLimitsFor(test *domain.Test, cfg config.K6Config) resourceLimits {
+	limits := resourceLimits{MemoryMB: cfg.MaxMemoryMB, CPUs: cfg.MaxCPUs}
+	if test.MaxMemoryMB != nil {
+		limits.MemoryMB = *test.MaxMemoryMB
+	}
+	if test.MaxCPUs != nil {
+		limits.CPUs = *test.MaxCPUs
+	}
+	return limits
+}
+
+func (l resourceLimits) enabled() bool {
+	return l.MemoryMB > 0 || l.CPUs > 0
+}
+
+// env returns the Go runtime settings that keep k6 (a Go binary) within the
+// limits on any platform: GOMAXPROCS bounds the CPUs it schedules on and
+// GOMEMLIMIT makes the GC work harder before the hard memory cap is hit.
+func (l resourceLimits) env() []string {
+	var env []string
+	if l.CPUs > 0 {
+		env = append(env, "GOMAXPROCS="+strconv.Itoa(max(1, int(math.Ceil(l.CPUs)))))
+	}
+	if l.MemoryMB > 0 {
+		env = append(env, fmt.Sprintf("GOMEMLIMIT=%dMiB", l.MemoryMB*9/10))
+	}
+	return env
+}
+
+func (l resourceLimits) String() string {
+	return fmt.Sprintf("memory=%dMB cpus=%g", l.MemoryMB, l.CPUs)
+}
+
+func (l resourceLimits) memoryExceededMessage() string {
+	return fmt.Sprintf("k6 exceeded the memory limit of %d MB and was stopped", l.MemoryMB)
+}
+
+// outOfMemory reports whether k6's stderr shows the Go runtime giving up on
+// an allocation.
+func outOfMemory(stderr string) bool {
+	return strings.Contains(stderr, "runtime: out of memory")
+}
@@ -0,0 +1,95 @@
+package app
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/google/uuid"
+)
+
+// runCgroup is a cgroup v2 child created for one run. The parent directory
+// must be writable by the backend and have the memory and cpu controllers
+// enabled in its cgroup.subtree_control.
+type runCgroup struct {
+	dir string
+	fd  *os.File
+}
+
+func newRunCgroup(root string, execID uuid.UUID, limits resourceLimits) (*runCgroup, error) {
+	dir := filepath.Join(root, "k6-"+execID.String())
+	if err := os.Mkdir(dir, 0755); err != nil {
+		return nil, fmt.Errorf("create cgroup: %w", err)
+	}
+
+	cg := &runCgroup{dir: dir}
+	if limits.MemoryMB > 0 {
+		if err := cg.write("memory.max", fmt.Sprintf("%d", int64(limits.MemoryMB)*1024*1024)); err != nil {
+			cg.remove()
+			return nil, err
+		}
+		// Not every host has swap accounting; the memory cap still applies
+		cg.write("memory.swap.max", "0")
+	}
+	if limits.CPUs > 0 {
+		const period = 100000
+		if err := cg.write("cpu.max", fmt.Sprintf("%d %d", int64(limits.CPUs*period), period)); err != nil {
+			cg.remove()
+			return nil, err
+		}
+	}
+
+	fd, err := os.Open(dir)
+	if err != nil {
+		cg.remove()
+		return nil, fmt.Errorf("open cgroup: %w", err)
+	}
+	cg.fd = fd
+	return cg, nil
+}
+
+func (c *runCgroup) write(file, value string) error {
+	if err := os.WriteFile(filepath.Join(c.dir, file), []byte(value), 0644); err != nil {
+		return fmt.Errorf("set %s: %w", file, err)
+	}
+	return nil
+}
+
+// attach starts the command directly inside the cgroup.
+func (c *runCgroup) attach(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.UseCgroupFD = true
+	cmd.SysProcAttr.CgroupFD = int(c.fd.Fd())
+}
+
+// oomKilled reports whether the kernel killed a process of the cgroup for
+// exceeding memory.max.
+func (c *runCgroup) oomKilled() bool {
+	f, err := os.Open(filepath.Join(c.dir, "memory.events"))
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if count, ok := strings.CutPrefix(scanner.Text(), "oom_kill "); ok {
+			return count != "0"
+		}
+	}
+	return false
+}
+
+// remove deletes the cgroup once its processes have exited.
+func (c *runCgroup) remove() {
+	if c.fd != nil {
+		c.fd.Close()
+	}
+	os.Remove(c.dir)
+}
@@ -0,0 +1,24 @@
+//go:build !linux
+
+package app
+
+import (
+	"errors"
+	"os/exec"
+
+	"github.com/google/uuid"
+)
+
+// runCgroup is only implemented on Linux; elsewhere runs are limited through
+// the Go runtime env vars alone.
+type runCgroup struct{}
+
+func newRunCgroup(root string, execID uuid.UUID, limits resourceLimits) (*runCgroup, error) {
+	return nil, errors.New("cgroup limits require Linux")
+}
+
+func (c *runCgroup) attach(cmd *exec.Cmd) {}
+
+func (c *runCgroup) oomKilled() bool { return false }
+
+func (c *runCgroup) remove() {}
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+const (
+	maxExecutionRetries = 5
+	defaultRetryBackoff = 10 * time.Second
+	minRetryBackoff     = time.Second
+	maxRetryBackoff     = 5 * time.Minute
+)
+
+// k6 exit codes that are never retried: a rerun would fail the same way.
+var nonRetryableExitCodes = map[int]bool{
+	k6ExitThresholdsFailed: true,
+	k6ExitInvalidConfig:    true,
+	k6ExitScriptAborted:    true, // test.abort()
+}
+
+// validateRetryPolicy accepts 0-5 retries and an empty backoff (default) or a
+// duration between 1s and 5m.
+func validateRetryPolicy(maxRetries int, backoff string) error {
+	if maxRetries < 0 || maxRetries > maxExecutionRetries {
+		return domain.NewValidationError(map[string]string{
+			"max_retries": fmt.Sprintf("Must be between 0 and %d", maxExecutionRetries),
+		})
+	}
+	if backoff == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(backoff)
+	if err != nil || d < minRetryBackoff || d > maxRetryBackoff {
+		return domain.NewValidationError(map[string]string{
+			"retry_backoff": fmt.Sprintf("Must be a duration between %s and %s", minRetryBackoff, maxRetryBackoff),
+		})
+	}
+	return nil
+}
+
+// retryPolicy resolves the execution's retry settings, falling back to the
+// test defaults for anything the execution did not override.
+func retryPolicy(execution *domain.TestExecution, test *domain.Test) (int, time.Duration) {
+	maxRetries := test.MaxRetries
+	if execution.MaxRetries != nil {
+		maxRetries = *execution.MaxRetries
+	}
+	backoffStr := test.RetryBackoff
+	if execution.RetryBackoff != nil {
+		backoffStr = *execution.RetryBackoff
+	}
+	backoff, err := time.ParseDuration(backoffStr)
+	if err != nil || backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+	return maxRetries, backoff
+}
+
+// retryableRunError reports whether a failed k6 run may be retried. Only
+// non-zero exits are; missing binaries and the codes in
+// nonRetryableExitCodes are not. Timeouts and cancellations are checked by
+// the caller through the run context.
+func retryableRunError(err error) bool {
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok {
+		return false
+	}
+	code := exitErr.ExitCode()
+	return code > 0 && !nonRetryableExitCodes[code]
+}
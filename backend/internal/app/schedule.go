@@ -1,24 +1,82 @@
 package app
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/robfig/cron/v3"
 
 	"github.com/willianpsouza/StressTestPlatform/internal/domain"
 )
 
-func nextCronRun(expression string) *time.Time {
-	parser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
-	sched, err := parser.Parse(expression)
+func nextCronRun(expression, timezone string) *time.Time {
+	t, err := getNextCronRun(expression, timezone)
 	if err != nil {
 		return nil
 	}
-	t := sched.Next(time.Now())
 	return &t
 }
 
+// scheduleLocation resolves a schedule's IANA timezone; empty means UTC.
+func scheduleLocation(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return time.UTC, nil
+	}
+	if timezone == "Local" {
+		return nil, fmt.Errorf("timezone must be an IANA name")
+	}
+	return time.LoadLocation(timezone)
+}
+
+func validateTimezone(timezone string) error {
+	if _, err := scheduleLocation(timezone); err != nil {
+		return domain.NewValidationError(map[string]string{
+			"timezone": "Must be a valid IANA timezone (e.g. UTC, America/Sao_Paulo)",
+		})
+	}
+	return nil
+}
+
+func validateCronExpression(expression string) error {
+	if _, err := cronParser.Parse(expression); err != nil {
+		return domain.NewValidationError(map[string]string{
+			"cron_expression": fmt.Sprintf("Invalid cron expression: %v", err),
+		})
+	}
+	return nil
+}
+
+// minScheduleInterval is the shortest period accepted for INTERVAL schedules.
+const minScheduleInterval = 60
+
+func nextIntervalRun(seconds int, from time.Time) *time.Time {
+	t := from.Add(time.Duration(seconds) * time.Second)
+	return &t
+}
+
+func validateScheduleInterval(seconds *int) error {
+	if seconds == nil || *seconds < minScheduleInterval {
+		return domain.NewValidationError(map[string]string{
+			"interval_seconds": fmt.Sprintf("Interval must be at least %d seconds", minScheduleInterval),
+		})
+	}
+	return nil
+}
+
+func validateScheduleLimits(endAt *time.Time, maxRuns *int) error {
+	if endAt != nil && !endAt.After(time.Now()) {
+		return domain.NewValidationError(map[string]string{
+			"end_at": "End date must be in the future",
+		})
+	}
+	if maxRuns != nil && *maxRuns < 1 {
+		return domain.NewValidationError(map[string]string{
+			"max_runs": "Max runs must be at least 1",
+		})
+	}
+	return nil
+}
+
 type ScheduleService struct {
 	scheduleRepo domain.ScheduleRepository
 	testRepo     domain.TestRepository
@@ -41,10 +99,15 @@ func (s *ScheduleService) Create(userID uuid.UUID, isRoot bool, input domain.Cre
 		return nil, domain.NewForbiddenError("Access denied")
 	}
 
-	if input.ScheduleType == domain.ScheduleTypeRecurring && (input.CronExpression == nil || *input.CronExpression == "") {
-		return nil, domain.NewValidationError(map[string]string{
-			"cron_expression": "Cron expression is required for recurring schedules",
-		})
+	if input.ScheduleType == domain.ScheduleTypeRecurring {
+		if input.CronExpression == nil || *input.CronExpression == "" {
+			return nil, domain.NewValidationError(map[string]string{
+				"cron_expression": "Cron expression is required for recurring schedules",
+			})
+		}
+		if err := validateCronExpression(*input.CronExpression); err != nil {
+			return nil, err
+		}
 	}
 
 	if input.ScheduleType == domain.ScheduleTypeOnce && input.NextRunAt == nil {
@@ -53,6 +116,23 @@ func (s *ScheduleService) Create(userID uuid.UUID, isRoot bool, input domain.Cre
 		})
 	}
 
+	timezone := input.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	if err := validateTimezone(timezone); err != nil {
+		return nil, err
+	}
+
+	if input.ScheduleType == domain.ScheduleTypeInterval {
+		if err := validateScheduleInterval(input.IntervalSeconds); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateScheduleLimits(input.EndAt, input.MaxRuns); err != nil {
+		return nil, err
+	}
+
 	vus := input.VUs
 	if vus <= 0 {
 		vus = test.DefaultVUs
@@ -66,19 +146,26 @@ func (s *ScheduleService) Create(userID uuid.UUID, isRoot bool, input domain.Cre
 	nextRunAt := input.NextRunAt
 	if input.ScheduleType == domain.ScheduleTypeRecurring && input.CronExpression != nil {
 		if nextRunAt == nil {
-			nextRunAt = nextCronRun(*input.CronExpression)
+			nextRunAt = nextCronRun(*input.CronExpression, timezone)
 		}
 	}
+	if input.ScheduleType == domain.ScheduleTypeInterval && nextRunAt == nil {
+		nextRunAt = nextIntervalRun(*input.IntervalSeconds, time.Now())
+	}
 
 	schedule := &domain.Schedule{
-		TestID:         input.TestID,
-		UserID:         userID,
-		ScheduleType:   input.ScheduleType,
-		CronExpression: input.CronExpression,
-		NextRunAt:      nextRunAt,
-		VUs:            vus,
-		Duration:       duration,
-		Status:         domain.ScheduleStatusActive,
+		TestID:          input.TestID,
+		UserID:          userID,
+		ScheduleType:    input.ScheduleType,
+		CronExpression:  input.CronExpression,
+		IntervalSeconds: input.IntervalSeconds,
+		Timezone:        timezone,
+		EndAt:           input.EndAt,
+		MaxRuns:         input.MaxRuns,
+		NextRunAt:       nextRunAt,
+		VUs:             vus,
+		Duration:        duration,
+		Status:          domain.ScheduleStatusActive,
 	}
 
 	if err := s.scheduleRepo.Create(schedule); err != nil {
@@ -98,6 +185,14 @@ func (s *ScheduleService) GetByID(id uuid.UUID, userID uuid.UUID, isRoot bool) (
 	return schedule, nil
 }
 
+// ListRuns returns the dispatch history of a schedule, newest first.
+func (s *ScheduleService) ListRuns(id uuid.UUID, userID uuid.UUID, isRoot bool, pagination domain.Pagination) ([]domain.ScheduleRun, int64, error) {
+	if _, err := s.GetByID(id, userID, isRoot); err != nil {
+		return nil, 0, err
+	}
+	return s.scheduleRepo.ListRuns(id, pagination)
+}
+
 func (s *ScheduleService) Update(id uuid.UUID, userID uuid.UUID, isRoot bool, input domain.UpdateScheduleInput) (*domain.Schedule, error) {
 	schedule, err := s.scheduleRepo.GetByID(id)
 	if err != nil {
@@ -107,12 +202,45 @@ func (s *ScheduleService) Update(id uuid.UUID, userID uuid.UUID, isRoot bool, in
 		return nil, domain.NewForbiddenError("Access denied")
 	}
 
+	if input.Timezone != nil {
+		if err := validateTimezone(*input.Timezone); err != nil {
+			return nil, err
+		}
+		schedule.Timezone = *input.Timezone
+	}
 	if input.CronExpression != nil {
+		if schedule.ScheduleType == domain.ScheduleTypeRecurring {
+			if err := validateCronExpression(*input.CronExpression); err != nil {
+				return nil, err
+			}
+		}
 		schedule.CronExpression = input.CronExpression
 	}
+	if (input.Timezone != nil || input.CronExpression != nil) &&
+		schedule.ScheduleType == domain.ScheduleTypeRecurring && schedule.CronExpression != nil {
+		schedule.NextRunAt = nextCronRun(*schedule.CronExpression, schedule.Timezone)
+	}
+	if input.IntervalSeconds != nil && schedule.ScheduleType == domain.ScheduleTypeInterval {
+		if err := validateScheduleInterval(input.IntervalSeconds); err != nil {
+			return nil, err
+		}
+		schedule.IntervalSeconds = input.IntervalSeconds
+		schedule.NextRunAt = nextIntervalRun(*input.IntervalSeconds, time.Now())
+	}
 	if input.NextRunAt != nil {
 		schedule.NextRunAt = input.NextRunAt
 	}
+	if input.EndAt != nil || input.MaxRuns != nil {
+		if err := validateScheduleLimits(input.EndAt, input.MaxRuns); err != nil {
+			return nil, err
+		}
+		if input.EndAt != nil {
+			schedule.EndAt = input.EndAt
+		}
+		if input.MaxRuns != nil {
+			schedule.MaxRuns = input.MaxRuns
+		}
+	}
 	if input.VUs != nil {
 		schedule.VUs = *input.VUs
 	}
@@ -164,9 +292,12 @@ func (s *ScheduleService) Resume(id uuid.UUID, userID uuid.UUID, isRoot bool) (*
 
 	schedule.Status = domain.ScheduleStatusActive
 
-	// Recalculate next_run_at for recurring schedules
+	// Recalculate next_run_at for recurring and interval schedules
 	if schedule.ScheduleType == domain.ScheduleTypeRecurring && schedule.CronExpression != nil {
-		schedule.NextRunAt = nextCronRun(*schedule.CronExpression)
+		schedule.NextRunAt = nextCronRun(*schedule.CronExpression, schedule.Timezone)
+	}
+	if schedule.ScheduleType == domain.ScheduleTypeInterval && schedule.IntervalSeconds != nil {
+		schedule.NextRunAt = nextIntervalRun(*schedule.IntervalSeconds, time.Now())
 	}
 
 	if err := s.scheduleRepo.Update(schedule); err != nil {
@@ -0,0 +1,120 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+func TestValidateCronExpression(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		wantErr    bool
+	}{
+		{name: "every minute", expression: "* * * * *"},
+		{name: "daily", expression: "0 9 * * *"},
+		{name: "ranges and steps", expression: "*/15 8-18 * * 1-5"},
+		{name: "named fields", expression: "0 0 1 JAN MON"},
+		{name: "empty", expression: "", wantErr: true},
+		{name: "too few fields", expression: "0 9 * *", wantErr: true},
+		{name: "seconds field", expression: "0 0 9 * * *", wantErr: true},
+		{name: "out of range", expression: "60 * * * *", wantErr: true},
+		{name: "garbage", expression: "every day", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCronExpression(tt.expression)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if details := validationDetails(t, err); details["cron_expression"] == "" {
+				t.Errorf("missing cron_expression detail: %v", details)
+			}
+		})
+	}
+}
+
+func TestCronNextAfter(t *testing.T) {
+	from := time.Date(2026, 3, 10, 11, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		expression string
+		timezone   string
+		want       time.Time
+		wantErr    bool
+	}{
+		{name: "utc default", expression: "0 9 * * *", want: time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC)},
+		{name: "later today", expression: "0 12 * * *", timezone: "UTC", want: time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)},
+		{name: "schedule timezone", expression: "0 9 * * *", timezone: "America/Sao_Paulo", want: time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)},
+		{name: "invalid timezone", expression: "0 9 * * *", timezone: "Mars/Olympus", wantErr: true},
+		{name: "invalid expression", expression: "nope", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cronNextAfter(tt.expression, tt.timezone, from)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("got %v, want %v", got.UTC(), tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateScheduleOwnership(t *testing.T) {
+	owner := uuid.New()
+	test := &domain.Test{ID: uuid.New(), UserID: owner, DefaultVUs: 1, DefaultDuration: "1m"}
+	nextRun := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		caller  uuid.UUID
+		isRoot  bool
+		wantErr bool
+	}{
+		{"owner", owner, false, false},
+		{"root schedules another user's test", uuid.New(), true, false},
+		{"regular user schedules another user's test", uuid.New(), false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedules := &fakeScheduleRepo{}
+			svc := NewScheduleService(schedules, newFakeTestRepo(test))
+
+			schedule, err := svc.Create(tt.caller, tt.isRoot, domain.CreateScheduleInput{
+				TestID:       test.ID,
+				ScheduleType: domain.ScheduleTypeOnce,
+				NextRunAt:    &nextRun,
+			})
+			if tt.wantErr {
+				if errorCode(err) != "FORBIDDEN" || len(schedules.schedules) != 0 {
+					t.Errorf("Create = %v with %d schedules stored, want FORBIDDEN and none", err, len(schedules.schedules))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			if schedule.UserID != tt.caller || schedule.TestID != test.ID {
+				t.Errorf("schedule user %s test %s, want %s and %s", schedule.UserID, schedule.TestID, tt.caller, test.ID)
+			}
+		})
+	}
+}
@@ -1,47 +1,81 @@
 package app
 
 import (
+	"fmt"
 	"log"
+	"os"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/robfig/cron/v3"
 
 	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+	"github.com/willianpsouza/StressTestPlatform/internal/pkg/config"
+)
+
+const (
+	// missedRunGrace is how late a due schedule may be before its run counts
+	// as missed; it covers the poll interval and slow ticks.
+	missedRunGrace = time.Minute
+
+	schedulerPollInterval = 10 * time.Second
+	// leaderLockTTL lets another instance take over within a few polls when
+	// the leader dies without releasing the lock.
+	leaderLockTTL = 3 * schedulerPollInterval
 )
 
 type Scheduler struct {
 	scheduleRepo domain.ScheduleRepository
 	execRepo     domain.ExecutionRepository
+	testRepo     domain.TestRepository
 	runner       *K6Runner
+	starts       *StartLimiter
+	config       config.SchedulerConfig
+	lock         domain.LeaderLock
+	instanceID   string
+	leader       bool
 	ticker       *time.Ticker
 	done         chan struct{}
+	stopped      chan struct{}
 	stopOnce     sync.Once
 }
 
 func NewScheduler(
 	scheduleRepo domain.ScheduleRepository,
 	execRepo domain.ExecutionRepository,
+	testRepo domain.TestRepository,
 	runner *K6Runner,
+	starts *StartLimiter,
+	lock domain.LeaderLock,
+	schedulerConfig config.SchedulerConfig,
 ) *Scheduler {
 	return &Scheduler{
 		scheduleRepo: scheduleRepo,
 		execRepo:     execRepo,
+		testRepo:     testRepo,
 		runner:       runner,
+		starts:       starts,
+		config:       schedulerConfig,
+		lock:         lock,
+		instanceID:   schedulerInstanceID(),
 		done:         make(chan struct{}),
+		stopped:      make(chan struct{}),
 	}
 }
 
 func (s *Scheduler) Start() {
-	s.ticker = time.NewTicker(10 * time.Second)
-	log.Println("[Scheduler] Started (polling every 10s)")
+	s.ticker = time.NewTicker(schedulerPollInterval)
+	log.Printf("[Scheduler] Started as %s (polling every %s)", s.instanceID, schedulerPollInterval)
 
 	go func() {
+		defer close(s.stopped)
 		for {
 			select {
 			case <-s.ticker.C:
-				s.poll()
+				s.tick()
 			case <-s.done:
+				s.resign()
 				return
 			}
 		}
@@ -54,10 +88,60 @@ func (s *Scheduler) Stop() {
 			s.ticker.Stop()
 		}
 		close(s.done)
+		if s.ticker != nil {
+			<-s.stopped
+		}
 		log.Println("[Scheduler] Stopped")
 	})
 }
 
+// tick polls for due schedules if this instance holds the leader lock.
+func (s *Scheduler) tick() {
+	if s.isLeader() {
+		s.poll()
+	}
+}
+
+// isLeader takes or renews the leader lock, so that only one of the backend
+// instances sharing Redis dispatches schedules. Without Redis no instance
+// leads: skipping a poll is safer than firing a schedule twice.
+func (s *Scheduler) isLeader() bool {
+	leader, err := s.lock.Acquire(s.instanceID, leaderLockTTL)
+	if err != nil {
+		log.Printf("[Scheduler] Failed to acquire leader lock: %v", err)
+		leader = false
+	}
+	if leader != s.leader {
+		if leader {
+			log.Printf("[Scheduler] %s is now the leader", s.instanceID)
+		} else {
+			log.Printf("[Scheduler] %s is no longer the leader", s.instanceID)
+		}
+		s.leader = leader
+	}
+	return leader
+}
+
+// resign releases the lock on shutdown so another instance can lead at once
+// instead of waiting for it to expire.
+func (s *Scheduler) resign() {
+	if !s.leader {
+		return
+	}
+	if err := s.lock.Release(s.instanceID); err != nil {
+		log.Printf("[Scheduler] Failed to release leader lock: %v", err)
+	}
+	s.leader = false
+}
+
+func schedulerInstanceID() string {
+	host, _ := os.Hostname()
+	if host == "" {
+		host = "backend"
+	}
+	return host + "-" + uuid.NewString()[:8]
+}
+
 func (s *Scheduler) poll() {
 	schedules, err := s.scheduleRepo.GetDueSchedules()
 	if err != nil {
@@ -65,12 +149,153 @@ func (s *Scheduler) poll() {
 		return
 	}
 
+	now := time.Now()
 	for _, schedule := range schedules {
+		if s.skipMissed(&schedule, now) {
+			continue
+		}
+		if s.inCooldown(&schedule) {
+			continue
+		}
 		s.executeSchedule(&schedule)
 	}
 }
 
+// skipMissed applies SCHEDULE_MISSED_POLICY to a RECURRING/INTERVAL schedule
+// whose next_run_at is well in the past (e.g. after downtime). With "skip" the
+// missed runs are dropped and next_run_at moves to the next future slot; with
+// "run_once" the schedule fires once and advance does the same afterwards.
+func (s *Scheduler) skipMissed(schedule *domain.Schedule, now time.Time) bool {
+	if schedule.ScheduleType == domain.ScheduleTypeOnce || schedule.NextRunAt == nil ||
+		now.Sub(*schedule.NextRunAt) <= missedRunGrace {
+		return false
+	}
+
+	missed := countDueRuns(schedule, now)
+	if s.config.MissedPolicy != config.MissedPolicySkip {
+		if missed > 1 {
+			log.Printf("[Scheduler] Schedule %s missed %d runs, running once to catch up", schedule.ID, missed)
+		}
+		return false
+	}
+
+	log.Printf("[Scheduler] Schedule %s missed %d runs, skipping to the next slot", schedule.ID, missed)
+	s.recordRun(schedule, domain.ScheduleRunMissed, nil, fmt.Errorf("missed %d runs while the scheduler was not running", missed))
+	s.advance(schedule, now)
+	if err := s.scheduleRepo.Update(schedule); err != nil {
+		log.Printf("[Scheduler] Failed to update schedule %s: %v", schedule.ID, err)
+	}
+	return true
+}
+
+// countDueRuns counts the occurrences from next_run_at up to now, capped so a
+// long outage with a tight cron does not loop for long.
+func countDueRuns(schedule *domain.Schedule, now time.Time) int {
+	const maxCount = 10000
+	if schedule.NextRunAt == nil || schedule.NextRunAt.After(now) {
+		return 0
+	}
+
+	switch schedule.ScheduleType {
+	case domain.ScheduleTypeInterval:
+		if schedule.IntervalSeconds == nil || *schedule.IntervalSeconds <= 0 {
+			return 1
+		}
+		n := int(now.Sub(*schedule.NextRunAt)/(time.Duration(*schedule.IntervalSeconds)*time.Second)) + 1
+		return min(n, maxCount)
+	case domain.ScheduleTypeRecurring:
+		if schedule.CronExpression == nil {
+			return 1
+		}
+		count := 1
+		t := *schedule.NextRunAt
+		for count < maxCount {
+			next, err := cronNextAfter(*schedule.CronExpression, schedule.Timezone, t)
+			if err != nil || next.After(now) {
+				break
+			}
+			t = next
+			count++
+		}
+		return count
+	}
+	return 1
+}
+
+// inCooldown reports whether the schedule's test is still cooling down. The
+// schedule is left untouched so it stays due and fires on a later poll.
+func (s *Scheduler) inCooldown(schedule *domain.Schedule) bool {
+	test, err := s.testRepo.GetByID(schedule.TestID)
+	if err != nil {
+		return false
+	}
+	eligibleAt, err := cooldownUntil(s.execRepo, test)
+	if err != nil {
+		log.Printf("[Scheduler] Failed to check cooldown for schedule %s: %v", schedule.ID, err)
+		return false
+	}
+	if eligibleAt != nil {
+		log.Printf("[Scheduler] Schedule %s deferred: test %s in cooldown until %s",
+			schedule.ID, schedule.TestID, eligibleAt.Format(time.RFC3339))
+		return true
+	}
+	return false
+}
+
+// previousRunActive reports whether the last execution fired by the schedule
+// is still PENDING or RUNNING.
+func (s *Scheduler) previousRunActive(schedule *domain.Schedule) bool {
+	last, err := s.execRepo.GetLatestBySchedule(schedule.ID)
+	if err != nil {
+		log.Printf("[Scheduler] Failed to get latest execution for schedule %s: %v", schedule.ID, err)
+		return false
+	}
+	return last != nil && (last.Status == domain.TestStatusPending || last.Status == domain.TestStatusRunning)
+}
+
+// scheduleExhausted reports whether the schedule has reached its max_runs or
+// its end_at has passed.
+func scheduleExhausted(schedule *domain.Schedule, now time.Time) bool {
+	if schedule.MaxRuns != nil && schedule.RunCount >= *schedule.MaxRuns {
+		return true
+	}
+	return schedule.EndAt != nil && !now.Before(*schedule.EndAt)
+}
+
+// complete marks the schedule COMPLETED and persists it.
+func (s *Scheduler) complete(schedule *domain.Schedule) {
+	log.Printf("[Scheduler] Schedule %s completed after %d runs", schedule.ID, schedule.RunCount)
+	schedule.Status = domain.ScheduleStatusCompleted
+	schedule.NextRunAt = nil
+	if err := s.scheduleRepo.Update(schedule); err != nil {
+		log.Printf("[Scheduler] Failed to update schedule %s: %v", schedule.ID, err)
+	}
+}
+
 func (s *Scheduler) executeSchedule(schedule *domain.Schedule) {
+	if scheduleExhausted(schedule, time.Now()) {
+		s.complete(schedule)
+		return
+	}
+
+	if s.config.SkipIfRunning && schedule.ScheduleType != domain.ScheduleTypeOnce && s.previousRunActive(schedule) {
+		log.Printf("[Scheduler] Skipping schedule %s: previous execution still running", schedule.ID)
+		s.recordRun(schedule, domain.ScheduleRunSkipped, nil, fmt.Errorf("previous execution still running"))
+		s.advance(schedule, time.Now())
+		if err := s.scheduleRepo.Update(schedule); err != nil {
+			log.Printf("[Scheduler] Failed to update schedule %s: %v", schedule.ID, err)
+		}
+		return
+	}
+
+	// A rate-limited schedule stays due and is retried on the next poll
+	if s.config.LimitStarts {
+		if err := s.starts.Take(schedule.UserID); err != nil {
+			log.Printf("[Scheduler] Delaying schedule %s: %v", schedule.ID, err)
+			return
+		}
+	}
+
 	log.Printf("[Scheduler] Executing schedule %s for test %s", schedule.ID, schedule.TestID)
 
 	// Create execution
@@ -85,6 +310,7 @@ func (s *Scheduler) executeSchedule(schedule *domain.Schedule) {
 
 	if err := s.execRepo.Create(exec); err != nil {
 		log.Printf("[Scheduler] Failed to create execution for schedule %s: %v", schedule.ID, err)
+		s.recordRun(schedule, domain.ScheduleRunFailed, nil, err)
 		return
 	}
 
@@ -95,19 +321,49 @@ func (s *Scheduler) executeSchedule(schedule *domain.Schedule) {
 		errMsg := err.Error()
 		exec.ErrorMessage = &errMsg
 		s.execRepo.Update(exec)
+		s.runner.publish(exec)
+		s.recordRun(schedule, domain.ScheduleRunFailed, &exec.ID, err)
+	} else {
+		s.recordRun(schedule, domain.ScheduleRunStarted, &exec.ID, nil)
 	}
 
 	// Update schedule
 	now := time.Now()
 	schedule.LastRunAt = &now
 	schedule.RunCount++
+	if scheduleExhausted(schedule, now) {
+		s.complete(schedule)
+		return
+	}
+	s.advance(schedule, now)
+
+	if err := s.scheduleRepo.Update(schedule); err != nil {
+		log.Printf("[Scheduler] Failed to update schedule %s: %v", schedule.ID, err)
+	}
+}
 
+// recordRun stores the outcome of a dispatch attempt in the schedule's run
+// history. A failure to record it is only logged.
+func (s *Scheduler) recordRun(schedule *domain.Schedule, outcome domain.ScheduleRunOutcome, executionID *uuid.UUID, cause error) {
+	run := &domain.ScheduleRun{ScheduleID: schedule.ID, ExecutionID: executionID, Outcome: outcome}
+	if cause != nil {
+		msg := cause.Error()
+		run.Error = &msg
+	}
+	if err := s.scheduleRepo.AddRun(run); err != nil {
+		log.Printf("[Scheduler] Failed to record %s run of schedule %s: %v", outcome, schedule.ID, err)
+	}
+}
+
+// advance moves the schedule past the tick that fired at now: ONCE schedules
+// complete, recurring and interval schedules get their next run time.
+func (s *Scheduler) advance(schedule *domain.Schedule, now time.Time) {
 	if schedule.ScheduleType == domain.ScheduleTypeOnce {
 		schedule.Status = domain.ScheduleStatusCompleted
 		schedule.NextRunAt = nil
 	} else if schedule.ScheduleType == domain.ScheduleTypeRecurring {
 		if schedule.CronExpression != nil {
-			nextRun, err := getNextCronRun(*schedule.CronExpression)
+			nextRun, err := getNextCronRun(*schedule.CronExpression, schedule.Timezone)
 			if err != nil {
 				log.Printf("[Scheduler] Failed to parse cron for schedule %s: %v", schedule.ID, err)
 				schedule.Status = domain.ScheduleStatusPaused
@@ -115,18 +371,33 @@ func (s *Scheduler) executeSchedule(schedule *domain.Schedule) {
 				schedule.NextRunAt = &nextRun
 			}
 		}
+	} else if schedule.ScheduleType == domain.ScheduleTypeInterval {
+		if schedule.IntervalSeconds != nil && *schedule.IntervalSeconds > 0 {
+			schedule.NextRunAt = nextIntervalRun(*schedule.IntervalSeconds, now)
+		} else {
+			log.Printf("[Scheduler] Schedule %s has no interval, pausing", schedule.ID)
+			schedule.Status = domain.ScheduleStatusPaused
+		}
 	}
+}
 
-	if err := s.scheduleRepo.Update(schedule); err != nil {
-		log.Printf("[Scheduler] Failed to update schedule %s: %v", schedule.ID, err)
-	}
+// cronParser accepts standard five-field expressions.
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+
+// getNextCronRun evaluates the expression in the schedule's timezone, so
+// "0 9 * * *" fires at 9am local to that zone rather than server time.
+func getNextCronRun(expression, timezone string) (time.Time, error) {
+	return cronNextAfter(expression, timezone, time.Now())
 }
 
-func getNextCronRun(expression string) (time.Time, error) {
-	parser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
-	sched, err := parser.Parse(expression)
+func cronNextAfter(expression, timezone string, from time.Time) (time.Time, error) {
+	loc, err := scheduleLocation(timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	sched, err := cronParser.Parse(expression)
 	if err != nil {
 		return time.Time{}, err
 	}
-	return sched.Next(time.Now()), nil
+	return sched.Next(from.In(loc)), nil
 }
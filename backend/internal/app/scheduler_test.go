@@ -0,0 +1,54 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/pkg/config"
+)
+
+func newTestScheduler(lock *fakeLeaderLock) (*Scheduler, *fakeScheduleRepo) {
+	schedules := &fakeScheduleRepo{}
+	return NewScheduler(schedules, newFakeExecRepo(), newFakeTestRepo(), nil, nil, lock, config.SchedulerConfig{}), schedules
+}
+
+func TestSchedulerOnlyLeaderPolls(t *testing.T) {
+	lock := &fakeLeaderLock{}
+	first, firstRepo := newTestScheduler(lock)
+	second, secondRepo := newTestScheduler(lock)
+
+	first.tick()
+	second.tick()
+	first.tick()
+	second.tick()
+	if firstRepo.polls != 2 || secondRepo.polls != 0 {
+		t.Fatalf("polls = %d and %d, want only the lock holder to poll", firstRepo.polls, secondRepo.polls)
+	}
+
+	// A clean shutdown hands over at once
+	first.resign()
+	second.tick()
+	first.tick()
+	if secondRepo.polls != 1 || firstRepo.polls != 2 {
+		t.Errorf("after resign polls = %d and %d, want the second instance to take over", firstRepo.polls, secondRepo.polls)
+	}
+}
+
+func TestSchedulerTakesOverExpiredLock(t *testing.T) {
+	lock := &fakeLeaderLock{}
+	first, _ := newTestScheduler(lock)
+	second, secondRepo := newTestScheduler(lock)
+
+	first.tick()
+	second.tick()
+	if secondRepo.polls != 0 {
+		t.Fatal("second instance polled while the lock was held")
+	}
+
+	// The leader died without releasing the lock
+	lock.expiresAt = time.Now().Add(-time.Second)
+	second.tick()
+	if secondRepo.polls != 1 {
+		t.Errorf("second instance did not take over the expired lock")
+	}
+}
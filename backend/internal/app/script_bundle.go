@@ -0,0 +1,371 @@
+package app
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+const (
+	maxBundleFiles          = 1000
+	defaultBundleEntry      = "main.js"
+	defaultInlineScriptName = "script.js" // filename of a script created from inline content
+)
+
+var bundleExtensions = []string{".zip", ".tar", ".tar.gz", ".tgz"}
+
+// isScriptBundle reports whether filename names an archive holding a main
+// script plus the modules it imports, rather than a single .js script.
+func isScriptBundle(filename string) bool {
+	lower := strings.ToLower(filename)
+	for _, ext := range bundleExtensions {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateScriptUpload checks the name and size of an uploaded script or bundle.
+// The name is only stored and echoed back, never used in a path, but one that
+// could escape a directory is rejected anyway.
+func validateScriptUpload(filename string, size int64, maxBytes int) error {
+	if filename == "" || filepath.Base(filename) != filename ||
+		strings.ContainsAny(filename, "/\\\x00") || strings.Contains(filename, "..") {
+		return domain.NewValidationError(map[string]string{
+			"script": "Script filename must not contain path separators or \"..\"",
+		})
+	}
+	if !strings.HasSuffix(strings.ToLower(filename), ".js") && !isScriptBundle(filename) {
+		return domain.NewValidationError(map[string]string{
+			"script": "Script must be a .js file or a .zip, .tar or .tar.gz bundle",
+		})
+	}
+	if size > int64(maxBytes) {
+		return domain.NewValidationError(map[string]string{
+			"script": "Script must be at most " + formatScriptLimit(maxBytes),
+		})
+	}
+	return nil
+}
+
+func formatScriptLimit(n int) string {
+	if n >= 1<<20 && n%(1<<20) == 0 {
+		return fmt.Sprintf("%dMB", n>>20)
+	}
+	return fmt.Sprintf("%d bytes", n)
+}
+
+// stageScript writes an uploaded script into dir and validates it. A .js file
+// is saved as <name>.js; a bundle is extracted into <name>/ and entry (main.js
+// by default) is its main script. It returns the path k6 runs and the bytes
+// written. Nothing is left behind on error.
+func (s *TestService) stageScript(dir, name, filename, entry string, reader io.Reader) (string, int64, error) {
+	maxBytes := int64(s.k6Config.MaxScriptBytes)
+	tooLarge := domain.NewValidationError(map[string]string{
+		"script": "Script must be at most " + formatScriptLimit(s.k6Config.MaxScriptBytes),
+	})
+
+	if !isScriptBundle(filename) {
+		scriptPath := filepath.Join(dir, name+".js")
+		f, err := os.Create(scriptPath)
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to create script file: %w", err)
+		}
+		written, err := io.Copy(f, io.LimitReader(reader, maxBytes+1))
+		f.Close()
+		if err != nil {
+			os.Remove(scriptPath)
+			return "", 0, fmt.Errorf("failed to write script file: %w", err)
+		}
+		if written > maxBytes {
+			os.Remove(scriptPath)
+			return "", 0, tooLarge
+		}
+		if err := s.validateScript(scriptPath, "script"); err != nil {
+			os.Remove(scriptPath)
+			return "", 0, err
+		}
+		return scriptPath, written, nil
+	}
+
+	archive, err := os.CreateTemp(dir, name+"-*.archive")
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create bundle file: %w", err)
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+
+	size, err := io.Copy(archive, io.LimitReader(reader, maxBytes+1))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to write bundle file: %w", err)
+	}
+	if size > maxBytes {
+		return "", 0, tooLarge
+	}
+
+	bundleDir := filepath.Join(dir, name)
+	os.RemoveAll(bundleDir)
+	if err := os.MkdirAll(bundleDir, 0755); err != nil {
+		return "", 0, fmt.Errorf("failed to create bundle directory: %w", err)
+	}
+	written, err := extractScriptBundle(archive, size, bundleDir, maxBytes)
+	if err == nil {
+		var scriptPath string
+		scriptPath, err = bundleEntryPath(bundleDir, entry)
+		if err == nil {
+			err = s.validateScript(scriptPath, "script")
+		}
+		if err == nil {
+			return scriptPath, written, nil
+		}
+	}
+	os.RemoveAll(bundleDir)
+	return "", 0, err
+}
+
+// extractScriptBundle unpacks a zip, tar or gzipped tar archive (sniffed from
+// its content, not its name) into dest. Entries must be regular files or
+// directories that stay inside dest, and at most maxBytes are extracted in
+// total. It returns the number of bytes extracted.
+func extractScriptBundle(archive *os.File, size int64, dest string, maxBytes int64) (int64, error) {
+	header := make([]byte, 512)
+	n, _ := archive.ReadAt(header, 0)
+	header = header[:n]
+	if _, err := archive.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	x := &bundleExtractor{dest: dest, remaining: maxBytes}
+	var err error
+	switch {
+	case bytes.HasPrefix(header, []byte("PK\x03\x04")), bytes.HasPrefix(header, []byte("PK\x05\x06")):
+		err = x.zip(archive, size)
+	case bytes.HasPrefix(header, []byte{0x1f, 0x8b}):
+		var gz *gzip.Reader
+		if gz, err = gzip.NewReader(archive); err == nil {
+			err = x.tar(gz)
+			gz.Close()
+		}
+	case len(header) >= 262 && string(header[257:262]) == "ustar":
+		err = x.tar(archive)
+	default:
+		return 0, bundleError("Bundle must be a zip, tar or tar.gz archive")
+	}
+	if err != nil {
+		var appErr *domain.AppError
+		if errors.As(err, &appErr) {
+			return 0, err
+		}
+		return 0, bundleError("Invalid bundle: " + err.Error())
+	}
+	return maxBytes - x.remaining, nil
+}
+
+type bundleExtractor struct {
+	dest      string
+	remaining int64
+	files     int
+}
+
+func (x *bundleExtractor) zip(r io.ReaderAt, size int64) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			if err := x.mkdir(f.Name); err != nil {
+				return err
+			}
+			continue
+		}
+		if !f.Mode().IsRegular() {
+			return bundleError(fmt.Sprintf("Bundle entry %q is not a regular file", f.Name))
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = x.file(f.Name, rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (x *bundleExtractor) tar(r io.Reader) error {
+	tr := tar.NewReader(r)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch h.Typeflag {
+		case tar.TypeDir:
+			if err := x.mkdir(h.Name); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := x.file(h.Name, tr); err != nil {
+				return err
+			}
+		default:
+			return bundleError(fmt.Sprintf("Bundle entry %q is not a regular file", h.Name))
+		}
+	}
+}
+
+func (x *bundleExtractor) mkdir(name string) error {
+	rel, err := bundleRelPath(name)
+	if err != nil || rel == "." {
+		return err
+	}
+	return os.MkdirAll(filepath.Join(x.dest, rel), 0755)
+}
+
+func (x *bundleExtractor) file(name string, r io.Reader) error {
+	rel, err := bundleRelPath(name)
+	if err != nil {
+		return err
+	}
+	x.files++
+	if x.files > maxBundleFiles {
+		return bundleError(fmt.Sprintf("Bundle must contain at most %d files", maxBundleFiles))
+	}
+
+	path := filepath.Join(x.dest, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	written, err := io.Copy(f, io.LimitReader(r, x.remaining+1))
+	if err != nil {
+		return err
+	}
+	if written > x.remaining {
+		return bundleError("Extracted bundle is too large")
+	}
+	x.remaining -= written
+	return nil
+}
+
+// bundleRelPath turns an archive entry name into a path relative to the
+// bundle directory, rejecting absolute paths and ".." components.
+func bundleRelPath(name string) (string, error) {
+	rel := filepath.Clean(filepath.FromSlash(strings.ReplaceAll(name, `\`, "/")))
+	if rel == "." {
+		return rel, nil
+	}
+	if !filepath.IsLocal(rel) {
+		return "", bundleError(fmt.Sprintf("Bundle entry %q escapes the bundle directory", name))
+	}
+	return rel, nil
+}
+
+// bundleEntryPath resolves the main script of an extracted bundle.
+func bundleEntryPath(bundleDir, entry string) (string, error) {
+	if entry == "" {
+		entry = defaultBundleEntry
+	}
+	rel, err := bundleRelPath(entry)
+	if err != nil || rel == "." || !strings.HasSuffix(strings.ToLower(rel), ".js") {
+		return "", domain.NewValidationError(map[string]string{
+			"script_entry": "Entry must be the relative path of a .js file in the bundle",
+		})
+	}
+	path := filepath.Join(bundleDir, rel)
+	if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() {
+		return "", domain.NewValidationError(map[string]string{
+			"script_entry": fmt.Sprintf("Bundle has no %s", filepath.ToSlash(rel)),
+		})
+	}
+	return path, nil
+}
+
+func bundleError(msg string) error {
+	return domain.NewValidationError(map[string]string{"script": msg})
+}
+
+// scriptBundleDir returns the directory a bundled test's script was extracted
+// into, or "" when scriptPath is a single .js file.
+func scriptBundleDir(scriptPath string, testID uuid.UUID) string {
+	marker := string(filepath.Separator) + testID.String() + string(filepath.Separator)
+	i := strings.LastIndex(scriptPath, marker)
+	if i < 0 {
+		return ""
+	}
+	return scriptPath[:i+len(marker)-1]
+}
+
+// scriptBaseDir is the directory holding the test's script file or bundle.
+func scriptBaseDir(t *domain.Test) string {
+	if dir := scriptBundleDir(t.ScriptPath, t.ID); dir != "" {
+		return filepath.Dir(dir)
+	}
+	return filepath.Dir(t.ScriptPath)
+}
+
+// removeScriptFiles deletes a test's script, or its whole bundle directory.
+func removeScriptFiles(scriptPath string, testID uuid.UUID) {
+	if dir := scriptBundleDir(scriptPath, testID); dir != "" {
+		os.RemoveAll(dir)
+		return
+	}
+	os.Remove(scriptPath)
+}
+
+// archiveScriptBundle packs a bundle directory into a temporary tar.gz, so it
+// can be uploaded again (e.g. by Clone). The caller removes the file.
+func archiveScriptBundle(dir string) (*os.File, int64, error) {
+	f, err := os.CreateTemp("", "k6-bundle-*.tar.gz")
+	if err != nil {
+		return nil, 0, err
+	}
+	fail := func(err error) (*os.File, int64, error) {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, 0, err
+	}
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	if err := tw.AddFS(os.DirFS(dir)); err != nil {
+		return fail(err)
+	}
+	if err := tw.Close(); err != nil {
+		return fail(err)
+	}
+	if err := gz.Close(); err != nil {
+		return fail(err)
+	}
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fail(err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return fail(err)
+	}
+	return f, size, nil
+}
@@ -0,0 +1,316 @@
+package app
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBundleRelPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		entry   string
+		want    string
+		wantErr bool
+	}{
+		{name: "file", entry: "main.js", want: "main.js"},
+		{name: "nested", entry: "lib/util.js", want: filepath.Join("lib", "util.js")},
+		{name: "directory", entry: "lib/", want: "lib"},
+		{name: "dot", entry: "./", want: "."},
+		{name: "inner dotdot", entry: "lib/../main.js", want: "main.js"},
+		{name: "backslashes", entry: `lib\util.js`, want: filepath.Join("lib", "util.js")},
+		{name: "parent", entry: "../evil.js", wantErr: true},
+		{name: "nested parent", entry: "lib/../../evil.js", wantErr: true},
+		{name: "backslash parent", entry: `..\evil.js`, wantErr: true},
+		{name: "absolute", entry: "/etc/passwd", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bundleRelPath(tt.entry)
+			if tt.wantErr {
+				if details := validationDetails(t, err); !strings.Contains(details["script"], "escapes") {
+					t.Errorf("unexpected detail: %v", details)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("bundleRelPath(%q) = %q, want %q", tt.entry, got, tt.want)
+			}
+		})
+	}
+}
+
+type bundleEntry struct {
+	name    string
+	body    string
+	symlink bool
+}
+
+func zipBundle(t *testing.T, entries ...bundleEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		h := &zip.FileHeader{Name: e.name, Method: zip.Deflate}
+		if e.symlink {
+			h.SetMode(os.ModeSymlink | 0o777)
+		}
+		w, err := zw.CreateHeader(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(e.body))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func tarBundle(t *testing.T, entries ...bundleEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		h := &tar.Header{Name: e.name, Mode: 0o644, Size: int64(len(e.body)), Typeflag: tar.TypeReg}
+		if e.symlink {
+			h = &tar.Header{Name: e.name, Linkname: e.body, Typeflag: tar.TypeSymlink}
+		}
+		if err := tw.WriteHeader(h); err != nil {
+			t.Fatal(err)
+		}
+		if !e.symlink {
+			tw.Write([]byte(e.body))
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func gzipBundle(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write(data)
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestExtractScriptBundle(t *testing.T) {
+	files := []bundleEntry{
+		{name: "main.js", body: "import './lib/util.js';"},
+		{name: "lib/util.js", body: "export default 1;"},
+	}
+
+	tests := []struct {
+		name      string
+		archive   func(t *testing.T) []byte
+		maxBytes  int64
+		wantBytes int64
+		wantErr   string
+	}{
+		{
+			name:      "zip",
+			archive:   func(t *testing.T) []byte { return zipBundle(t, files...) },
+			maxBytes:  1024,
+			wantBytes: 40,
+		},
+		{
+			name:      "tar",
+			archive:   func(t *testing.T) []byte { return tarBundle(t, files...) },
+			maxBytes:  1024,
+			wantBytes: 40,
+		},
+		{
+			name:      "tar.gz",
+			archive:   func(t *testing.T) []byte { return gzipBundle(t, tarBundle(t, files...)) },
+			maxBytes:  1024,
+			wantBytes: 40,
+		},
+		{
+			name:      "exactly at limit",
+			archive:   func(t *testing.T) []byte { return zipBundle(t, files...) },
+			maxBytes:  40,
+			wantBytes: 40,
+		},
+		{
+			name:     "zip over limit",
+			archive:  func(t *testing.T) []byte { return zipBundle(t, files...) },
+			maxBytes: 39,
+			wantErr:  "Extracted bundle is too large",
+		},
+		{
+			name:     "tar.gz over limit",
+			archive:  func(t *testing.T) []byte { return gzipBundle(t, tarBundle(t, files...)) },
+			maxBytes: 10,
+			wantErr:  "Extracted bundle is too large",
+		},
+		{
+			name: "too many files",
+			archive: func(t *testing.T) []byte {
+				entries := make([]bundleEntry, maxBundleFiles+1)
+				for i := range entries {
+					entries[i] = bundleEntry{name: fmt.Sprintf("f%d.js", i)}
+				}
+				return zipBundle(t, entries...)
+			},
+			maxBytes: 1024,
+			wantErr:  "Bundle must contain at most",
+		},
+		{
+			name:     "zip traversal",
+			archive:  func(t *testing.T) []byte { return zipBundle(t, bundleEntry{name: "../evil.js", body: "x"}) },
+			maxBytes: 1024,
+			wantErr:  "escapes the bundle directory",
+		},
+		{
+			name:     "tar absolute path",
+			archive:  func(t *testing.T) []byte { return tarBundle(t, bundleEntry{name: "/tmp/evil.js", body: "x"}) },
+			maxBytes: 1024,
+			wantErr:  "escapes the bundle directory",
+		},
+		{
+			name: "zip symlink",
+			archive: func(t *testing.T) []byte {
+				return zipBundle(t, bundleEntry{name: "link.js", body: "/etc/passwd", symlink: true})
+			},
+			maxBytes: 1024,
+			wantErr:  "is not a regular file",
+		},
+		{
+			name: "tar symlink",
+			archive: func(t *testing.T) []byte {
+				return tarBundle(t, bundleEntry{name: "link.js", body: "/etc/passwd", symlink: true})
+			},
+			maxBytes: 1024,
+			wantErr:  "is not a regular file",
+		},
+		{
+			name:     "unknown format",
+			archive:  func(t *testing.T) []byte { return []byte("export default function () {}") },
+			maxBytes: 1024,
+			wantErr:  "Bundle must be a zip, tar or tar.gz archive",
+		},
+		{
+			name:     "corrupt gzip",
+			archive:  func(t *testing.T) []byte { return []byte{0x1f, 0x8b, 0x00, 0x01} },
+			maxBytes: 1024,
+			wantErr:  "Invalid bundle",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			data := tt.archive(t)
+			archivePath := filepath.Join(dir, "bundle.archive")
+			if err := os.WriteFile(archivePath, data, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			archive, err := os.Open(archivePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer archive.Close()
+
+			dest := filepath.Join(dir, "out")
+			if err := os.Mkdir(dest, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			written, err := extractScriptBundle(archive, int64(len(data)), dest, tt.maxBytes)
+			if tt.wantErr != "" {
+				if details := validationDetails(t, err); !strings.Contains(details["script"], tt.wantErr) {
+					t.Errorf("script detail = %q, want it to contain %q", details["script"], tt.wantErr)
+				}
+				if _, err := os.Stat(filepath.Join(dir, "evil.js")); err == nil {
+					t.Errorf("entry was written outside the bundle directory")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if written != tt.wantBytes {
+				t.Errorf("written = %d, want %d", written, tt.wantBytes)
+			}
+			for _, f := range files {
+				got, err := os.ReadFile(filepath.Join(dest, filepath.FromSlash(f.name)))
+				if err != nil || string(got) != f.body {
+					t.Errorf("%s = %q, %v; want %q", f.name, got, err, f.body)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateScriptUpload(t *testing.T) {
+	const maxBytes = 5 << 20
+
+	tests := []struct {
+		name       string
+		filename   string
+		size       int64
+		wantDetail string
+	}{
+		{name: "js", filename: "load.js", size: 100},
+		{name: "uppercase extension", filename: "LOAD.JS", size: 100},
+		{name: "zip bundle", filename: "bundle.zip", size: 100},
+		{name: "tar.gz bundle", filename: "bundle.tar.gz", size: 100},
+		{name: "tgz bundle", filename: "bundle.tgz", size: 100},
+		{name: "at limit", filename: "load.js", size: maxBytes},
+		{name: "over limit", filename: "load.js", size: maxBytes + 1, wantDetail: "Script must be at most 5MB"},
+		{name: "empty name", filename: "", wantDetail: "path separators"},
+		{name: "slash", filename: "dir/load.js", wantDetail: "path separators"},
+		{name: "backslash", filename: `dir\load.js`, wantDetail: "path separators"},
+		{name: "dotdot", filename: "..load.js", wantDetail: "path separators"},
+		{name: "nul byte", filename: "load\x00.js", wantDetail: "path separators"},
+		{name: "wrong extension", filename: "load.ts", wantDetail: "Script must be a .js file"},
+		{name: "no extension", filename: "load", wantDetail: "Script must be a .js file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateScriptUpload(tt.filename, tt.size, maxBytes)
+			if tt.wantDetail == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if details := validationDetails(t, err); !strings.Contains(details["script"], tt.wantDetail) {
+				t.Errorf("script detail = %q, want it to contain %q", details["script"], tt.wantDetail)
+			}
+		})
+	}
+}
+
+func TestFormatScriptLimit(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{n: 1 << 20, want: "1MB"},
+		{n: 10 << 20, want: "10MB"},
+		{n: 1<<20 + 1, want: "1048577 bytes"},
+		{n: 512, want: "512 bytes"},
+	}
+	for _, tt := range tests {
+		if got := formatScriptLimit(tt.n); got != tt.want {
+			t.Errorf("formatScriptLimit(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
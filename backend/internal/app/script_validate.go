@@ -0,0 +1,73 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+const (
+	scriptInspectTimeout  = 30 * time.Second
+	maxInspectOutputBytes = 4096
+)
+
+// validateScript runs `k6 inspect` on the script when K6_VALIDATE_ON_UPLOAD is
+// set, so syntax and import errors surface at upload instead of at run time.
+// A failing script is reported under field with k6's own output.
+func (s *TestService) validateScript(path, field string) error {
+	if !s.k6Config.ValidateOnUpload {
+		return nil
+	}
+	_, err := s.inspectScript(path, field)
+	return err
+}
+
+// inspectScript runs `k6 inspect` and returns the JSON it prints on stdout:
+// the options the script declares. A script k6 rejects is reported under
+// field with k6's own output.
+func (s *TestService) inspectScript(path, field string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), scriptInspectTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, k6Binary(s.k6Config), "inspect", path)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err == nil {
+		return stdout.Bytes(), nil
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, domain.NewValidationError(map[string]string{
+			field: "Script validation timed out",
+		})
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return nil, fmt.Errorf("failed to run k6 inspect: %w", err)
+	}
+
+	msg := strings.TrimSpace(stderr.String() + "\n" + stdout.String())
+	if len(msg) > maxInspectOutputBytes {
+		msg = msg[:maxInspectOutputBytes] + "..."
+	}
+	if msg == "" {
+		msg = "Script failed k6 validation"
+	}
+	return nil, domain.NewValidationError(map[string]string{
+		field: msg,
+	})
+}
+
+// uploadScriptPath is where a replacement script is staged before validation.
+// It keeps the .js extension so k6 treats it as a script.
+func uploadScriptPath(scriptPath string) string {
+	return strings.TrimSuffix(scriptPath, ".js") + ".upload.js"
+}
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/google/uuid"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+// recordScriptVersion snapshots the script currently on disk as a new
+// version. The file is authoritative, so a failure only loses history and is
+// logged rather than failing the save.
+func (s *TestService) recordScriptVersion(t *domain.Test, userID uuid.UUID, restoredFrom *int) {
+	content, err := os.ReadFile(t.ScriptPath)
+	if err != nil {
+		log.Printf("[Scripts] Failed to read script of test %s for versioning: %v", t.ID, err)
+		return
+	}
+
+	v := &domain.ScriptVersion{
+		TestID:       t.ID,
+		Filename:     t.ScriptFilename,
+		Content:      string(content),
+		SizeBytes:    int64(len(content)),
+		CreatedBy:    &userID,
+		RestoredFrom: restoredFrom,
+	}
+	if err := s.versionRepo.Create(v); err != nil {
+		log.Printf("[Scripts] Failed to record script version for test %s: %v", t.ID, err)
+	}
+}
+
+// ensureBaseVersion records the current script as the first version of tests
+// created before versioning existed, so their original script is not lost
+// when it is overwritten.
+func (s *TestService) ensureBaseVersion(t *domain.Test) {
+	versions, err := s.versionRepo.ListByTest(t.ID)
+	if err != nil || len(versions) > 0 {
+		return
+	}
+	s.recordScriptVersion(t, t.UserID, nil)
+}
+
+func (s *TestService) ListScriptVersions(id uuid.UUID, userID uuid.UUID, isRoot bool) ([]domain.ScriptVersion, error) {
+	t, err := s.GetByID(id, userID, isRoot)
+	if err != nil {
+		return nil, err
+	}
+	return s.versionRepo.ListByTest(t.ID)
+}
+
+func (s *TestService) GetScriptVersion(id uuid.UUID, version int, userID uuid.UUID, isRoot bool) (*domain.ScriptVersion, error) {
+	t, err := s.GetByID(id, userID, isRoot)
+	if err != nil {
+		return nil, err
+	}
+	v, err := s.versionRepo.Get(t.ID, version)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.NewNotFoundError("Script version")
+		}
+		return nil, err
+	}
+	return v, nil
+}
+
+// RollbackScript makes a previous version active again. The restored content
+// is validated like any other save and recorded as a new version, so the
+// history stays append-only. Versions only hold the main script, so a
+// rollback between a single .js file and a bundle is refused, and within a
+// bundle only its main script is restored.
+func (s *TestService) RollbackScript(id uuid.UUID, version int, userID uuid.UUID, isRoot bool) (*domain.Test, error) {
+	v, err := s.GetScriptVersion(id, version, userID, isRoot)
+	if err != nil {
+		return nil, err
+	}
+	t, err := s.testRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if isScriptBundle(v.Filename) != (scriptBundleDir(t.ScriptPath, t.ID) != "") {
+		return nil, domain.NewValidationError(map[string]string{
+			"version": "Cannot roll back between a single script and a bundle; upload the script instead",
+		})
+	}
+
+	uploadPath := uploadScriptPath(t.ScriptPath)
+	if err := os.WriteFile(uploadPath, []byte(v.Content), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write script: %w", err)
+	}
+	if err := s.validateScript(uploadPath, "version"); err != nil {
+		os.Remove(uploadPath)
+		return nil, err
+	}
+	if err := os.Rename(uploadPath, t.ScriptPath); err != nil {
+		os.Remove(uploadPath)
+		return nil, fmt.Errorf("failed to write script: %w", err)
+	}
+
+	t.ScriptFilename = v.Filename
+	t.ScriptSizeBytes = v.SizeBytes
+	if err := s.testRepo.Update(t); err != nil {
+		return nil, err
+	}
+
+	s.recordScriptVersion(t, userID, &v.Version)
+	return t, nil
+}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+// SessionCleaner periodically deletes expired and revoked sessions so the
+// sessions table doesn't grow unbounded.
+type SessionCleaner struct {
+	sessionRepo domain.SessionRepository
+	interval    time.Duration
+	ticker      *time.Ticker
+	done        chan struct{}
+	stopOnce    sync.Once
+}
+
+func NewSessionCleaner(sessionRepo domain.SessionRepository, interval time.Duration) *SessionCleaner {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &SessionCleaner{
+		sessionRepo: sessionRepo,
+		interval:    interval,
+		done:        make(chan struct{}),
+	}
+}
+
+func (c *SessionCleaner) Start() {
+	c.ticker = time.NewTicker(c.interval)
+	log.Printf("[Sessions] Cleanup started (every %s)", c.interval)
+
+	go func() {
+		c.clean()
+		for {
+			select {
+			case <-c.ticker.C:
+				c.clean()
+			case <-c.done:
+				return
+			}
+		}
+	}()
+}
+
+func (c *SessionCleaner) Stop() {
+	c.stopOnce.Do(func() {
+		if c.ticker != nil {
+			c.ticker.Stop()
+		}
+		close(c.done)
+		log.Println("[Sessions] Cleanup stopped")
+	})
+}
+
+func (c *SessionCleaner) clean() {
+	removed, err := c.sessionRepo.CleanExpired()
+	if err != nil {
+		log.Printf("[Sessions] Failed to clean expired sessions: %v", err)
+		return
+	}
+	if removed > 0 {
+		log.Printf("[Sessions] Removed %d expired/revoked sessions", removed)
+	}
+}
@@ -0,0 +1,81 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+const maxStages = 20
+
+// validateStages checks that every stage duration parses and every target is
+// within maxVUs. Durations are not bounded here: the runner trims the stages
+// to the configured maximum duration when the run starts.
+func validateStages(stages domain.Stages, maxVUs int) error {
+	if len(stages) == 0 {
+		return nil
+	}
+	if len(stages) > maxStages {
+		return domain.NewValidationError(map[string]string{
+			"stages": fmt.Sprintf("At most %d stages are allowed", maxStages),
+		})
+	}
+
+	var total time.Duration
+	for i, stage := range stages {
+		d, err := time.ParseDuration(stage.Duration)
+		if err != nil || d < 0 {
+			return domain.NewValidationError(map[string]string{
+				fmt.Sprintf("stages[%d].duration", i): "Must be a non-negative duration (e.g. 30s, 2m)",
+			})
+		}
+		if stage.Target < 0 || stage.Target > maxVUs {
+			return domain.NewValidationError(map[string]string{
+				fmt.Sprintf("stages[%d].target", i): fmt.Sprintf("Must be between 0 and %d", maxVUs),
+			})
+		}
+		total += d
+	}
+	if total <= 0 {
+		return domain.NewValidationError(map[string]string{
+			"stages": "Total stage duration must be positive",
+		})
+	}
+	return nil
+}
+
+// capStages trims the stages so their total duration does not exceed
+// maxDuration, shortening the stage that crosses the limit. It returns the
+// trimmed stages, their total duration and the peak target.
+func capStages(stages domain.Stages, maxDuration time.Duration) (domain.Stages, time.Duration, int) {
+	var out domain.Stages
+	var total time.Duration
+	peak := 0
+	for _, stage := range stages {
+		d, err := time.ParseDuration(stage.Duration)
+		if err != nil || d < 0 {
+			continue
+		}
+		if total+d > maxDuration {
+			d = maxDuration - total
+		}
+		out = append(out, domain.Stage{Duration: d.String(), Target: stage.Target})
+		total += d
+		peak = max(peak, stage.Target)
+		if total >= maxDuration {
+			break
+		}
+	}
+	return out, total, peak
+}
+
+// stageArgs renders stages as k6 --stage DURATION:TARGET flags.
+func stageArgs(stages domain.Stages) []string {
+	args := make([]string, 0, len(stages)*2)
+	for _, stage := range stages {
+		args = append(args, "--stage", stage.Duration+":"+strconv.Itoa(stage.Target))
+	}
+	return args
+}
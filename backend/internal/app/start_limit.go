@@ -0,0 +1,53 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+// StartLimiter caps how many executions a user may start per minute, on top
+// of the concurrency limits: short runs would otherwise let a user flood the
+// database and the scheduler.
+type StartLimiter struct {
+	limiter   domain.RateLimiter
+	perMinute int
+}
+
+// NewStartLimiter returns a limiter; perMinute <= 0 disables it.
+func NewStartLimiter(limiter domain.RateLimiter, perMinute int) *StartLimiter {
+	return &StartLimiter{limiter: limiter, perMinute: perMinute}
+}
+
+// Take consumes one start for the user, or returns a 429 carrying the wait
+// until the next start is allowed. Limiter failures let the start through.
+func (l *StartLimiter) Take(userID uuid.UUID) error {
+	if l == nil || l.perMinute <= 0 {
+		return nil
+	}
+	allowed, retryAfter, err := l.limiter.Take(userID.String(), l.perMinute, time.Minute)
+	if err != nil {
+		log.Printf("[StartLimiter] Rate limit check failed for user %s, allowing: %v", userID, err)
+		return nil
+	}
+	if allowed {
+		return nil
+	}
+	return domain.NewTooManyRequestsError(
+		fmt.Sprintf("Start limit reached (%d executions per minute)", l.perMinute),
+	).WithRetryAfter(retryAfter)
+}
+
+// Refund gives back a start taken for a run that was then refused.
+func (l *StartLimiter) Refund(userID uuid.UUID) {
+	if l == nil || l.perMinute <= 0 {
+		return
+	}
+	if err := l.limiter.Refund(userID.String(), l.perMinute, time.Minute); err != nil {
+		log.Printf("[StartLimiter] Failed to refund start for user %s: %v", userID, err)
+	}
+}
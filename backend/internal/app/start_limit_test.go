@@ -0,0 +1,65 @@
+package app
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type failingRateLimiter struct{}
+
+func (failingRateLimiter) Take(string, int, time.Duration) (bool, time.Duration, error) {
+	return false, 0, errors.New("redis down")
+}
+
+func (failingRateLimiter) Refund(string, int, time.Duration) error {
+	return errors.New("redis down")
+}
+
+func TestStartLimiter(t *testing.T) {
+	limiter := newFakeRateLimiter(20 * time.Second)
+	starts := NewStartLimiter(limiter, 2)
+	userID := uuid.New()
+
+	for i := 0; i < 2; i++ {
+		if err := starts.Take(userID); err != nil {
+			t.Fatalf("start %d: %v", i+1, err)
+		}
+	}
+	appErr := tooManyRequests(t, starts.Take(userID))
+	if appErr.RetryAfter != 20*time.Second {
+		t.Errorf("RetryAfter = %v, want the limiter's 20s", appErr.RetryAfter)
+	}
+	if err := starts.Take(uuid.New()); err != nil {
+		t.Errorf("another user was limited: %v", err)
+	}
+
+	starts.Refund(userID)
+	if err := starts.Take(userID); err != nil {
+		t.Errorf("start after a refund: %v", err)
+	}
+}
+
+func TestStartLimiterDisabled(t *testing.T) {
+	tests := []struct {
+		name   string
+		starts *StartLimiter
+	}{
+		{"nil", nil},
+		{"zero per minute", NewStartLimiter(newFakeRateLimiter(time.Second), 0)},
+		{"limiter failing open", NewStartLimiter(failingRateLimiter{}, 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			for i := 0; i < 3; i++ {
+				if err := tt.starts.Take(userID); err != nil {
+					t.Fatalf("start %d: %v", i+1, err)
+				}
+			}
+			tt.starts.Refund(userID)
+		})
+	}
+}
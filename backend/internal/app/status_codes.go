@@ -0,0 +1,43 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+const maxSuccessStatusCodes = 50
+
+// DefaultSuccessStatusCodes are the codes counted as successful when a test
+// does not configure its own set.
+var DefaultSuccessStatusCodes = []int{200, 201}
+
+// normalizeSuccessStatusCodes validates the codes and returns them sorted and
+// deduplicated. An empty set falls back to DefaultSuccessStatusCodes.
+func normalizeSuccessStatusCodes(codes []int) ([]int, error) {
+	if len(codes) == 0 {
+		return append([]int(nil), DefaultSuccessStatusCodes...), nil
+	}
+	if len(codes) > maxSuccessStatusCodes {
+		return nil, domain.NewValidationError(map[string]string{
+			"success_status_codes": fmt.Sprintf("At most %d status codes allowed", maxSuccessStatusCodes),
+		})
+	}
+
+	seen := make(map[int]bool, len(codes))
+	out := make([]int, 0, len(codes))
+	for _, c := range codes {
+		if c < 100 || c > 599 {
+			return nil, domain.NewValidationError(map[string]string{
+				"success_status_codes": fmt.Sprintf("Invalid HTTP status code %d", c),
+			})
+		}
+		if !seen[c] {
+			seen[c] = true
+			out = append(out, c)
+		}
+	}
+	sort.Ints(out)
+	return out, nil
+}
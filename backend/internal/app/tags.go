@@ -0,0 +1,45 @@
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+const (
+	maxTags      = 20
+	maxTagLength = 50
+)
+
+// Tags are lowercase labels such as "team:payments" or "env:staging".
+var tagRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_.:/-]*$`)
+
+// normalizeTags trims, lowercases, dedupes and sorts tags. The result is
+// never nil so tests and domains always serialize an array.
+func normalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len(tag) > maxTagLength || !tagRe.MatchString(tag) {
+			return nil, domain.NewValidationError(map[string]string{
+				"tags": fmt.Sprintf("Invalid tag %q: use up to %d lowercase letters, digits or _ . : / -", tag, maxTagLength),
+			})
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	if len(out) > maxTags {
+		return nil, domain.NewValidationError(map[string]string{
+			"tags": fmt.Sprintf("At most %d tags are allowed", maxTags),
+		})
+	}
+	sort.Strings(out)
+	return out, nil
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+const (
+	maxTargets      = 20
+	maxTargetWeight = 1000
+)
+
+// normalizeTargets validates a target list, strips trailing slashes and fills in
+// default weights. Weights are bounded per target so their sum is always positive.
+func normalizeTargets(targets domain.Targets) (domain.Targets, error) {
+	if len(targets) == 0 {
+		return nil, nil
+	}
+	if len(targets) > maxTargets {
+		return nil, domain.NewValidationError(map[string]string{
+			"targets": fmt.Sprintf("At most %d targets are allowed", maxTargets),
+		})
+	}
+
+	errs := map[string]string{}
+	seen := make(map[string]bool, len(targets))
+	out := make(domain.Targets, 0, len(targets))
+
+	for i, t := range targets {
+		key := fmt.Sprintf("targets[%d]", i)
+
+		raw := strings.TrimSpace(t.URL)
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+			errs[key+".url"] = "Must be an absolute http or https URL"
+			continue
+		}
+		if u.User != nil {
+			errs[key+".url"] = "Credentials are not allowed in target URLs"
+			continue
+		}
+		if u.RawQuery != "" || u.Fragment != "" {
+			errs[key+".url"] = "Target must be a base URL without query or fragment"
+			continue
+		}
+		base := strings.TrimRight(u.String(), "/")
+		if seen[base] {
+			errs[key+".url"] = "Duplicate target"
+			continue
+		}
+		seen[base] = true
+
+		weight := t.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		if weight < 0 || weight > maxTargetWeight {
+			errs[key+".weight"] = fmt.Sprintf("Must be between 1 and %d", maxTargetWeight)
+			continue
+		}
+
+		out = append(out, domain.Target{URL: base, Weight: weight})
+	}
+
+	if len(errs) > 0 {
+		return nil, domain.NewValidationError(errs)
+	}
+	return out, nil
+}
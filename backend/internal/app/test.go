@@ -3,6 +3,7 @@ package app
 import (
 	"fmt"
 	"io"
+	"log"
 	"os"
 	"path/filepath"
 	"strings"
@@ -14,20 +15,29 @@ import (
 )
 
 type TestService struct {
-	testRepo   domain.TestRepository
-	domainRepo domain.DomainRepository
-	k6Config   config.K6Config
+	testRepo    domain.TestRepository
+	domainRepo  domain.DomainRepository
+	userRepo    domain.UserRepository
+	versionRepo domain.ScriptVersionRepository
+	grafana     domain.GrafanaProvisioner
+	k6Config    config.K6Config
 }
 
 func NewTestService(
 	testRepo domain.TestRepository,
 	domainRepo domain.DomainRepository,
+	userRepo domain.UserRepository,
+	versionRepo domain.ScriptVersionRepository,
+	grafana domain.GrafanaProvisioner,
 	k6Config config.K6Config,
 ) *TestService {
 	return &TestService{
-		testRepo:   testRepo,
-		domainRepo: domainRepo,
-		k6Config:   k6Config,
+		testRepo:    testRepo,
+		domainRepo:  domainRepo,
+		userRepo:    userRepo,
+		versionRepo: versionRepo,
+		grafana:     grafana,
+		k6Config:    k6Config,
 	}
 }
 
@@ -38,16 +48,38 @@ func (s *TestService) Create(userID uuid.UUID, isRoot bool, input domain.CreateT
 		})
 	}
 
-	if !strings.HasSuffix(strings.ToLower(filename), ".js") {
-		return nil, domain.NewValidationError(map[string]string{
-			"script": "Script must be a .js file",
-		})
+	if err := validateScriptUpload(filename, scriptSize, s.k6Config.MaxScriptBytes); err != nil {
+		return nil, err
 	}
 
-	if scriptSize > 1024*1024 {
-		return nil, domain.NewValidationError(map[string]string{
-			"script": "Script must be less than 1MB",
-		})
+	if err := validateCooldown(input.Cooldown); err != nil {
+		return nil, err
+	}
+	successCodes, err := normalizeSuccessStatusCodes(input.SuccessStatusCodes)
+	if err != nil {
+		return nil, err
+	}
+	if input.WebhookURL != nil {
+		if err := validateWebhookURL(*input.WebhookURL); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateThresholds(input.Thresholds); err != nil {
+		return nil, err
+	}
+	if err := validateRetryPolicy(input.MaxRetries, input.RetryBackoff); err != nil {
+		return nil, err
+	}
+	if err := validateResourceLimits(input.MaxMemoryMB, input.MaxCPUs, s.k6Config); err != nil {
+		return nil, err
+	}
+	tags, err := normalizeTags(input.Tags)
+	if err != nil {
+		return nil, err
+	}
+	trendStats, err := normalizeTrendStats(input.SummaryTrendStats)
+	if err != nil {
+		return nil, err
 	}
 
 	// Verify domain ownership
@@ -58,6 +90,9 @@ func (s *TestService) Create(userID uuid.UUID, isRoot bool, input domain.CreateT
 	if !isRoot && d.UserID != userID {
 		return nil, domain.NewForbiddenError("Access denied")
 	}
+	if err := validateStages(input.DefaultStages, domainK6Limits(s.k6Config, d).MaxVUs); err != nil {
+		return nil, err
+	}
 
 	// Check name uniqueness within domain
 	existing, _ := s.testRepo.GetByDomainAndName(input.DomainID, input.Name)
@@ -74,17 +109,9 @@ func (s *TestService) Create(userID uuid.UUID, isRoot bool, input domain.CreateT
 		return nil, fmt.Errorf("failed to create script directory: %w", err)
 	}
 
-	scriptPath := filepath.Join(scriptDir, testID.String()+".js")
-	f, err := os.Create(scriptPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create script file: %w", err)
-	}
-	defer f.Close()
-
-	written, err := io.Copy(f, scriptReader)
+	scriptPath, written, err := s.stageScript(scriptDir, testID.String(), filename, input.ScriptEntry, scriptReader)
 	if err != nil {
-		os.Remove(scriptPath)
-		return nil, fmt.Errorf("failed to write script file: %w", err)
+		return nil, err
 	}
 
 	// Set defaults
@@ -98,26 +125,151 @@ func (s *TestService) Create(userID uuid.UUID, isRoot bool, input domain.CreateT
 	}
 
 	test := &domain.Test{
-		ID:              testID,
-		DomainID:        input.DomainID,
-		UserID:          userID,
-		Name:            input.Name,
-		Description:     input.Description,
-		ScriptFilename:  filename,
-		ScriptPath:      scriptPath,
-		ScriptSizeBytes: written,
-		DefaultVUs:      vus,
-		DefaultDuration: duration,
+		ID:                 testID,
+		DomainID:           input.DomainID,
+		UserID:             userID,
+		Name:               input.Name,
+		Description:        input.Description,
+		Tags:               tags,
+		ScriptFilename:     filename,
+		ScriptPath:         scriptPath,
+		ScriptSizeBytes:    written,
+		DefaultVUs:         vus,
+		DefaultDuration:    duration,
+		DefaultStages:      input.DefaultStages,
+		Cooldown:           input.Cooldown,
+		SuccessStatusCodes: successCodes,
+		WebhookURL:         emptyToNil(input.WebhookURL),
+		WebhookSecret:      emptyToNil(input.WebhookSecret),
+		Thresholds:         input.Thresholds,
+		MaxRetries:         input.MaxRetries,
+		RetryBackoff:       input.RetryBackoff,
+		MaxMemoryMB:        input.MaxMemoryMB,
+		MaxCPUs:            input.MaxCPUs,
+		SummaryTrendStats:  trendStats,
+		NormalizeURLs:      input.NormalizeURLs,
 	}
 
 	if err := s.testRepo.Create(test); err != nil {
-		os.Remove(scriptPath)
+		removeScriptFiles(scriptPath, testID)
 		return nil, err
 	}
 
+	s.recordScriptVersion(test, userID, nil)
+
+	test.DomainName = &d.Name
+	s.provisionDashboard(test)
+
 	return test, nil
 }
 
+// provisionDashboard creates the test's Grafana dashboard in the owner's
+// folder (General when they have none). Grafana being unavailable must not
+// block test creation, so failures are only logged.
+func (s *TestService) provisionDashboard(test *domain.Test) {
+	if s.grafana == nil {
+		return
+	}
+	folderUID := ""
+	if owner, err := s.userRepo.GetByID(test.UserID); err == nil && owner.GrafanaFolderUID != nil {
+		folderUID = *owner.GrafanaFolderUID
+	}
+	dashboard, err := s.grafana.CreateDashboard(folderUID, *test)
+	if err != nil {
+		log.Printf("[Grafana] Failed to create dashboard for test %s: %v", test.ID, err)
+		return
+	}
+	if err := s.testRepo.SetGrafanaDashboard(test.ID, dashboard.UID, dashboard.URL); err != nil {
+		log.Printf("[Grafana] Failed to save dashboard for test %s: %v", test.ID, err)
+		return
+	}
+	test.GrafanaDashboardUID = &dashboard.UID
+	test.GrafanaDashboardURL = &dashboard.URL
+}
+
+// Clone creates a new test from an existing one's settings and script. It goes
+// through Create, so the clone gets its own ID and script file and the same
+// validation, domain ownership and name uniqueness checks apply. Executions
+// are not copied.
+func (s *TestService) Clone(id uuid.UUID, userID uuid.UUID, isRoot bool, input domain.CloneTestInput) (*domain.Test, error) {
+	src, err := s.GetByID(id, userID, isRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var script *os.File
+	size := src.ScriptSizeBytes
+	entry := ""
+	if bundleDir := scriptBundleDir(src.ScriptPath, src.ID); bundleDir != "" {
+		script, size, err = archiveScriptBundle(bundleDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to archive script bundle: %w", err)
+		}
+		defer os.Remove(script.Name())
+		entry, _ = filepath.Rel(bundleDir, src.ScriptPath)
+	} else if script, err = os.Open(src.ScriptPath); err != nil {
+		return nil, fmt.Errorf("failed to read script: %w", err)
+	}
+	defer script.Close()
+
+	create := domain.CreateTestInput{
+		DomainID:           src.DomainID,
+		Name:               src.Name + " (copy)",
+		Description:        src.Description,
+		Tags:               src.Tags,
+		DefaultVUs:         src.DefaultVUs,
+		DefaultDuration:    src.DefaultDuration,
+		DefaultStages:      src.DefaultStages,
+		Cooldown:           src.Cooldown,
+		SuccessStatusCodes: src.SuccessStatusCodes,
+		WebhookURL:         src.WebhookURL,
+		WebhookSecret:      src.WebhookSecret,
+		Thresholds:         src.Thresholds,
+		MaxRetries:         src.MaxRetries,
+		RetryBackoff:       src.RetryBackoff,
+		MaxMemoryMB:        src.MaxMemoryMB,
+		MaxCPUs:            src.MaxCPUs,
+		SummaryTrendStats:  src.SummaryTrendStats,
+		NormalizeURLs:      src.NormalizeURLs,
+		ScriptEntry:        filepath.ToSlash(entry),
+	}
+	if input.DomainID != nil {
+		create.DomainID = *input.DomainID
+	}
+	if input.Name != nil {
+		create.Name = strings.TrimSpace(*input.Name)
+	}
+
+	return s.Create(userID, isRoot, create, src.ScriptFilename, script, size)
+}
+
+// CreateFromContent creates a test whose script is given inline, with the
+// same validation as an uploaded .js file.
+func (s *TestService) CreateFromContent(userID uuid.UUID, isRoot bool, input domain.CreateTestJSONInput) (*domain.Test, error) {
+	filename := input.ScriptFilename
+	if filename == "" {
+		filename = defaultInlineScriptName
+	}
+	if strings.TrimSpace(input.ScriptContent) == "" {
+		return nil, domain.NewValidationError(map[string]string{
+			"script_content": "Script content is required",
+		})
+	}
+	if isScriptBundle(filename) {
+		return nil, domain.NewValidationError(map[string]string{
+			"script_filename": "Inline scripts must be .js files; upload bundles as multipart/form-data",
+		})
+	}
+	content := strings.NewReader(input.ScriptContent)
+	return s.Create(userID, isRoot, input.CreateTestInput, filename, content, content.Size())
+}
+
+// MaxScriptBytes is the K6_MAX_SCRIPT_BYTES limit, for handlers bounding
+// request bodies.
+func (s *TestService) MaxScriptBytes() int {
+	return s.k6Config.MaxScriptBytes
+}
+
 func (s *TestService) GetByID(id uuid.UUID, userID uuid.UUID, isRoot bool) (*domain.Test, error) {
 	t, err := s.testRepo.GetByID(id)
 	if err != nil {
@@ -154,6 +306,89 @@ func (s *TestService) Update(id uuid.UUID, userID uuid.UUID, isRoot bool, input
 	if input.DefaultDuration != nil {
 		t.DefaultDuration = *input.DefaultDuration
 	}
+	if input.Tags != nil {
+		tags, err := normalizeTags(input.Tags)
+		if err != nil {
+			return nil, err
+		}
+		t.Tags = tags
+	}
+	if input.DefaultStages != nil {
+		limits, _ := loadK6Limits(s.domainRepo, s.k6Config, t.DomainID)
+		if err := validateStages(input.DefaultStages, limits.MaxVUs); err != nil {
+			return nil, err
+		}
+		t.DefaultStages = input.DefaultStages
+	}
+	if input.Cooldown != nil {
+		if err := validateCooldown(*input.Cooldown); err != nil {
+			return nil, err
+		}
+		t.Cooldown = *input.Cooldown
+	}
+	if input.SuccessStatusCodes != nil {
+		codes, err := normalizeSuccessStatusCodes(input.SuccessStatusCodes)
+		if err != nil {
+			return nil, err
+		}
+		t.SuccessStatusCodes = codes
+	}
+	if input.WebhookURL != nil {
+		if err := validateWebhookURL(*input.WebhookURL); err != nil {
+			return nil, err
+		}
+		t.WebhookURL = emptyToNil(input.WebhookURL)
+	}
+	if input.WebhookSecret != nil {
+		t.WebhookSecret = emptyToNil(input.WebhookSecret)
+	}
+	if input.Thresholds != nil {
+		if err := validateThresholds(input.Thresholds); err != nil {
+			return nil, err
+		}
+		t.Thresholds = input.Thresholds
+	}
+	if input.SummaryTrendStats != nil {
+		stats, err := normalizeTrendStats(input.SummaryTrendStats)
+		if err != nil {
+			return nil, err
+		}
+		t.SummaryTrendStats = stats
+	}
+	if input.NormalizeURLs != nil {
+		t.NormalizeURLs = *input.NormalizeURLs
+	}
+	if input.MaxRetries != nil || input.RetryBackoff != nil {
+		maxRetries, backoff := t.MaxRetries, t.RetryBackoff
+		if input.MaxRetries != nil {
+			maxRetries = *input.MaxRetries
+		}
+		if input.RetryBackoff != nil {
+			backoff = *input.RetryBackoff
+		}
+		if err := validateRetryPolicy(maxRetries, backoff); err != nil {
+			return nil, err
+		}
+		t.MaxRetries, t.RetryBackoff = maxRetries, backoff
+	}
+	if input.MaxMemoryMB != nil {
+		if *input.MaxMemoryMB == 0 {
+			t.MaxMemoryMB = nil
+		} else if err := validateResourceLimits(input.MaxMemoryMB, nil, s.k6Config); err != nil {
+			return nil, err
+		} else {
+			t.MaxMemoryMB = input.MaxMemoryMB
+		}
+	}
+	if input.MaxCPUs != nil {
+		if *input.MaxCPUs == 0 {
+			t.MaxCPUs = nil
+		} else if err := validateResourceLimits(nil, input.MaxCPUs, s.k6Config); err != nil {
+			return nil, err
+		} else {
+			t.MaxCPUs = input.MaxCPUs
+		}
+	}
 
 	if err := s.testRepo.Update(t); err != nil {
 		return nil, err
@@ -161,7 +396,10 @@ func (s *TestService) Update(id uuid.UUID, userID uuid.UUID, isRoot bool, input
 	return t, nil
 }
 
-func (s *TestService) UpdateScript(id uuid.UUID, userID uuid.UUID, isRoot bool, filename string, reader io.Reader, size int64) (*domain.Test, error) {
+// UpdateScript replaces the test's script with a .js file or a bundle. The
+// upload is staged next to the current script and swapped in only once it
+// validates; entry names a bundle's main script (main.js by default).
+func (s *TestService) UpdateScript(id uuid.UUID, userID uuid.UUID, isRoot bool, filename, entry string, reader io.Reader, size int64) (*domain.Test, error) {
 	t, err := s.testRepo.GetByID(id)
 	if err != nil {
 		return nil, err
@@ -170,34 +408,62 @@ func (s *TestService) UpdateScript(id uuid.UUID, userID uuid.UUID, isRoot bool,
 		return nil, domain.NewForbiddenError("Access denied")
 	}
 
-	if !strings.HasSuffix(strings.ToLower(filename), ".js") {
-		return nil, domain.NewValidationError(map[string]string{
-			"script": "Script must be a .js file",
-		})
-	}
-	if size > 1024*1024 {
-		return nil, domain.NewValidationError(map[string]string{
-			"script": "Script must be less than 1MB",
-		})
+	if err := validateScriptUpload(filename, size, s.k6Config.MaxScriptBytes); err != nil {
+		return nil, err
 	}
 
-	f, err := os.Create(t.ScriptPath)
+	dir := scriptBaseDir(t)
+	uploadPath, written, err := s.stageScript(dir, t.ID.String()+".upload", filename, entry, reader)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create script file: %w", err)
+		return nil, err
 	}
-	defer f.Close()
-
-	written, err := io.Copy(f, reader)
-	if err != nil {
-		return nil, fmt.Errorf("failed to write script file: %w", err)
+	s.ensureBaseVersion(t)
+
+	oldPath := t.ScriptPath
+	oldBundleDir := scriptBundleDir(oldPath, t.ID)
+	var newPath string
+	if isScriptBundle(filename) {
+		uploadDir := filepath.Join(dir, t.ID.String()+".upload")
+		bundleDir := filepath.Join(dir, t.ID.String())
+		rel, _ := filepath.Rel(uploadDir, uploadPath)
+		newPath = filepath.Join(bundleDir, rel)
+		if oldBundleDir != "" {
+			os.RemoveAll(oldBundleDir + ".old")
+			if err := os.Rename(oldBundleDir, oldBundleDir+".old"); err != nil {
+				os.RemoveAll(uploadDir)
+				return nil, fmt.Errorf("failed to replace script bundle: %w", err)
+			}
+			defer os.RemoveAll(oldBundleDir + ".old")
+		}
+		if err := os.Rename(uploadDir, bundleDir); err != nil {
+			os.RemoveAll(uploadDir)
+			if oldBundleDir != "" {
+				os.Rename(oldBundleDir+".old", oldBundleDir)
+			}
+			return nil, fmt.Errorf("failed to replace script bundle: %w", err)
+		}
+		if oldBundleDir == "" {
+			os.Remove(oldPath)
+		}
+	} else {
+		newPath = filepath.Join(dir, t.ID.String()+".js")
+		if err := os.Rename(uploadPath, newPath); err != nil {
+			os.Remove(uploadPath)
+			return nil, fmt.Errorf("failed to replace script file: %w", err)
+		}
+		if oldBundleDir != "" {
+			os.RemoveAll(oldBundleDir)
+		}
 	}
 
 	t.ScriptFilename = filename
+	t.ScriptPath = newPath
 	t.ScriptSizeBytes = written
 
 	if err := s.testRepo.Update(t); err != nil {
 		return nil, err
 	}
+	s.recordScriptVersion(t, userID, nil)
 	return t, nil
 }
 
@@ -210,8 +476,8 @@ func (s *TestService) Delete(id uuid.UUID, userID uuid.UUID, isRoot bool) error
 		return domain.NewForbiddenError("Access denied")
 	}
 
-	// Remove script file
-	os.Remove(t.ScriptPath)
+	// Remove script file, or the whole bundle
+	removeScriptFiles(t.ScriptPath, t.ID)
 
 	return s.testRepo.Delete(id)
 }
@@ -241,13 +507,23 @@ func (s *TestService) SaveScriptContent(id uuid.UUID, userID uuid.UUID, isRoot b
 		return nil, domain.NewForbiddenError("Access denied")
 	}
 
-	if len(content) > 1024*1024 {
+	if len(content) > s.k6Config.MaxScriptBytes {
 		return nil, domain.NewValidationError(map[string]string{
-			"content": "Script must be less than 1MB",
+			"content": "Script must be at most " + formatScriptLimit(s.k6Config.MaxScriptBytes),
 		})
 	}
 
-	if err := os.WriteFile(t.ScriptPath, []byte(content), 0644); err != nil {
+	uploadPath := uploadScriptPath(t.ScriptPath)
+	if err := os.WriteFile(uploadPath, []byte(content), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write script: %w", err)
+	}
+	if err := s.validateScript(uploadPath, "content"); err != nil {
+		os.Remove(uploadPath)
+		return nil, err
+	}
+	s.ensureBaseVersion(t)
+	if err := os.Rename(uploadPath, t.ScriptPath); err != nil {
+		os.Remove(uploadPath)
 		return nil, fmt.Errorf("failed to write script: %w", err)
 	}
 
@@ -255,6 +531,7 @@ func (s *TestService) SaveScriptContent(id uuid.UUID, userID uuid.UUID, isRoot b
 	if err := s.testRepo.Update(t); err != nil {
 		return nil, err
 	}
+	s.recordScriptVersion(t, userID, nil)
 
 	return t, nil
 }
@@ -262,3 +539,10 @@ func (s *TestService) SaveScriptContent(id uuid.UUID, userID uuid.UUID, isRoot b
 func (s *TestService) List(filter domain.TestFilter) ([]domain.Test, int64, error) {
 	return s.testRepo.List(filter)
 }
+
+func emptyToNil(s *string) *string {
+	if s == nil || *s == "" {
+		return nil
+	}
+	return s
+}
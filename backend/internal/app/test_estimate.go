@@ -0,0 +1,89 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+// Estimate reports the load a run of the test would generate, without
+// executing it. VUs and duration are resolved like POST /executions and capped
+// to the domain's limits like the runner does; k6 inspect supplies the
+// options the script declares.
+func (s *TestService) Estimate(id uuid.UUID, userID uuid.UUID, isRoot bool, input domain.EstimateTestInput) (*domain.TestEstimate, error) {
+	test, err := s.GetByID(id, userID, isRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	vus := input.VUs
+	if vus <= 0 {
+		vus = test.DefaultVUs
+	}
+	duration := input.Duration
+	if duration == "" {
+		duration = test.DefaultDuration
+	}
+	stages := input.Stages
+	if len(stages) == 0 && input.VUs <= 0 && input.Duration == "" {
+		stages = test.DefaultStages
+	}
+
+	limits, _ := loadK6Limits(s.domainRepo, s.k6Config, test.DomainID)
+	estimate := &domain.TestEstimate{}
+	if len(stages) > 0 {
+		if err := validateStages(stages, limits.MaxVUs); err != nil {
+			return nil, err
+		}
+		_, requested, _ := capStages(stages, math.MaxInt64)
+		_, dur, peak := capStages(stages, limits.MaxDuration)
+		estimate.VUs = min(peak, limits.MaxVUs)
+		estimate.Duration = dur.String()
+		estimate.DurationSeconds = dur.Seconds()
+		estimate.Capped = estimate.VUs < peak || dur < requested
+	} else {
+		requested, err := time.ParseDuration(duration)
+		if err != nil || requested <= 0 {
+			return nil, domain.NewValidationError(map[string]string{
+				"duration": "Must be a duration such as 30s or 5m",
+			})
+		}
+		dur := min(requested, limits.MaxDuration)
+		estimate.VUs = min(vus, limits.MaxVUs)
+		estimate.Duration = dur.String()
+		estimate.DurationSeconds = dur.Seconds()
+		estimate.Capped = estimate.VUs < vus || dur < requested
+	}
+
+	out, err := s.inspectScript(test.ScriptPath, "script")
+	if err != nil {
+		return nil, err
+	}
+	var options domain.JSONMap
+	if err := json.Unmarshal(out, &options); err != nil {
+		return nil, fmt.Errorf("parse k6 inspect output: %w", err)
+	}
+	estimate.Options = domain.JSONMap{}
+	for name, value := range options {
+		if value != nil {
+			estimate.Options[name] = value
+		}
+	}
+
+	if rps, ok := options["rps"].(float64); ok && rps > 0 {
+		requests := int64(rps * estimate.DurationSeconds)
+		estimate.RPS = &rps
+		estimate.EstimatedRequests = &requests
+	}
+	if iterations, ok := options["iterations"].(float64); ok && iterations > 0 {
+		n := int64(iterations)
+		estimate.Iterations = &n
+	}
+
+	return estimate, nil
+}
@@ -0,0 +1,189 @@
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+const (
+	maxThresholdExpressions = 50
+	maxSummaryTrendStats    = 20
+)
+
+// defaultSummaryTrendStats are exported for trend metrics unless the test
+// sets its own summary_trend_stats.
+var defaultSummaryTrendStats = []string{"avg", "min", "med", "max", "p(90)", "p(95)", "p(99)"}
+
+// trendStatRe matches the stats k6 accepts in --summary-trend-stats.
+var trendStatRe = regexp.MustCompile(`^(avg|min|med|max|count|p\((\d+(?:\.\d+)?)\))$`)
+
+// thresholdExprRe matches k6-style expressions such as "p(95)<500",
+// "avg<=200" or "rate<0.01".
+var thresholdExprRe = regexp.MustCompile(`^\s*(avg|min|max|med|count|rate|value|p\((\d+(?:\.\d+)?)\))\s*(<=|>=|==|!=|<|>)\s*(-?\d+(?:\.\d+)?)\s*$`)
+
+type thresholdExpr struct {
+	stat       string
+	percentile string // set for p(N)
+	op         string
+	limit      float64
+}
+
+func parseThresholdExpr(expr string) (thresholdExpr, error) {
+	m := thresholdExprRe.FindStringSubmatch(expr)
+	if m == nil {
+		return thresholdExpr{}, fmt.Errorf("invalid threshold expression %q", expr)
+	}
+	limit, err := strconv.ParseFloat(m[4], 64)
+	if err != nil {
+		return thresholdExpr{}, fmt.Errorf("invalid threshold value in %q", expr)
+	}
+	if m[2] != "" {
+		if p, _ := strconv.ParseFloat(m[2], 64); p <= 0 || p >= 100 {
+			return thresholdExpr{}, fmt.Errorf("percentile out of range in %q", expr)
+		}
+	}
+	return thresholdExpr{stat: m[1], percentile: m[2], op: m[3], limit: limit}, nil
+}
+
+func validateThresholds(thresholds domain.Thresholds) error {
+	total := 0
+	for metric, exprs := range thresholds {
+		if metric == "" || len(metric) > 100 {
+			return domain.NewValidationError(map[string]string{
+				"thresholds": "Metric names must be 1-100 characters",
+			})
+		}
+		for _, expr := range exprs {
+			if _, err := parseThresholdExpr(expr); err != nil {
+				return domain.NewValidationError(map[string]string{
+					"thresholds": err.Error(),
+				})
+			}
+			total++
+		}
+	}
+	if total > maxThresholdExpressions {
+		return domain.NewValidationError(map[string]string{
+			"thresholds": fmt.Sprintf("At most %d threshold expressions allowed", maxThresholdExpressions),
+		})
+	}
+	return nil
+}
+
+// thresholdTrendStats returns the trend stats (p(N), avg, ...) the thresholds
+// need, so they can be requested from k6 via --summary-trend-stats even when
+// the test narrows its summary_trend_stats.
+func thresholdTrendStats(thresholds domain.Thresholds) []string {
+	var stats []string
+	for _, exprs := range thresholds {
+		for _, expr := range exprs {
+			if t, err := parseThresholdExpr(expr); err == nil && trendStatRe.MatchString(t.stat) && !slices.Contains(stats, t.stat) {
+				stats = append(stats, t.stat)
+			}
+		}
+	}
+	sort.Strings(stats)
+	return stats
+}
+
+// evaluateThresholds checks every expression against the metrics of a k6
+// --summary-export document. A threshold whose metric or statistic is
+// missing fails. Results are ordered by metric, then declaration order.
+func evaluateThresholds(thresholds domain.Thresholds, summary domain.JSONMap) (bool, domain.ThresholdResults) {
+	metrics, _ := summary["metrics"].(map[string]interface{})
+
+	names := make([]string, 0, len(thresholds))
+	for name := range thresholds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	passed := true
+	var results domain.ThresholdResults
+	for _, name := range names {
+		values, _ := metrics[name].(map[string]interface{})
+		for _, expr := range thresholds[name] {
+			result := domain.ThresholdResult{Metric: name, Expression: expr}
+			if t, err := parseThresholdExpr(expr); err == nil {
+				if actual, ok := summaryStat(values, t.stat); ok {
+					result.Actual = &actual
+					result.Passed = compareThreshold(actual, t.op, t.limit)
+				}
+			}
+			passed = passed && result.Passed
+			results = append(results, result)
+		}
+	}
+	return passed, results
+}
+
+// summaryStat reads a statistic from a summary-export metric. k6 exports the
+// ratio of Rate metrics as "value", so "rate" falls back to it.
+func summaryStat(values map[string]interface{}, stat string) (float64, bool) {
+	if v, ok := values[stat].(float64); ok {
+		return v, true
+	}
+	if stat == "rate" {
+		if v, ok := values["value"].(float64); ok {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+func compareThreshold(actual float64, op string, limit float64) bool {
+	switch op {
+	case "<":
+		return actual < limit
+	case "<=":
+		return actual <= limit
+	case ">":
+		return actual > limit
+	case ">=":
+		return actual >= limit
+	case "==":
+		return actual == limit
+	case "!=":
+		return actual != limit
+	}
+	return false
+}
+
+// normalizeTrendStats trims and de-duplicates the stats of a test's
+// summary_trend_stats, rejecting any k6 would not accept. An empty list
+// returns nil, which selects the default stats.
+func normalizeTrendStats(stats []string) ([]string, error) {
+	var out []string
+	for _, stat := range stats {
+		stat = strings.TrimSpace(stat)
+		if stat == "" || slices.Contains(out, stat) {
+			continue
+		}
+		m := trendStatRe.FindStringSubmatch(stat)
+		if m == nil {
+			return nil, domain.NewValidationError(map[string]string{
+				"summary_trend_stats": fmt.Sprintf("Invalid stat %q: use avg, min, med, max, count or p(N)", stat),
+			})
+		}
+		if m[2] != "" {
+			if p, _ := strconv.ParseFloat(m[2], 64); p <= 0 || p > 100 {
+				return nil, domain.NewValidationError(map[string]string{
+					"summary_trend_stats": fmt.Sprintf("Percentile out of range in %q", stat),
+				})
+			}
+		}
+		out = append(out, stat)
+	}
+	if len(out) > maxSummaryTrendStats {
+		return nil, domain.NewValidationError(map[string]string{
+			"summary_trend_stats": fmt.Sprintf("At most %d stats allowed", maxSummaryTrendStats),
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,96 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+// thresholdSummary is a k6 --summary-export document with a trend, a rate
+// and a counter metric.
+var thresholdSummary = domain.JSONMap{
+	"metrics": map[string]interface{}{
+		"http_req_duration": map[string]interface{}{"avg": 120.0, "p(95)": 480.0, "p(99.9)": 900.0, "max": 1200.0},
+		"http_req_failed":   map[string]interface{}{"value": 0.02, "passes": 2.0, "fails": 98.0},
+		"http_reqs":         map[string]interface{}{"count": 100.0, "rate": 10.0},
+	},
+}
+
+func TestEvaluateThresholds(t *testing.T) {
+	tests := []struct {
+		name       string
+		metric     string
+		expr       string
+		wantPassed bool
+		wantActual *float64
+	}{
+		{name: "percentile passes", metric: "http_req_duration", expr: "p(95)<500", wantPassed: true, wantActual: ptrFloat(480)},
+		{name: "percentile fails", metric: "http_req_duration", expr: "p(95)<400", wantActual: ptrFloat(480)},
+		{name: "fractional percentile", metric: "http_req_duration", expr: "p(99.9)<=900", wantPassed: true, wantActual: ptrFloat(900)},
+		{name: "avg with spaces", metric: "http_req_duration", expr: " avg < 200 ", wantPassed: true, wantActual: ptrFloat(120)},
+		{name: "rate falls back to value", metric: "http_req_failed", expr: "rate<0.01", wantActual: ptrFloat(0.02)},
+		{name: "rate of a counter", metric: "http_reqs", expr: "rate>=10", wantPassed: true, wantActual: ptrFloat(10)},
+		{name: "count", metric: "http_reqs", expr: "count==100", wantPassed: true, wantActual: ptrFloat(100)},
+		{name: "not equal", metric: "http_reqs", expr: "count!=100", wantActual: ptrFloat(100)},
+		{name: "greater than", metric: "http_req_duration", expr: "max>1000", wantPassed: true, wantActual: ptrFloat(1200)},
+		{name: "missing stat fails", metric: "http_req_duration", expr: "p(90)<500"},
+		{name: "missing metric fails", metric: "checks", expr: "rate>0.99"},
+		{name: "invalid expression fails", metric: "http_req_duration", expr: "p95<500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			passed, results := evaluateThresholds(domain.Thresholds{tt.metric: {tt.expr}}, thresholdSummary)
+			if passed != tt.wantPassed {
+				t.Errorf("passed = %v, want %v", passed, tt.wantPassed)
+			}
+			if len(results) != 1 {
+				t.Fatalf("got %d results, want 1", len(results))
+			}
+			r := results[0]
+			if r.Metric != tt.metric || r.Expression != tt.expr || r.Passed != tt.wantPassed {
+				t.Errorf("result = %+v", r)
+			}
+			switch {
+			case tt.wantActual == nil && r.Actual != nil:
+				t.Errorf("actual = %v, want nil", *r.Actual)
+			case tt.wantActual != nil && (r.Actual == nil || *r.Actual != *tt.wantActual):
+				t.Errorf("actual = %v, want %v", r.Actual, *tt.wantActual)
+			}
+		})
+	}
+}
+
+func TestEvaluateThresholdsOrderAndOverall(t *testing.T) {
+	thresholds := domain.Thresholds{
+		"http_reqs":         {"count>0"},
+		"http_req_duration": {"p(95)<500", "avg<100"},
+	}
+	passed, results := evaluateThresholds(thresholds, thresholdSummary)
+	if passed {
+		t.Error("passed = true, want false when any expression fails")
+	}
+
+	want := []struct {
+		metric, expr string
+		passed       bool
+	}{
+		{"http_req_duration", "p(95)<500", true},
+		{"http_req_duration", "avg<100", false},
+		{"http_reqs", "count>0", true},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(results), len(want))
+	}
+	for i, w := range want {
+		if results[i].Metric != w.metric || results[i].Expression != w.expr || results[i].Passed != w.passed {
+			t.Errorf("result %d = %+v, want %+v", i, results[i], w)
+		}
+	}
+
+	if passed, results := evaluateThresholds(domain.Thresholds{}, thresholdSummary); !passed || len(results) != 0 {
+		t.Errorf("no thresholds = %v, %v; want pass with no results", passed, results)
+	}
+}
+
+func ptrFloat(v float64) *float64 { return &v }
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the RFC 6238 SHA-1 test key "12345678901234567890" in base32.
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestValidateTOTP(t *testing.T) {
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		now      int64
+		wantOK   bool
+		wantStep int64
+	}{
+		// RFC 6238 appendix B vectors, truncated to 6 digits
+		{name: "rfc vector 59", secret: rfc6238Secret, code: "287082", now: 59, wantOK: true, wantStep: 1},
+		{name: "rfc vector 1111111109", secret: rfc6238Secret, code: "081804", now: 1111111109, wantOK: true, wantStep: 37037036},
+		{name: "rfc vector 1234567890", secret: rfc6238Secret, code: "005924", now: 1234567890, wantOK: true, wantStep: 41152263},
+		{name: "lowercase secret and padding spaces", secret: "gezdgnbvgy3tqojqgezdgnbvgy3tqojq", code: " 287082 ", now: 59, wantOK: true, wantStep: 1},
+		{name: "previous step within skew", secret: rfc6238Secret, code: "287082", now: 59 + totpPeriod, wantOK: true, wantStep: 1},
+		{name: "next step within skew", secret: rfc6238Secret, code: "287082", now: 59 - totpPeriod, wantOK: true, wantStep: 1},
+		{name: "outside skew", secret: rfc6238Secret, code: "287082", now: 59 + 2*totpPeriod},
+		{name: "wrong code", secret: rfc6238Secret, code: "000000", now: 59},
+		{name: "wrong length", secret: rfc6238Secret, code: "28708", now: 59},
+		{name: "invalid secret", secret: "not base32!", code: "287082", now: 59},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := validateTOTP(tt.secret, tt.code, time.Unix(tt.now, 0))
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && step != tt.wantStep {
+				t.Errorf("step = %d, want %d", step, tt.wantStep)
+			}
+		})
+	}
+}
+
+func TestGenerateTOTPSecretRoundTrip(t *testing.T) {
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("secret %q is not base32: %v", secret, err)
+	}
+	now := time.Now()
+	code := totpCode(key, uint64(now.Unix())/totpPeriod)
+	if _, ok := validateTOTP(secret, code, now); !ok {
+		t.Errorf("code %s for a fresh secret was rejected", code)
+	}
+}
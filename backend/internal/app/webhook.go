@@ -0,0 +1,149 @@
+package app
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/domain"
+)
+
+const (
+	webhookTimeout  = 5 * time.Second
+	webhookAttempts = 3
+
+	// WebhookSignatureHeader carries "sha256=<hex HMAC of the body>" when the
+	// test has a webhook secret.
+	WebhookSignatureHeader = "X-StressTest-Signature"
+)
+
+// WebhookPayload is POSTed to a test's webhook URL when an execution finishes.
+type WebhookPayload struct {
+	Event          string            `json:"event"`
+	ExecutionID    uuid.UUID         `json:"execution_id"`
+	TestID         uuid.UUID         `json:"test_id"`
+	TestName       string            `json:"test_name"`
+	Status         domain.TestStatus `json:"status"`
+	VUs            int               `json:"vus"`
+	Duration       string            `json:"duration"`
+	StartedAt      *time.Time        `json:"started_at,omitempty"`
+	CompletedAt    *time.Time        `json:"completed_at,omitempty"`
+	ErrorMessage   *string           `json:"error_message,omitempty"`
+	MetricsSummary domain.JSONMap    `json:"metrics_summary,omitempty"`
+	// Set when the test has a baseline execution
+	Regressed          *bool                      `json:"regressed,omitempty"`
+	BaselineComparison *domain.BaselineComparison `json:"baseline_comparison,omitempty"`
+}
+
+// WebhookNotifier delivers execution results to per-test webhook URLs.
+type WebhookNotifier struct {
+	client  *http.Client
+	backoff time.Duration
+}
+
+func NewWebhookNotifier() *WebhookNotifier {
+	return &WebhookNotifier{
+		client:  &http.Client{Timeout: webhookTimeout},
+		backoff: time.Second,
+	}
+}
+
+// Notify sends the result in the background; it never blocks the caller.
+func (n *WebhookNotifier) Notify(test *domain.Test, execution *domain.TestExecution) {
+	if test.WebhookURL == nil || *test.WebhookURL == "" {
+		return
+	}
+
+	body, err := json.Marshal(WebhookPayload{
+		Event:          "execution.finished",
+		ExecutionID:    execution.ID,
+		TestID:         test.ID,
+		TestName:       test.Name,
+		Status:         execution.Status,
+		VUs:            execution.VUs,
+		Duration:       execution.Duration,
+		StartedAt:      execution.StartedAt,
+		CompletedAt:    execution.CompletedAt,
+		ErrorMessage:   execution.ErrorMessage,
+		MetricsSummary: execution.MetricsSummary,
+
+		Regressed:          execution.Regressed,
+		BaselineComparison: execution.BaselineComparison,
+	})
+	if err != nil {
+		log.Printf("[Webhook] Failed to encode payload for execution %s: %v", execution.ID, err)
+		return
+	}
+
+	var secret string
+	if test.WebhookSecret != nil {
+		secret = *test.WebhookSecret
+	}
+
+	go n.deliver(*test.WebhookURL, secret, execution.ID, body)
+}
+
+func (n *WebhookNotifier) deliver(target, secret string, execID uuid.UUID, body []byte) {
+	var lastErr error
+	for attempt := 1; attempt <= webhookAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(n.backoff * time.Duration(attempt-1))
+		}
+		if lastErr = n.post(target, secret, body); lastErr == nil {
+			return
+		}
+	}
+	log.Printf("[Webhook] Failed to deliver execution %s after %d attempts: %v", execID, webhookAttempts, lastErr)
+}
+
+func (n *WebhookNotifier) post(target, secret string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "StressTestPlatform-Webhook/1.0")
+	if secret != "" {
+		req.Header.Set(WebhookSignatureHeader, "sha256="+signWebhookBody(secret, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func signWebhookBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// validateWebhookURL accepts an empty string (no webhook) or an absolute
+// http(s) URL.
+func validateWebhookURL(raw string) error {
+	if raw == "" {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(raw) > 2048 {
+		return domain.NewValidationError(map[string]string{
+			"webhook_url": "Must be an absolute http(s) URL",
+		})
+	}
+	return nil
+}
@@ -15,6 +15,16 @@ type Session struct {
 	ExpiresAt time.Time  `json:"expires_at"`
 	CreatedAt time.Time  `json:"created_at"`
 	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+
+	// Refresh rotation chain: FamilyID is the ID of the login session that
+	// started it, ParentTokenHash the token this session replaced. RotatedAt
+	// is set once the session's own token has been exchanged.
+	FamilyID        uuid.UUID  `json:"-"`
+	ParentTokenHash *string    `json:"-"`
+	RotatedAt       *time.Time `json:"-"`
+
+	// Current is set when listing, for the session the caller is using
+	Current bool `json:"current"`
 }
 
 func (s *Session) IsExpired() bool {
@@ -41,16 +51,76 @@ type LoginResponse struct {
 	User         User   `json:"user"`
 }
 
+// TwoFactorChallenge is returned by login instead of a LoginResponse when the
+// user has TOTP enabled. The challenge token is exchanged at /auth/2fa/login.
+type TwoFactorChallenge struct {
+	TwoFactorRequired bool      `json:"two_factor_required"`
+	ChallengeToken    string    `json:"challenge_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+type TwoFactorLoginInput struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+}
+
+type TwoFactorSetup struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+	QRPayload  string `json:"qr_payload"`
+}
+
 type TokenClaims struct {
 	UserID uuid.UUID `json:"user_id"`
 	Email  string    `json:"email"`
 	Role   UserRole  `json:"role"`
+	// ActorID is the ROOT user acting as UserID, set only on impersonation tokens
+	ActorID *uuid.UUID `json:"act,omitempty"`
+	// SessionID is the FamilyID of the login session the token was issued
+	// for; nil for API keys and impersonation tokens
+	SessionID *uuid.UUID `json:"sid,omitempty"`
+}
+
+type PasswordReset struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (p *PasswordReset) IsValid() bool {
+	return p.UsedAt == nil && time.Now().Before(p.ExpiresAt)
+}
+
+type ForgotPasswordInput struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordInput struct {
+	Token           string `json:"token"`
+	NewPassword     string `json:"new_password"`
+	ConfirmPassword string `json:"confirm_password"`
+}
+
+type PasswordResetRepository interface {
+	Create(reset *PasswordReset) error
+	GetByTokenHash(hash string) (*PasswordReset, error)
+	// MarkUsed flags the reset as consumed; it returns false if it was already used.
+	MarkUsed(id uuid.UUID) (bool, error)
+	InvalidateAllForUser(userID uuid.UUID) error
 }
 
 type SessionRepository interface {
 	Create(session *Session) error
+	GetByID(id uuid.UUID) (*Session, error)
 	GetByTokenHash(hash string) (*Session, error)
+	ListByUser(userID uuid.UUID) ([]Session, error)
 	Revoke(id uuid.UUID) error
+	// Rotate revokes a session whose token was exchanged by a refresh. It
+	// returns false when the session was already revoked.
+	Rotate(id uuid.UUID) (bool, error)
 	RevokeAllForUser(userID uuid.UUID) error
-	CleanExpired() error
+	CleanExpired() (int64, error)
 }
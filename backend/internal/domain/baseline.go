@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// BaselineComparison is how an execution measured up against the baseline
+// execution of its test. P95 comes from the k6 summary export and error
+// rates are percentages, as in metrics_summary. A value missing on either
+// side is not compared.
+type BaselineComparison struct {
+	BaselineExecutionID uuid.UUID `json:"baseline_execution_id"`
+	P95Ms               *float64  `json:"p95_ms,omitempty"`
+	BaselineP95Ms       *float64  `json:"baseline_p95_ms,omitempty"`
+	ErrorRate           *float64  `json:"error_rate,omitempty"`
+	BaselineErrorRate   *float64  `json:"baseline_error_rate,omitempty"`
+	Regressions         []string  `json:"regressions,omitempty"` // one line per regressed value
+}
+
+func (c *BaselineComparison) Scan(value interface{}) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("unsupported type for BaselineComparison scan")
+	}
+	return json.Unmarshal(bytes, c)
+}
+
+func (c BaselineComparison) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// CheckResult aggregates the outcomes of one k6 check() over an execution.
+// Checks with the same name in different groups are reported separately.
+type CheckResult struct {
+	Name   string  `json:"name"`
+	Group  string  `json:"group,omitempty"`
+	Passes int64   `json:"passes"`
+	Fails  int64   `json:"fails"`
+	Rate   float64 `json:"rate"` // passes / (passes + fails)
+}
+
+type CheckResults []CheckResult
+
+func (r *CheckResults) Scan(value interface{}) error {
+	if value == nil {
+		*r = nil
+		return nil
+	}
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("unsupported type for CheckResults scan")
+	}
+	return json.Unmarshal(bytes, r)
+}
+
+func (r CheckResults) Value() (driver.Value, error) {
+	if len(r) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(r)
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"time"
 )
 
 type JSONMap map[string]interface{}
@@ -32,6 +33,9 @@ func (j JSONMap) Value() (driver.Value, error) {
 	return json.Marshal(j)
 }
 
+// MaxPageSize caps how many rows a single list query returns.
+const MaxPageSize = 100
+
 type Pagination struct {
 	Page     int `json:"page"`
 	PageSize int `json:"page_size"`
@@ -42,11 +46,12 @@ func DefaultPagination() Pagination {
 }
 
 func (p Pagination) Offset() int {
-	return (p.Page - 1) * p.PageSize
+	return (max(p.Page, 1) - 1) * p.Limit()
 }
 
+// Limit is the page size clamped to [1, MaxPageSize].
 func (p Pagination) Limit() int {
-	return p.PageSize
+	return min(max(p.PageSize, 1), MaxPageSize)
 }
 
 type PaginatedResult[T any] struct {
@@ -58,15 +63,43 @@ type PaginatedResult[T any] struct {
 }
 
 func NewPaginatedResult[T any](data []T, total int64, pagination Pagination) PaginatedResult[T] {
-	totalPages := int(total) / pagination.PageSize
-	if int(total)%pagination.PageSize > 0 {
+	pageSize := pagination.Limit()
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
 		totalPages++
 	}
 	return PaginatedResult[T]{
 		Data:       data,
 		Total:      total,
 		Page:       pagination.Page,
-		PageSize:   pagination.PageSize,
+		PageSize:   pageSize,
 		TotalPages: totalPages,
 	}
 }
+
+// RateLimiter is a token bucket per key: up to limit takes per window, refilled
+// continuously. When a take is refused, retryAfter says when a token is back.
+// Refund gives back a token taken for something that did not happen.
+type RateLimiter interface {
+	Take(key string, limit int, window time.Duration) (allowed bool, retryAfter time.Duration, err error)
+	Refund(key string, limit int, window time.Duration) error
+}
+
+// IdempotencyStore maps client-supplied idempotency keys to the ID of what the
+// first request with the key created, and a fingerprint of that request.
+// Reserve claims an unused key for fingerprint; for a key already claimed it
+// returns the stored ID ("" while that request is still in flight) and the
+// fingerprint it was claimed with.
+type IdempotencyStore interface {
+	Reserve(key, fingerprint string, ttl time.Duration) (reserved bool, id, storedFingerprint string, err error)
+	Complete(key, fingerprint, id string, ttl time.Duration) error
+	Release(key string) error
+}
+
+// LeaderLock elects one holder among the instances sharing it. Acquire takes
+// the lock, or extends it when owner already holds it, for ttl; it returns
+// false while another owner holds it. Release gives it up if owner holds it.
+type LeaderLock interface {
+	Acquire(owner string, ttl time.Duration) (bool, error)
+	Release(owner string) error
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "testing"
+
+func TestPaginationLimitAndOffset(t *testing.T) {
+	tests := []struct {
+		name       string
+		pagination Pagination
+		wantLimit  int
+		wantOffset int
+	}{
+		{name: "default", pagination: DefaultPagination(), wantLimit: 20, wantOffset: 0},
+		{name: "third page", pagination: Pagination{Page: 3, PageSize: 25}, wantLimit: 25, wantOffset: 50},
+		{name: "at max", pagination: Pagination{Page: 1, PageSize: MaxPageSize}, wantLimit: MaxPageSize, wantOffset: 0},
+		{name: "above max", pagination: Pagination{Page: 2, PageSize: 10000}, wantLimit: MaxPageSize, wantOffset: MaxPageSize},
+		{name: "zero size", pagination: Pagination{Page: 2, PageSize: 0}, wantLimit: 1, wantOffset: 1},
+		{name: "negative size", pagination: Pagination{Page: 1, PageSize: -5}, wantLimit: 1, wantOffset: 0},
+		{name: "zero page", pagination: Pagination{Page: 0, PageSize: 10}, wantLimit: 10, wantOffset: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.pagination.Limit(); got != tt.wantLimit {
+				t.Errorf("Limit() = %d, want %d", got, tt.wantLimit)
+			}
+			if got := tt.pagination.Offset(); got != tt.wantOffset {
+				t.Errorf("Offset() = %d, want %d", got, tt.wantOffset)
+			}
+		})
+	}
+}
+
+func TestNewPaginatedResultUsesClampedLimit(t *testing.T) {
+	result := NewPaginatedResult([]int{}, 250, Pagination{Page: 1, PageSize: 10000})
+	if result.PageSize != MaxPageSize {
+		t.Errorf("PageSize = %d, want %d", result.PageSize, MaxPageSize)
+	}
+	if result.TotalPages != 3 {
+		t.Errorf("TotalPages = %d, want 3", result.TotalPages)
+	}
+}
@@ -7,28 +7,42 @@ import (
 )
 
 type Domain struct {
-	ID          uuid.UUID  `json:"id"`
-	UserID      uuid.UUID  `json:"user_id"`
-	Name        string     `json:"name"`
-	Description *string    `json:"description,omitempty"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
-	DeletedAt   *time.Time `json:"-"`
+	ID            uuid.UUID  `json:"id"`
+	UserID        uuid.UUID  `json:"user_id"`
+	Name          string     `json:"name"`
+	Description   *string    `json:"description,omitempty"`
+	Tags          []string   `json:"tags"`
+	MaxVUs        *int       `json:"max_vus,omitempty"`        // set by ROOT; nil uses K6_MAX_VUS
+	MaxDuration   *string    `json:"max_duration,omitempty"`   // set by ROOT; nil uses K6_MAX_DURATION
+	MaxConcurrent *int       `json:"max_concurrent,omitempty"` // per user, set by ROOT; nil uses K6_MAX_CONCURRENT
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	DeletedAt     *time.Time `json:"-"`
 }
 
 type CreateDomainInput struct {
-	Name        string  `json:"name"`
-	Description *string `json:"description,omitempty"`
+	Name        string   `json:"name"`
+	Description *string  `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
 }
 
 type UpdateDomainInput struct {
-	Name        *string `json:"name,omitempty"`
-	Description *string `json:"description,omitempty"`
+	Name        *string  `json:"name,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"` // an empty array clears them
+
+	// Limit overrides, ROOT only; 0 or "" goes back to the global config
+	MaxVUs        *int    `json:"max_vus,omitempty"`
+	MaxDuration   *string `json:"max_duration,omitempty"`
+	MaxConcurrent *int    `json:"max_concurrent,omitempty"`
 }
 
 type DomainFilter struct {
 	UserID *uuid.UUID `json:"user_id,omitempty"`
 	Search *string    `json:"search,omitempty"`
+	// Tags matches domains with any of the tags, or all of them with MatchAllTags
+	Tags         []string `json:"tags,omitempty"`
+	MatchAllTags bool     `json:"match_all_tags,omitempty"`
 	Pagination
 }
 
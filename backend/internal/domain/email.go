@@ -0,0 +1,14 @@
+package domain
+
+// EmailMessage is a plain-text email.
+type EmailMessage struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers email. Send blocks until the server accepted the message;
+// callers that must not block send from a goroutine.
+type Mailer interface {
+	Send(msg EmailMessage) error
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 )
 
 var (
@@ -31,6 +32,7 @@ type AppError struct {
 	Details    map[string]string `json:"details,omitempty"`
 	StatusCode int               `json:"-"`
 	Err        error             `json:"-"`
+	RetryAfter time.Duration     `json:"-"` // sent as the Retry-After header when set
 }
 
 func (e *AppError) Error() string {
@@ -59,6 +61,7 @@ func (e *AppError) WithError(err error) *AppError {
 		Details:    e.Details,
 		StatusCode: e.StatusCode,
 		Err:        err,
+		RetryAfter: e.RetryAfter,
 	}
 }
 
@@ -69,6 +72,18 @@ func (e *AppError) WithDetails(details map[string]string) *AppError {
 		Details:    details,
 		StatusCode: e.StatusCode,
 		Err:        e.Err,
+		RetryAfter: e.RetryAfter,
+	}
+}
+
+func (e *AppError) WithRetryAfter(d time.Duration) *AppError {
+	return &AppError{
+		Code:       e.Code,
+		Message:    e.Message,
+		Details:    e.Details,
+		StatusCode: e.StatusCode,
+		Err:        e.Err,
+		RetryAfter: d,
 	}
 }
 
@@ -142,3 +157,11 @@ func IsConflict(err error) bool {
 	}
 	return false
 }
+
+func IsTooManyRequests(err error) bool {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
@@ -1,6 +1,10 @@
 package domain
 
 import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,23 +21,55 @@ const (
 	TestStatusTimeout   TestStatus = "TIMEOUT"
 )
 
+// ExitReason tells why a run did not complete: the test ran but breached its
+// thresholds, the script itself failed, or k6 was stopped by the platform.
+type ExitReason string
+
+const (
+	ExitReasonThresholdFailed ExitReason = "threshold_failed"
+	ExitReasonScriptError     ExitReason = "script_error"
+	ExitReasonTimeout         ExitReason = "timeout"
+	ExitReasonOOM             ExitReason = "oom"
+	ExitReasonUnknown         ExitReason = "unknown"
+)
+
 type TestExecution struct {
-	ID             uuid.UUID  `json:"id"`
-	TestID         uuid.UUID  `json:"test_id"`
-	UserID         uuid.UUID  `json:"user_id"`
-	ScheduleID     *uuid.UUID `json:"schedule_id,omitempty"`
-	VUs            int        `json:"vus"`
-	Duration       string     `json:"duration"`
-	Status         TestStatus `json:"status"`
-	StartedAt      *time.Time `json:"started_at,omitempty"`
-	CompletedAt    *time.Time `json:"completed_at,omitempty"`
-	ExitCode       *int       `json:"exit_code,omitempty"`
-	Stdout         *string    `json:"stdout,omitempty"`
-	Stderr         *string    `json:"stderr,omitempty"`
-	MetricsSummary JSONMap    `json:"metrics_summary,omitempty"`
-	ErrorMessage   *string    `json:"error_message,omitempty"`
-	CreatedAt      time.Time  `json:"created_at"`
-	UpdatedAt      time.Time  `json:"updated_at"`
+	ID                uuid.UUID        `json:"id"`
+	TestID            uuid.UUID        `json:"test_id"`
+	UserID            uuid.UUID        `json:"user_id"`
+	ScheduleID        *uuid.UUID       `json:"schedule_id,omitempty"`
+	VUs               int              `json:"vus"`
+	Duration          string           `json:"duration"`
+	EffectiveVUs      *int             `json:"effective_vus,omitempty"`      // after K6_MAX_VUS, set when the run starts
+	EffectiveDuration *string          `json:"effective_duration,omitempty"` // after K6_MAX_DURATION
+	Capped            bool             `json:"capped"`                       // the effective load is below the requested one
+	Status            TestStatus       `json:"status"`
+	StartedAt         *time.Time       `json:"started_at,omitempty"`
+	CompletedAt       *time.Time       `json:"completed_at,omitempty"`
+	AggregatedAt      *time.Time       `json:"aggregated_at,omitempty"` // when its metrics were aggregated
+	ExitCode          *int             `json:"exit_code,omitempty"`
+	ExitReason        *ExitReason      `json:"exit_reason,omitempty"` // set when the run failed or timed out
+	Stdout            *string          `json:"stdout,omitempty"`
+	Stderr            *string          `json:"stderr,omitempty"`
+	MetricsSummary    JSONMap          `json:"metrics_summary,omitempty"`
+	SummaryExport     JSONMap          `json:"summary_export,omitempty"` // k6 --summary-export output, only loaded by GetByID
+	Targets           Targets          `json:"targets,omitempty"`
+	Env               EnvVars          `json:"env,omitempty"`
+	Stages            Stages           `json:"stages,omitempty"`
+	ErrorMessage      *string          `json:"error_message,omitempty"`
+	ThresholdsPassed  *bool            `json:"thresholds_passed,omitempty"`
+	ThresholdResults  ThresholdResults `json:"threshold_results,omitempty"`
+	// Regressed is nil when the run was not compared with a baseline
+	Regressed          *bool               `json:"regressed,omitempty"`
+	BaselineComparison *BaselineComparison `json:"baseline_comparison,omitempty"`
+	CheckResults       CheckResults        `json:"check_results,omitempty"`
+	MaxRetries         *int                `json:"max_retries,omitempty"`   // overrides the test default
+	RetryBackoff       *string             `json:"retry_backoff,omitempty"` // overrides the test default
+	Attempts           int                 `json:"attempts"`
+	Notes              *string             `json:"notes,omitempty"`
+	CaptureErrors      bool                `json:"capture_errors"` // log sample failing responses into error_samples
+	CreatedAt          time.Time           `json:"created_at"`
+	UpdatedAt          time.Time           `json:"updated_at"`
 
 	// Joined fields
 	TestName   *string `json:"test_name,omitempty"`
@@ -42,28 +78,242 @@ type TestExecution struct {
 	UserEmail  *string `json:"user_email,omitempty"`
 }
 
+// Target is a base URL the script may send traffic to. Weight is relative to
+// the other targets of the same execution; zero means the default weight of 1.
+type Target struct {
+	URL    string `json:"url"`
+	Weight int    `json:"weight,omitempty"`
+}
+
+// Targets is passed to k6 as the TARGETS env var and stored as JSONB.
+type Targets []Target
+
+func (t *Targets) Scan(value interface{}) error {
+	if value == nil {
+		*t = nil
+		return nil
+	}
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("unsupported type for Targets scan")
+	}
+	return json.Unmarshal(bytes, t)
+}
+
+func (t Targets) Value() (driver.Value, error) {
+	if len(t) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(t)
+}
+
+// Stage is one step of a k6 ramping profile: ramp to Target VUs over Duration.
+type Stage struct {
+	Duration string `json:"duration"`
+	Target   int    `json:"target"`
+}
+
+// Stages replace the constant vus/duration with k6 --stage flags and are
+// stored as JSONB.
+type Stages []Stage
+
+func (s *Stages) Scan(value interface{}) error {
+	if value == nil {
+		*s = nil
+		return nil
+	}
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("unsupported type for Stages scan")
+	}
+	return json.Unmarshal(bytes, s)
+}
+
+func (s Stages) Value() (driver.Value, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(s)
+}
+
+// EnvVars are passed to k6 as --env KEY=VALUE flags and stored as JSONB.
+type EnvVars map[string]string
+
+func (e *EnvVars) Scan(value interface{}) error {
+	if value == nil {
+		*e = nil
+		return nil
+	}
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("unsupported type for EnvVars scan")
+	}
+	return json.Unmarshal(bytes, e)
+}
+
+func (e EnvVars) Value() (driver.Value, error) {
+	if len(e) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(e)
+}
+
 type CreateExecutionInput struct {
 	TestID   uuid.UUID `json:"test_id"`
 	VUs      int       `json:"vus"`
 	Duration string    `json:"duration"`
+	Targets  Targets   `json:"targets,omitempty"`
+	Env      EnvVars   `json:"env,omitempty"`
+	// Stages override vus/duration; when omitted the test's default stages
+	// are used unless vus or duration is given.
+	Stages Stages `json:"stages,omitempty"`
+
+	// IgnoreCooldown lets a manual run start while the test is still cooling down.
+	IgnoreCooldown bool `json:"ignore_cooldown,omitempty"`
+
+	// Retry settings; nil falls back to the test defaults.
+	MaxRetries   *int    `json:"max_retries,omitempty"`
+	RetryBackoff *string `json:"retry_backoff,omitempty"`
+
+	// CaptureErrors keeps the first failing responses of the run for debugging.
+	CaptureErrors bool `json:"capture_errors,omitempty"`
+}
+
+// UpdateExecutionNotesInput replaces the notes of an execution; an empty
+// string clears them.
+type UpdateExecutionNotesInput struct {
+	Notes string `json:"notes"`
+}
+
+// ErrorSample is a failing HTTP response captured from a run started with
+// capture_errors. Body is cut to a fixed size; Truncated tells when it was.
+type ErrorSample struct {
+	ID          uuid.UUID `json:"id"`
+	ExecutionID uuid.UUID `json:"execution_id"`
+	Method      string    `json:"method"`
+	URL         string    `json:"url"`
+	Status      int       `json:"status"`
+	Body        string    `json:"body"`
+	Truncated   bool      `json:"truncated"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// UsageFilter selects the executions counted by the usage statistics: those
+// created within [From, To], of one user or of everyone.
+type UsageFilter struct {
+	UserID *uuid.UUID
+	From   *time.Time
+	To     *time.Time
+}
+
+// UsageStats is the load-testing capacity consumed by a user (or by all users
+// when UserID is nil). VUMinutes sums effective VUs times run time over the
+// finished runs; AvgErrorRate is the mean of the per-run error rates, in percent.
+type UsageStats struct {
+	UserID        *uuid.UUID `json:"user_id,omitempty"`
+	UserName      string     `json:"user_name,omitempty"`
+	UserEmail     string     `json:"user_email,omitempty"`
+	Executions    int64      `json:"executions"`
+	TotalRequests int64      `json:"total_requests"`
+	VUMinutes     float64    `json:"vu_minutes"`
+	AvgErrorRate  float64    `json:"avg_error_rate"`
 }
 
+// UsageReport is the platform-wide usage with a per-user breakdown, heaviest
+// users (by VU-minutes) first.
+type UsageReport struct {
+	From   *time.Time   `json:"from,omitempty"`
+	To     *time.Time   `json:"to,omitempty"`
+	Totals UsageStats   `json:"totals"`
+	Users  []UsageStats `json:"users"`
+}
+
+// Sort keys accepted by the execution list. ExecutionSortDuration orders by
+// how long the run took (completed_at - started_at); runs that have not
+// finished sort last.
+const (
+	ExecutionSortCreatedAt = "created_at"
+	ExecutionSortDuration  = "duration"
+	ExecutionSortStatus    = "status"
+)
+
 type ExecutionFilter struct {
-	UserID     *uuid.UUID  `json:"user_id,omitempty"`
-	TestID     *uuid.UUID  `json:"test_id,omitempty"`
-	Status     *TestStatus `json:"status,omitempty"`
-	AllUsers   bool        `json:"all_users,omitempty"`
+	UserID   *uuid.UUID  `json:"user_id,omitempty"`
+	TestID   *uuid.UUID  `json:"test_id,omitempty"`
+	Status   *TestStatus `json:"status,omitempty"`
+	AllUsers bool        `json:"all_users,omitempty"`
+	Search   *string     `json:"search,omitempty"` // test or domain name
+	From     *time.Time  `json:"from,omitempty"`   // created_at >= From
+	To       *time.Time  `json:"to,omitempty"`     // created_at <= To
+	Sort     string      `json:"sort,omitempty"`   // an ExecutionSort* key, created_at by default
+	Asc      bool        `json:"asc,omitempty"`    // newest/largest first by default
 	Pagination
 }
 
+// ExecutionDeleteFilter selects finished executions for bulk deletion.
+// PENDING and RUNNING executions are never matched.
+type ExecutionDeleteFilter struct {
+	UserID    *uuid.UUID  `json:"-"`
+	TestID    *uuid.UUID  `json:"test_id,omitempty"`
+	Status    *TestStatus `json:"status,omitempty"`
+	OlderThan *time.Time  `json:"older_than,omitempty"`
+}
+
+// ExecutionEvent is published whenever an execution changes status.
+type ExecutionEvent struct {
+	ExecutionID uuid.UUID  `json:"execution_id"`
+	TestID      uuid.UUID  `json:"test_id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	Status      TestStatus `json:"status"`
+	Timestamp   time.Time  `json:"timestamp"`
+}
+
+// ExecutionEventBus fans execution events out to every API instance. The
+// subscription channel is closed once ctx is done.
+type ExecutionEventBus interface {
+	Publish(event ExecutionEvent) error
+	Subscribe(ctx context.Context) (<-chan ExecutionEvent, error)
+}
+
 type ExecutionRepository interface {
 	Create(exec *TestExecution) error
 	GetByID(id uuid.UUID) (*TestExecution, error)
 	Update(exec *TestExecution) error
+	UpdateSummaryExport(id uuid.UUID, summary JSONMap) error
+	UpdateNotes(id uuid.UUID, notes *string) error
+	AddErrorSamples(executionID uuid.UUID, samples []ErrorSample) error
+	ListErrorSamples(executionID uuid.UUID) ([]ErrorSample, error)
 	Delete(id uuid.UUID) error
+	Discard(id uuid.UUID) error
 	DeleteByTestID(testID uuid.UUID) (int64, error)
+	DeleteByFilter(filter ExecutionDeleteFilter, dryRun bool) (int64, error)
+	Restore(id uuid.UUID) error
+	PurgeDeleted(before time.Time) (int64, error)
 	List(filter ExecutionFilter) ([]TestExecution, int64, error)
 	CountRunningByUser(userID uuid.UUID) (int, error)
+	GetLastCompletedAt(testID uuid.UUID) (*time.Time, error)
+	GetLatestBySchedule(scheduleID uuid.UUID) (*TestExecution, error)
 	MarkOrphansAsFailed() (int, error)
 	GetStats() (map[string]interface{}, error)
+	CountByStatus() (map[TestStatus]int64, error)
+	// LastAggregationLag is aggregated_at - completed_at of the execution
+	// aggregated most recently; ok is false before any aggregation.
+	LastAggregationLag() (lag time.Duration, ok bool, err error)
+	Usage(filter UsageFilter) (*UsageStats, error)
+	UsageByUser(filter UsageFilter) ([]UsageStats, error)
 }
@@ -0,0 +1,27 @@
+package domain
+
+// GrafanaDashboard identifies a dashboard provisioned in Grafana.
+type GrafanaDashboard struct {
+	UID string `json:"uid"`
+	URL string `json:"url"`
+}
+
+// GrafanaUser is the Grafana account linked to a platform user.
+type GrafanaUser struct {
+	ID    int    `json:"id"`
+	Login string `json:"login"`
+}
+
+// GrafanaFolder is a dashboard folder; each user gets a personal one.
+type GrafanaFolder struct {
+	UID   string `json:"uid"`
+	Title string `json:"title"`
+}
+
+// GrafanaProvisioner creates Grafana resources for platform objects.
+type GrafanaProvisioner interface {
+	CreateUser(email, name, password string) (*GrafanaUser, error)
+	CreateFolder(title string) (*GrafanaFolder, error)
+	RestrictFolderToUser(folderUID string, grafanaUserID int) error
+	CreateDashboard(folderUID string, test Test) (*GrafanaDashboard, error)
+}
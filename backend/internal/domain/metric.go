@@ -6,17 +6,58 @@ import (
 	"github.com/google/uuid"
 )
 
+// MetricType is the k6 metric type of a series.
+type MetricType string
+
+const (
+	MetricTypeCounter MetricType = "counter"
+	MetricTypeGauge   MetricType = "gauge"
+	MetricTypeRate    MetricType = "rate"
+	MetricTypeTrend   MetricType = "trend"
+)
+
+func (t MetricType) IsValid() bool {
+	switch t {
+	case MetricTypeCounter, MetricTypeGauge, MetricTypeRate, MetricTypeTrend:
+		return true
+	}
+	return false
+}
+
+// BuiltinMetrics lists the k6 built-in metrics and their types. Everything
+// else in the CSV is a custom metric defined by the script.
+var BuiltinMetrics = map[string]MetricType{
+	"checks":                   MetricTypeRate,
+	"data_received":            MetricTypeCounter,
+	"data_sent":                MetricTypeCounter,
+	"dropped_iterations":       MetricTypeCounter,
+	"http_req_blocked":         MetricTypeTrend,
+	"http_req_connecting":      MetricTypeTrend,
+	"http_req_duration":        MetricTypeTrend,
+	"http_req_failed":          MetricTypeRate,
+	"http_req_receiving":       MetricTypeTrend,
+	"http_req_sending":         MetricTypeTrend,
+	"http_req_tls_handshaking": MetricTypeTrend,
+	"http_req_waiting":         MetricTypeTrend,
+	"http_reqs":                MetricTypeCounter,
+	"iteration_duration":       MetricTypeTrend,
+	"iterations":               MetricTypeCounter,
+	"vus":                      MetricTypeGauge,
+	"vus_max":                  MetricTypeGauge,
+}
+
 type K6Metric struct {
-	ID          int64     `json:"id"`
-	ExecutionID uuid.UUID `json:"execution_id"`
-	TestID      uuid.UUID `json:"test_id"`
-	MetricName  string    `json:"metric_name"`
-	Timestamp   time.Time `json:"timestamp"`
-	MetricValue float64   `json:"metric_value"`
-	Method      *string   `json:"method,omitempty"`
-	Status      *string   `json:"status,omitempty"`
-	URL         *string   `json:"url,omitempty"`
-	Scenario    *string   `json:"scenario,omitempty"`
+	ID          int64      `json:"id"`
+	ExecutionID uuid.UUID  `json:"execution_id"`
+	TestID      uuid.UUID  `json:"test_id"`
+	MetricName  string     `json:"metric_name"`
+	MetricType  MetricType `json:"metric_type"`
+	Timestamp   time.Time  `json:"timestamp"`
+	MetricValue float64    `json:"metric_value"`
+	Method      *string    `json:"method,omitempty"`
+	Status      *string    `json:"status,omitempty"`
+	URL         *string    `json:"url,omitempty"`
+	Scenario    *string    `json:"scenario,omitempty"`
 }
 
 type MetricDatapoint struct {
@@ -41,6 +82,9 @@ type MetricRepository interface {
 	ComputeExecutionSummary(executionID uuid.UUID) (JSONMap, error)
 	AggregateAndCleanup(executionID uuid.UUID) error
 	DeleteByExecution(executionID uuid.UUID) error
+	PurgeAggregated(before time.Time) (int64, error)
+	StorageStats(testID uuid.UUID) (*MetricStorageStats, error)
+	GetExecutionTimeseries(executionID uuid.UUID, intervalSeconds int) ([]ExecutionTimeseriesPoint, error)
 
 	// Grafana queries — filter by domain/test/date
 	GetTimeseriesByFilter(filter MetricFilter) ([]MetricDatapoint, error)
@@ -48,6 +92,29 @@ type MetricRepository interface {
 	GetSummaryByFilter(domainName, testName string) ([]MetricSummary, error)
 }
 
+// ExecutionTimeseriesPoint is one interval of an execution's HTTP traffic,
+// built from the per-second aggregated buckets.
+type ExecutionTimeseriesPoint struct {
+	Time         time.Time `json:"time"`
+	Requests     float64   `json:"requests"`
+	RPS          float64   `json:"rps"`
+	ResponseTime float64   `json:"response_time"` // average, ms
+	Failures     float64   `json:"failures"`
+}
+
+// MetricStorageStats sizes the metric rows a test keeps in PostgreSQL, so
+// users can see what deleting its executions would free.
+type MetricStorageStats struct {
+	TestID         uuid.UUID  `json:"test_id"`
+	Executions     int64      `json:"executions"`
+	RawRows        int64      `json:"raw_rows"`
+	AggregatedRows int64      `json:"aggregated_rows"`
+	ScenarioRows   int64      `json:"scenario_rows"`
+	ApproxBytes    int64      `json:"approx_bytes"` // row data only, indexes excluded
+	Oldest         *time.Time `json:"oldest,omitempty"`
+	Newest         *time.Time `json:"newest,omitempty"`
+}
+
 type MetricSummary struct {
 	MetricName string  `json:"metric_name"`
 	Count      int64   `json:"count"`
@@ -56,4 +123,6 @@ type MetricSummary struct {
 	Max        float64 `json:"max"`
 	P90        float64 `json:"p90"`
 	P95        float64 `json:"p95"`
+	P99        float64 `json:"p99"`
+	StdDev     float64 `json:"stddev"` // sample standard deviation, 0 for a single sample
 }
@@ -11,6 +11,7 @@ type ScheduleType string
 const (
 	ScheduleTypeOnce      ScheduleType = "ONCE"
 	ScheduleTypeRecurring ScheduleType = "RECURRING"
+	ScheduleTypeInterval  ScheduleType = "INTERVAL"
 )
 
 type ScheduleStatus string
@@ -23,39 +24,84 @@ const (
 )
 
 type Schedule struct {
-	ID             uuid.UUID      `json:"id"`
-	TestID         uuid.UUID      `json:"test_id"`
-	UserID         uuid.UUID      `json:"user_id"`
-	ScheduleType   ScheduleType   `json:"schedule_type"`
-	CronExpression *string        `json:"cron_expression,omitempty"`
-	NextRunAt      *time.Time     `json:"next_run_at,omitempty"`
-	VUs            int            `json:"vus"`
-	Duration       string         `json:"duration"`
-	Status         ScheduleStatus `json:"status"`
-	LastRunAt      *time.Time     `json:"last_run_at,omitempty"`
-	RunCount       int            `json:"run_count"`
-	CreatedAt      time.Time      `json:"created_at"`
-	UpdatedAt      time.Time      `json:"updated_at"`
+	ID              uuid.UUID      `json:"id"`
+	TestID          uuid.UUID      `json:"test_id"`
+	UserID          uuid.UUID      `json:"user_id"`
+	ScheduleType    ScheduleType   `json:"schedule_type"`
+	CronExpression  *string        `json:"cron_expression,omitempty"`
+	IntervalSeconds *int           `json:"interval_seconds,omitempty"`
+	Timezone        string         `json:"timezone"`
+	EndAt           *time.Time     `json:"end_at,omitempty"`
+	MaxRuns         *int           `json:"max_runs,omitempty"`
+	NextRunAt       *time.Time     `json:"next_run_at,omitempty"`
+	VUs             int            `json:"vus"`
+	Duration        string         `json:"duration"`
+	Status          ScheduleStatus `json:"status"`
+	LastRunAt       *time.Time     `json:"last_run_at,omitempty"`
+	RunCount        int            `json:"run_count"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
 
 	// Joined fields
-	TestName   *string `json:"test_name,omitempty"`
-	DomainName *string `json:"domain_name,omitempty"`
+	TestName   *string          `json:"test_name,omitempty"`
+	DomainName *string          `json:"domain_name,omitempty"`
+	LastRun    *ScheduleLastRun `json:"last_run,omitempty"` // nil until the schedule has fired
+}
+
+// ScheduleLastRun is the outcome of the most recent execution fired by a
+// schedule.
+type ScheduleLastRun struct {
+	ExecutionID uuid.UUID  `json:"execution_id"`
+	Status      TestStatus `json:"status"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ErrorRate   *float64   `json:"error_rate,omitempty"` // percentage of failed requests
+	P95Ms       *float64   `json:"p95_ms,omitempty"`     // http_req_duration p(95)
+}
+
+// ScheduleRunOutcome is what happened when a schedule came due.
+type ScheduleRunOutcome string
+
+const (
+	ScheduleRunStarted ScheduleRunOutcome = "started" // an execution was created and started
+	ScheduleRunFailed  ScheduleRunOutcome = "failed"  // creating or starting the execution failed
+	ScheduleRunSkipped ScheduleRunOutcome = "skipped" // the previous execution was still running
+	ScheduleRunMissed  ScheduleRunOutcome = "missed"  // runs missed during downtime were dropped
+)
+
+// ScheduleRun records one dispatch attempt of a schedule. ExecutionID is set
+// when an execution was created, Error when the attempt failed or was skipped.
+type ScheduleRun struct {
+	ID          uuid.UUID          `json:"id"`
+	ScheduleID  uuid.UUID          `json:"schedule_id"`
+	ExecutionID *uuid.UUID         `json:"execution_id,omitempty"`
+	Outcome     ScheduleRunOutcome `json:"outcome"`
+	Error       *string            `json:"error,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
 }
 
 type CreateScheduleInput struct {
-	TestID         uuid.UUID    `json:"test_id"`
-	ScheduleType   ScheduleType `json:"schedule_type"`
-	CronExpression *string      `json:"cron_expression,omitempty"`
-	NextRunAt      *time.Time   `json:"next_run_at,omitempty"`
-	VUs            int          `json:"vus"`
-	Duration       string       `json:"duration"`
+	TestID          uuid.UUID    `json:"test_id"`
+	ScheduleType    ScheduleType `json:"schedule_type"`
+	CronExpression  *string      `json:"cron_expression,omitempty"`
+	IntervalSeconds *int         `json:"interval_seconds,omitempty"`
+	Timezone        string       `json:"timezone,omitempty"`
+	EndAt           *time.Time   `json:"end_at,omitempty"`
+	MaxRuns         *int         `json:"max_runs,omitempty"`
+	NextRunAt       *time.Time   `json:"next_run_at,omitempty"`
+	VUs             int          `json:"vus"`
+	Duration        string       `json:"duration"`
 }
 
 type UpdateScheduleInput struct {
-	CronExpression *string    `json:"cron_expression,omitempty"`
-	NextRunAt      *time.Time `json:"next_run_at,omitempty"`
-	VUs            *int       `json:"vus,omitempty"`
-	Duration       *string    `json:"duration,omitempty"`
+	CronExpression  *string    `json:"cron_expression,omitempty"`
+	IntervalSeconds *int       `json:"interval_seconds,omitempty"`
+	Timezone        *string    `json:"timezone,omitempty"`
+	EndAt           *time.Time `json:"end_at,omitempty"`
+	MaxRuns         *int       `json:"max_runs,omitempty"`
+	NextRunAt       *time.Time `json:"next_run_at,omitempty"`
+	VUs             *int       `json:"vus,omitempty"`
+	Duration        *string    `json:"duration,omitempty"`
 }
 
 type ScheduleFilter struct {
@@ -72,4 +118,6 @@ type ScheduleRepository interface {
 	Delete(id uuid.UUID) error
 	List(filter ScheduleFilter) ([]Schedule, int64, error)
 	GetDueSchedules() ([]Schedule, error)
+	AddRun(run *ScheduleRun) error
+	ListRuns(scheduleID uuid.UUID, pagination Pagination) ([]ScheduleRun, int64, error)
 }
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScriptVersion is a saved copy of a test script. The highest version is the
+// one currently on disk at Test.ScriptPath.
+type ScriptVersion struct {
+	ID           uuid.UUID  `json:"id"`
+	TestID       uuid.UUID  `json:"test_id"`
+	Version      int        `json:"version"`
+	Filename     string     `json:"filename"`
+	Content      string     `json:"content,omitempty"`
+	SizeBytes    int64      `json:"size_bytes"`
+	CreatedBy    *uuid.UUID `json:"created_by,omitempty"`
+	RestoredFrom *int       `json:"restored_from,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+
+	// Joined fields
+	CreatedByName *string `json:"created_by_name,omitempty"`
+}
+
+type ScriptVersionRepository interface {
+	// Create stores the version with the next number for the test.
+	Create(version *ScriptVersion) error
+	// ListByTest returns the versions newest first, without content.
+	ListByTest(testID uuid.UUID) ([]ScriptVersion, error)
+	Get(testID uuid.UUID, version int) (*ScriptVersion, error)
+}
@@ -7,19 +7,35 @@ import (
 )
 
 type Test struct {
-	ID              uuid.UUID  `json:"id"`
-	DomainID        uuid.UUID  `json:"domain_id"`
-	UserID          uuid.UUID  `json:"user_id"`
-	Name            string     `json:"name"`
-	Description     *string    `json:"description,omitempty"`
-	ScriptFilename  string     `json:"script_filename"`
-	ScriptPath      string     `json:"-"`
-	ScriptSizeBytes int64      `json:"script_size_bytes"`
-	DefaultVUs      int        `json:"default_vus"`
-	DefaultDuration string     `json:"default_duration"`
-	CreatedAt       time.Time  `json:"created_at"`
-	UpdatedAt       time.Time  `json:"updated_at"`
-	DeletedAt       *time.Time `json:"-"`
+	ID                  uuid.UUID  `json:"id"`
+	DomainID            uuid.UUID  `json:"domain_id"`
+	UserID              uuid.UUID  `json:"user_id"`
+	Name                string     `json:"name"`
+	Description         *string    `json:"description,omitempty"`
+	Tags                []string   `json:"tags"`
+	ScriptFilename      string     `json:"script_filename"`
+	ScriptPath          string     `json:"-"`
+	ScriptSizeBytes     int64      `json:"script_size_bytes"`
+	DefaultVUs          int        `json:"default_vus"`
+	DefaultDuration     string     `json:"default_duration"`
+	DefaultStages       Stages     `json:"default_stages,omitempty"`
+	Cooldown            string     `json:"cooldown"`
+	SuccessStatusCodes  []int      `json:"success_status_codes"`
+	WebhookURL          *string    `json:"webhook_url,omitempty"`
+	WebhookSecret       *string    `json:"-"`
+	Thresholds          Thresholds `json:"thresholds,omitempty"`
+	MaxRetries          int        `json:"max_retries"`
+	RetryBackoff        string     `json:"retry_backoff,omitempty"`
+	MaxMemoryMB         *int       `json:"max_memory_mb,omitempty"`         // nil uses the K6_MAX_MEMORY_MB default
+	MaxCPUs             *float64   `json:"max_cpus,omitempty"`              // nil uses the K6_MAX_CPUS default
+	SummaryTrendStats   []string   `json:"summary_trend_stats,omitempty"`   // nil uses the default k6 trend stats
+	NormalizeURLs       bool       `json:"normalize_urls"`                  // collapse IDs and query strings in metric URLs
+	BaselineExecutionID *uuid.UUID `json:"baseline_execution_id,omitempty"` // later runs are compared against it
+	GrafanaDashboardUID *string    `json:"grafana_dashboard_uid,omitempty"`
+	GrafanaDashboardURL *string    `json:"grafana_dashboard_url,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+	DeletedAt           *time.Time `json:"-"`
 
 	// Joined fields
 	DomainName *string `json:"domain_name,omitempty"`
@@ -28,24 +44,94 @@ type Test struct {
 }
 
 type CreateTestInput struct {
-	DomainID        uuid.UUID `json:"domain_id"`
-	Name            string    `json:"name"`
-	Description     *string   `json:"description,omitempty"`
-	DefaultVUs      int       `json:"default_vus"`
-	DefaultDuration string    `json:"default_duration"`
+	DomainID           uuid.UUID  `json:"domain_id"`
+	Name               string     `json:"name"`
+	Description        *string    `json:"description,omitempty"`
+	Tags               []string   `json:"tags,omitempty"`
+	DefaultVUs         int        `json:"default_vus"`
+	DefaultDuration    string     `json:"default_duration"`
+	DefaultStages      Stages     `json:"default_stages,omitempty"`
+	Cooldown           string     `json:"cooldown,omitempty"`
+	SuccessStatusCodes []int      `json:"success_status_codes,omitempty"`
+	WebhookURL         *string    `json:"webhook_url,omitempty"`
+	WebhookSecret      *string    `json:"webhook_secret,omitempty"`
+	Thresholds         Thresholds `json:"thresholds,omitempty"`
+	MaxRetries         int        `json:"max_retries,omitempty"`
+	RetryBackoff       string     `json:"retry_backoff,omitempty"`
+	MaxMemoryMB        *int       `json:"max_memory_mb,omitempty"`
+	MaxCPUs            *float64   `json:"max_cpus,omitempty"`
+	SummaryTrendStats  []string   `json:"summary_trend_stats,omitempty"`
+	NormalizeURLs      bool       `json:"normalize_urls,omitempty"`
+	ScriptEntry        string     `json:"script_entry,omitempty"` // main script of a bundle upload
+}
+
+// CreateTestJSONInput creates a test from an inline script instead of a
+// multipart upload. Bundles are only accepted as uploads.
+type CreateTestJSONInput struct {
+	CreateTestInput
+	ScriptFilename string `json:"script_filename,omitempty"` // script.js by default
+	ScriptContent  string `json:"script_content"`
 }
 
 type UpdateTestInput struct {
-	Name            *string `json:"name,omitempty"`
-	Description     *string `json:"description,omitempty"`
-	DefaultVUs      *int    `json:"default_vus,omitempty"`
-	DefaultDuration *string `json:"default_duration,omitempty"`
+	Name               *string  `json:"name,omitempty"`
+	Description        *string  `json:"description,omitempty"`
+	Tags               []string `json:"tags,omitempty"` // an empty array clears them
+	DefaultVUs         *int     `json:"default_vus,omitempty"`
+	DefaultDuration    *string  `json:"default_duration,omitempty"`
+	DefaultStages      Stages   `json:"default_stages,omitempty"` // an empty array clears them
+	Cooldown           *string  `json:"cooldown,omitempty"`
+	SuccessStatusCodes []int    `json:"success_status_codes,omitempty"`
+	MaxRetries         *int     `json:"max_retries,omitempty"`
+	RetryBackoff       *string  `json:"retry_backoff,omitempty"`
+	// Zero clears the override and falls back to the configured default
+	MaxMemoryMB *int     `json:"max_memory_mb,omitempty"`
+	MaxCPUs     *float64 `json:"max_cpus,omitempty"`
+	// An empty array restores the default trend stats
+	SummaryTrendStats []string `json:"summary_trend_stats,omitempty"`
+	NormalizeURLs     *bool    `json:"normalize_urls,omitempty"`
+	// Empty strings clear the webhook URL/secret
+	WebhookURL    *string `json:"webhook_url,omitempty"`
+	WebhookSecret *string `json:"webhook_secret,omitempty"`
+	// An empty object removes all thresholds
+	Thresholds Thresholds `json:"thresholds,omitempty"`
+}
+
+// CloneTestInput targets the source test's domain and "<name> (copy)" by default.
+type CloneTestInput struct {
+	DomainID *uuid.UUID `json:"domain_id,omitempty"`
+	Name     *string    `json:"name,omitempty"`
+}
+
+// EstimateTestInput describes the run to estimate, with the same defaults as
+// CreateExecutionInput: omitted fields fall back to the test's.
+type EstimateTestInput struct {
+	VUs      int    `json:"vus"`
+	Duration string `json:"duration"`
+	Stages   Stages `json:"stages,omitempty"`
+}
+
+// TestEstimate is what a run would generate, after the platform's VU and
+// duration caps, without executing it. Request counts are only known when the
+// script declares a global rps limit.
+type TestEstimate struct {
+	VUs               int      `json:"vus"`
+	Duration          string   `json:"duration"`
+	DurationSeconds   float64  `json:"duration_seconds"`
+	Capped            bool     `json:"capped"`
+	RPS               *float64 `json:"rps,omitempty"`                // the script's rps option
+	Iterations        *int64   `json:"iterations,omitempty"`         // the script's fixed iteration count
+	EstimatedRequests *int64   `json:"estimated_requests,omitempty"` // upper bound: rps × duration
+	Options           JSONMap  `json:"options"`                      // options declared by the script, per k6 inspect
 }
 
 type TestFilter struct {
 	UserID   *uuid.UUID `json:"user_id,omitempty"`
 	DomainID *uuid.UUID `json:"domain_id,omitempty"`
 	Search   *string    `json:"search,omitempty"`
+	// Tags matches tests with any of the tags, or all of them with MatchAllTags
+	Tags         []string `json:"tags,omitempty"`
+	MatchAllTags bool     `json:"match_all_tags,omitempty"`
 	Pagination
 }
 
@@ -54,6 +140,9 @@ type TestRepository interface {
 	GetByID(id uuid.UUID) (*Test, error)
 	GetByDomainAndName(domainID uuid.UUID, name string) (*Test, error)
 	Update(test *Test) error
+	SetGrafanaDashboard(id uuid.UUID, uid, url string) error
+	// SetBaseline sets or, with nil, clears the test's baseline execution
+	SetBaseline(id uuid.UUID, executionID *uuid.UUID) error
 	Delete(id uuid.UUID) error
 	List(filter TestFilter) ([]Test, int64, error)
 }
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// Thresholds maps a k6 metric name to pass/fail expressions, mirroring k6's
+// options.thresholds, e.g. {"http_req_duration": ["p(95)<500"]}. They are
+// evaluated against the execution's summary export and stored as JSONB.
+type Thresholds map[string][]string
+
+func (t *Thresholds) Scan(value interface{}) error {
+	if value == nil {
+		*t = nil
+		return nil
+	}
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("unsupported type for Thresholds scan")
+	}
+	return json.Unmarshal(bytes, t)
+}
+
+func (t Thresholds) Value() (driver.Value, error) {
+	if len(t) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(t)
+}
+
+// ThresholdResult is the outcome of one threshold expression. Actual is nil
+// when the metric or statistic was missing from the summary.
+type ThresholdResult struct {
+	Metric     string   `json:"metric"`
+	Expression string   `json:"expression"`
+	Actual     *float64 `json:"actual"`
+	Passed     bool     `json:"passed"`
+}
+
+type ThresholdResults []ThresholdResult
+
+func (r *ThresholdResults) Scan(value interface{}) error {
+	if value == nil {
+		*r = nil
+		return nil
+	}
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("unsupported type for ThresholdResults scan")
+	}
+	return json.Unmarshal(bytes, r)
+}
+
+func (r ThresholdResults) Value() (driver.Value, error) {
+	if len(r) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(r)
+}
@@ -22,18 +22,21 @@ const (
 )
 
 type User struct {
-	ID              uuid.UUID  `json:"id"`
-	Email           string     `json:"email"`
-	PasswordHash    string     `json:"-"`
-	Name            string     `json:"name"`
-	Role            UserRole   `json:"role"`
-	Status          UserStatus `json:"status"`
-	GrafanaUserID   *int       `json:"grafana_user_id,omitempty"`
-	GrafanaUsername *string    `json:"grafana_username,omitempty"`
-	LastLoginAt     *time.Time `json:"last_login_at,omitempty"`
-	CreatedAt       time.Time  `json:"created_at"`
-	UpdatedAt       time.Time  `json:"updated_at"`
-	DeletedAt       *time.Time `json:"-"`
+	ID               uuid.UUID  `json:"id"`
+	Email            string     `json:"email"`
+	PasswordHash     string     `json:"-"`
+	Name             string     `json:"name"`
+	Role             UserRole   `json:"role"`
+	Status           UserStatus `json:"status"`
+	GrafanaUserID    *int       `json:"grafana_user_id,omitempty"`
+	GrafanaUsername  *string    `json:"grafana_username,omitempty"`
+	GrafanaFolderUID *string    `json:"grafana_folder_uid,omitempty"`
+	TOTPSecret       *string    `json:"-"`
+	TOTPEnabled      bool       `json:"totp_enabled"`
+	LastLoginAt      *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	DeletedAt        *time.Time `json:"-"`
 }
 
 func (u *User) IsRoot() bool {
@@ -54,7 +57,10 @@ type UpdateUserInput struct {
 }
 
 type UpdateProfileInput struct {
-	Name *string `json:"name,omitempty"`
+	Name  *string `json:"name,omitempty"`
+	Email *string `json:"email,omitempty"`
+	// Required when changing the email
+	CurrentPassword string `json:"current_password,omitempty"`
 }
 
 type ChangePasswordInput struct {
@@ -77,4 +83,15 @@ type UserRepository interface {
 	Update(user *User) error
 	Delete(id uuid.UUID) error
 	List(filter UserFilter) ([]User, int64, error)
+	// StartTwoFactorChallenge makes challengeID the user's only valid 2FA
+	// login challenge, with no failed attempts.
+	StartTwoFactorChallenge(userID, challengeID uuid.UUID) error
+	// CompleteTwoFactorChallenge consumes the challenge and records step as
+	// the last accepted TOTP step. It returns false, changing nothing, when
+	// the challenge is not the current one or step is not after the last one.
+	CompleteTwoFactorChallenge(userID, challengeID uuid.UUID, step int64) (bool, error)
+	// FailTwoFactorChallenge counts a wrong code against the challenge and
+	// drops it after maxAttempts. It returns false when the challenge was
+	// not the current one.
+	FailTwoFactorChallenge(userID, challengeID uuid.UUID, maxAttempts int) (bool, error)
 }
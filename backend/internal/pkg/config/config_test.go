@@ -0,0 +1,44 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckJWTSecret(t *testing.T) {
+	strong := strings.Repeat("k", MinJWTSecretBytes)
+
+	tests := []struct {
+		name      string
+		env       string
+		secret    string
+		wantErr   bool
+		wantFatal bool
+	}{
+		{"default in production", EnvProduction, DefaultJWTSecret, true, true},
+		{"short in production", EnvProduction, "short-secret", true, true},
+		{"one byte short in production", EnvProduction, strong[1:], true, true},
+		{"strong in production", EnvProduction, strong, false, false},
+		{"default in development", "development", DefaultJWTSecret, true, false},
+		{"short in development", "development", "short-secret", true, false},
+		{"strong in development", "development", strong, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fatal, err := CheckJWTSecret(tt.env, tt.secret)
+			if (err != nil) != tt.wantErr || fatal != tt.wantFatal {
+				t.Errorf("CheckJWTSecret = %v, %v; want fatal %v, error %v", fatal, err, tt.wantFatal, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadProductionWithoutJWTSecret(t *testing.T) {
+	t.Setenv("APP_ENV", EnvProduction)
+	t.Setenv("JWT_SECRET", "")
+
+	cfg := Load()
+	if fatal, _ := CheckJWTSecret(cfg.App.Env, cfg.JWT.Secret); !fatal {
+		t.Error("production starts with the default JWT secret")
+	}
+}
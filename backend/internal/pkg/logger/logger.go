@@ -0,0 +1,36 @@
+package logger
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/willianpsouza/StressTestPlatform/internal/pkg/config"
+)
+
+// New builds the application logger from LOG_LEVEL and LOG_FORMAT. Unknown
+// levels fall back to info and unknown formats to text.
+func New(cfg config.LogConfig, w io.Writer) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: parseLevel(cfg.Level)}
+
+	var handler slog.Handler
+	if strings.EqualFold(cfg.Format, "json") {
+		handler = slog.NewJSONHandler(w, opts)
+	} else {
+		handler = slog.NewTextHandler(w, opts)
+	}
+	return slog.New(handler)
+}
+
+func parseLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
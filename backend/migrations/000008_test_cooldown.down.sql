@@ -0,0 +1 @@
+ALTER TABLE tests DROP COLUMN IF EXISTS cooldown;
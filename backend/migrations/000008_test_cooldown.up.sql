@@ -0,0 +1 @@
+ALTER TABLE tests ADD COLUMN IF NOT EXISTS cooldown VARCHAR(50) NOT NULL DEFAULT '';
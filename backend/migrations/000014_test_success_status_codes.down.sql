@@ -0,0 +1,2 @@
+DROP FUNCTION IF EXISTS fn_is_failure_status(UUID, VARCHAR);
+ALTER TABLE tests DROP COLUMN IF EXISTS success_status_codes;
@@ -0,0 +1,12 @@
+-- HTTP status codes counted as successful when computing failures/error rate.
+ALTER TABLE tests ADD COLUMN IF NOT EXISTS success_status_codes INTEGER[] NOT NULL DEFAULT '{200,201}';
+
+-- Shared by the backend and metrics-api so both compute the same error rate.
+-- Unknown tests fall back to the historical default of 200/201.
+CREATE OR REPLACE FUNCTION fn_is_failure_status(p_test_id UUID, p_status VARCHAR)
+RETURNS BOOLEAN AS $$
+    SELECT NOT (p_status = ANY(COALESCE(
+        (SELECT success_status_codes::TEXT[] FROM tests WHERE id = p_test_id),
+        ARRAY['200','201']
+    )));
+$$ LANGUAGE sql STABLE;
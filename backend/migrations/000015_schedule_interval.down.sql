@@ -0,0 +1,4 @@
+-- PostgreSQL cannot drop enum values; INTERVAL schedules are cancelled instead.
+UPDATE schedules SET status = 'CANCELLED'::schedule_status, next_run_at = NULL
+WHERE schedule_type::text = 'INTERVAL';
+ALTER TABLE schedules DROP COLUMN IF EXISTS interval_seconds;
@@ -0,0 +1,2 @@
+ALTER TYPE schedule_type ADD VALUE IF NOT EXISTS 'INTERVAL';
+ALTER TABLE schedules ADD COLUMN IF NOT EXISTS interval_seconds INTEGER;
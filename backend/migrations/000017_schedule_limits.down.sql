@@ -0,0 +1,2 @@
+ALTER TABLE schedules DROP COLUMN IF EXISTS max_runs;
+ALTER TABLE schedules DROP COLUMN IF EXISTS end_at;
@@ -0,0 +1,2 @@
+ALTER TABLE schedules ADD COLUMN IF NOT EXISTS end_at TIMESTAMPTZ;
+ALTER TABLE schedules ADD COLUMN IF NOT EXISTS max_runs INTEGER;
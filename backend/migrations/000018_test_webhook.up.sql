@@ -0,0 +1,2 @@
+ALTER TABLE tests ADD COLUMN IF NOT EXISTS webhook_url VARCHAR(2048);
+ALTER TABLE tests ADD COLUMN IF NOT EXISTS webhook_secret VARCHAR(255);
@@ -0,0 +1,3 @@
+ALTER TABLE test_executions DROP COLUMN IF EXISTS threshold_results;
+ALTER TABLE test_executions DROP COLUMN IF EXISTS thresholds_passed;
+ALTER TABLE tests DROP COLUMN IF EXISTS thresholds;
@@ -0,0 +1,3 @@
+ALTER TABLE tests ADD COLUMN IF NOT EXISTS thresholds JSONB;
+ALTER TABLE test_executions ADD COLUMN IF NOT EXISTS thresholds_passed BOOLEAN;
+ALTER TABLE test_executions ADD COLUMN IF NOT EXISTS threshold_results JSONB;
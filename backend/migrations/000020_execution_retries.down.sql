@@ -0,0 +1,5 @@
+ALTER TABLE test_executions DROP COLUMN IF EXISTS attempts;
+ALTER TABLE test_executions DROP COLUMN IF EXISTS retry_backoff;
+ALTER TABLE test_executions DROP COLUMN IF EXISTS max_retries;
+ALTER TABLE tests DROP COLUMN IF EXISTS retry_backoff;
+ALTER TABLE tests DROP COLUMN IF EXISTS max_retries;
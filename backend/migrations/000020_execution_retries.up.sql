@@ -0,0 +1,5 @@
+ALTER TABLE tests ADD COLUMN IF NOT EXISTS max_retries INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE tests ADD COLUMN IF NOT EXISTS retry_backoff VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE test_executions ADD COLUMN IF NOT EXISTS max_retries INTEGER;
+ALTER TABLE test_executions ADD COLUMN IF NOT EXISTS retry_backoff VARCHAR(20);
+ALTER TABLE test_executions ADD COLUMN IF NOT EXISTS attempts INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+ALTER TABLE test_executions DROP COLUMN IF EXISTS stages;
+ALTER TABLE tests DROP COLUMN IF EXISTS default_stages;
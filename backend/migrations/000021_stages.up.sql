@@ -0,0 +1,2 @@
+ALTER TABLE tests ADD COLUMN IF NOT EXISTS default_stages JSONB;
+ALTER TABLE test_executions ADD COLUMN IF NOT EXISTS stages JSONB;
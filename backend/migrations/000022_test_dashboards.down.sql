@@ -0,0 +1,2 @@
+ALTER TABLE tests DROP COLUMN IF EXISTS grafana_dashboard_url;
+ALTER TABLE tests DROP COLUMN IF EXISTS grafana_dashboard_uid;
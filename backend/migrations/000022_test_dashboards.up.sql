@@ -0,0 +1,2 @@
+ALTER TABLE tests ADD COLUMN IF NOT EXISTS grafana_dashboard_uid VARCHAR(40);
+ALTER TABLE tests ADD COLUMN IF NOT EXISTS grafana_dashboard_url VARCHAR(500);
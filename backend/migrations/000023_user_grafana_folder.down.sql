@@ -0,0 +1 @@
+ALTER TABLE users DROP COLUMN IF EXISTS grafana_folder_uid;
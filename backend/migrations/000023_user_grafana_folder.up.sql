@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS grafana_folder_uid VARCHAR(40);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_tests_tags;
+DROP INDEX IF EXISTS idx_domains_tags;
+
+ALTER TABLE tests DROP COLUMN IF EXISTS tags;
+ALTER TABLE domains DROP COLUMN IF EXISTS tags;
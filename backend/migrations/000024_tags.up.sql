@@ -0,0 +1,5 @@
+ALTER TABLE domains ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE tests ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_domains_tags ON domains USING GIN (tags);
+CREATE INDEX IF NOT EXISTS idx_tests_tags ON tests USING GIN (tags);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS test_script_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    test_id UUID NOT NULL REFERENCES tests(id) ON DELETE CASCADE,
+    version INT NOT NULL,
+    filename VARCHAR(255) NOT NULL,
+    content TEXT NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    restored_from INT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (test_id, version)
+);
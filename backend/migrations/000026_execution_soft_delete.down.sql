@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_test_executions_deleted_at;
+
+DELETE FROM test_executions WHERE deleted_at IS NOT NULL;
+ALTER TABLE test_executions DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,3 @@
+ALTER TABLE test_executions ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_test_executions_deleted_at ON test_executions(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,116 @@
+-- k6 metric type (counter, gauge, rate, trend) so custom metrics defined by
+-- scripts can be summarized. NULL for rows imported before this migration.
+ALTER TABLE k6_metrics ADD COLUMN IF NOT EXISTS metric_type VARCHAR(10);
+ALTER TABLE k6_metrics_aggregated ADD COLUMN IF NOT EXISTS metric_type VARCHAR(10);
+
+-- ---------------------------------------------------------------------------
+-- SP 1: aggregate raw metrics into k6_metrics_aggregated, then cleanup
+-- (now carrying the metric type of built-in and custom metrics)
+-- ---------------------------------------------------------------------------
+CREATE OR REPLACE FUNCTION sp_aggregate_execution_metrics(p_execution_id UUID)
+RETURNS VOID AS $$
+DECLARE
+    v_test_id UUID;
+BEGIN
+    -- 1. Get test_id from raw data
+    SELECT test_id INTO v_test_id
+    FROM k6_metrics
+    WHERE execution_id = p_execution_id
+    LIMIT 1;
+
+    IF v_test_id IS NULL THEN
+        RETURN; -- no raw data to aggregate
+    END IF;
+
+    -- 2. Delete existing aggregated data for idempotency
+    DELETE FROM k6_metrics_aggregated WHERE execution_id = p_execution_id;
+
+    -- 3. Insert per-second bucket rows (for timeseries)
+    INSERT INTO k6_metrics_aggregated (
+        execution_id, test_id, bucket_time, metric_name, metric_type,
+        url, method, status, scenario,
+        count, sum_value, avg_value, min_value, max_value,
+        p50, p90, p95, p99, is_summary
+    )
+    SELECT
+        p_execution_id,
+        v_test_id,
+        date_trunc('second', timestamp) AS bucket,
+        metric_name,
+        MAX(metric_type),
+        url, method, status, scenario,
+        COUNT(*)::BIGINT,
+        SUM(metric_value),
+        AVG(metric_value),
+        MIN(metric_value),
+        MAX(metric_value),
+        PERCENTILE_CONT(0.50) WITHIN GROUP (ORDER BY metric_value),
+        PERCENTILE_CONT(0.90) WITHIN GROUP (ORDER BY metric_value),
+        PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY metric_value),
+        PERCENTILE_CONT(0.99) WITHIN GROUP (ORDER BY metric_value),
+        FALSE
+    FROM k6_metrics
+    WHERE execution_id = p_execution_id
+    GROUP BY date_trunc('second', timestamp), metric_name, url, method, status, scenario;
+
+    -- 4. Insert global summary rows (one per metric_name, no endpoint dimensions)
+    INSERT INTO k6_metrics_aggregated (
+        execution_id, test_id, bucket_time, metric_name, metric_type,
+        url, method, status, scenario,
+        count, sum_value, avg_value, min_value, max_value,
+        p50, p90, p95, p99, is_summary
+    )
+    SELECT
+        p_execution_id,
+        v_test_id,
+        NULL,
+        metric_name,
+        MAX(metric_type),
+        NULL, NULL, NULL, NULL,
+        COUNT(*)::BIGINT,
+        SUM(metric_value),
+        AVG(metric_value),
+        MIN(metric_value),
+        MAX(metric_value),
+        PERCENTILE_CONT(0.50) WITHIN GROUP (ORDER BY metric_value),
+        PERCENTILE_CONT(0.90) WITHIN GROUP (ORDER BY metric_value),
+        PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY metric_value),
+        PERCENTILE_CONT(0.99) WITHIN GROUP (ORDER BY metric_value),
+        TRUE
+    FROM k6_metrics
+    WHERE execution_id = p_execution_id
+    GROUP BY metric_name;
+
+    -- 5. Insert per-endpoint summary rows (for HTTP tables)
+    INSERT INTO k6_metrics_aggregated (
+        execution_id, test_id, bucket_time, metric_name, metric_type,
+        url, method, status, scenario,
+        count, sum_value, avg_value, min_value, max_value,
+        p50, p90, p95, p99, is_summary
+    )
+    SELECT
+        p_execution_id,
+        v_test_id,
+        NULL,
+        metric_name,
+        MAX(metric_type),
+        url, method, status, NULL,
+        COUNT(*)::BIGINT,
+        SUM(metric_value),
+        AVG(metric_value),
+        MIN(metric_value),
+        MAX(metric_value),
+        PERCENTILE_CONT(0.50) WITHIN GROUP (ORDER BY metric_value),
+        PERCENTILE_CONT(0.90) WITHIN GROUP (ORDER BY metric_value),
+        PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY metric_value),
+        PERCENTILE_CONT(0.99) WITHIN GROUP (ORDER BY metric_value),
+        TRUE
+    FROM k6_metrics
+    WHERE execution_id = p_execution_id
+      AND url IS NOT NULL
+    GROUP BY metric_name, url, method, status;
+
+    -- 6. Cleanup raw metrics
+    PERFORM sp_cleanup_raw_metrics(p_execution_id);
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1 @@
+ALTER TABLE test_executions ADD COLUMN IF NOT EXISTS check_results JSONB;
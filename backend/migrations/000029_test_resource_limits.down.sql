@@ -0,0 +1,2 @@
+ALTER TABLE tests DROP COLUMN IF EXISTS max_cpus;
+ALTER TABLE tests DROP COLUMN IF EXISTS max_memory_mb;
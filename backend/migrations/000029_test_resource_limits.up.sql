@@ -0,0 +1,2 @@
+ALTER TABLE tests ADD COLUMN IF NOT EXISTS max_memory_mb INTEGER;
+ALTER TABLE tests ADD COLUMN IF NOT EXISTS max_cpus DOUBLE PRECISION;
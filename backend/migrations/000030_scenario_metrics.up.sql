@@ -0,0 +1,151 @@
+-- Per-scenario breakdown of HTTP metrics, filled by sp_aggregate_execution_metrics.
+-- http_reqs rows are split by status (for error rates); http_req_duration rows
+-- have status NULL. Rows without a scenario tag count as 'default'.
+CREATE TABLE IF NOT EXISTS k6_metrics_scenarios (
+    id            BIGSERIAL PRIMARY KEY,
+    execution_id  UUID NOT NULL REFERENCES test_executions(id) ON DELETE CASCADE,
+    test_id       UUID NOT NULL REFERENCES tests(id) ON DELETE CASCADE,
+    scenario      VARCHAR(100) NOT NULL,
+    metric_name   VARCHAR(100) NOT NULL,
+    status        VARCHAR(10),
+    count         BIGINT NOT NULL DEFAULT 0,
+    sum_value     DOUBLE PRECISION NOT NULL DEFAULT 0,
+    avg_value     DOUBLE PRECISION NOT NULL DEFAULT 0,
+    p95           DOUBLE PRECISION
+);
+
+CREATE INDEX IF NOT EXISTS idx_k6ms_exec_id ON k6_metrics_scenarios(execution_id);
+CREATE INDEX IF NOT EXISTS idx_k6ms_test_id ON k6_metrics_scenarios(test_id);
+
+-- ---------------------------------------------------------------------------
+-- SP 1: aggregate raw metrics into k6_metrics_aggregated, then cleanup
+-- (carrying the metric type and a per-scenario breakdown)
+-- ---------------------------------------------------------------------------
+CREATE OR REPLACE FUNCTION sp_aggregate_execution_metrics(p_execution_id UUID)
+RETURNS VOID AS $$
+DECLARE
+    v_test_id UUID;
+BEGIN
+    -- 1. Get test_id from raw data
+    SELECT test_id INTO v_test_id
+    FROM k6_metrics
+    WHERE execution_id = p_execution_id
+    LIMIT 1;
+
+    IF v_test_id IS NULL THEN
+        RETURN; -- no raw data to aggregate
+    END IF;
+
+    -- 2. Delete existing aggregated data for idempotency
+    DELETE FROM k6_metrics_aggregated WHERE execution_id = p_execution_id;
+    DELETE FROM k6_metrics_scenarios WHERE execution_id = p_execution_id;
+
+    -- 3. Insert per-second bucket rows (for timeseries)
+    INSERT INTO k6_metrics_aggregated (
+        execution_id, test_id, bucket_time, metric_name, metric_type,
+        url, method, status, scenario,
+        count, sum_value, avg_value, min_value, max_value,
+        p50, p90, p95, p99, is_summary
+    )
+    SELECT
+        p_execution_id,
+        v_test_id,
+        date_trunc('second', timestamp) AS bucket,
+        metric_name,
+        MAX(metric_type),
+        url, method, status, scenario,
+        COUNT(*)::BIGINT,
+        SUM(metric_value),
+        AVG(metric_value),
+        MIN(metric_value),
+        MAX(metric_value),
+        PERCENTILE_CONT(0.50) WITHIN GROUP (ORDER BY metric_value),
+        PERCENTILE_CONT(0.90) WITHIN GROUP (ORDER BY metric_value),
+        PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY metric_value),
+        PERCENTILE_CONT(0.99) WITHIN GROUP (ORDER BY metric_value),
+        FALSE
+    FROM k6_metrics
+    WHERE execution_id = p_execution_id
+    GROUP BY date_trunc('second', timestamp), metric_name, url, method, status, scenario;
+
+    -- 4. Insert global summary rows (one per metric_name, no endpoint dimensions)
+    INSERT INTO k6_metrics_aggregated (
+        execution_id, test_id, bucket_time, metric_name, metric_type,
+        url, method, status, scenario,
+        count, sum_value, avg_value, min_value, max_value,
+        p50, p90, p95, p99, is_summary
+    )
+    SELECT
+        p_execution_id,
+        v_test_id,
+        NULL,
+        metric_name,
+        MAX(metric_type),
+        NULL, NULL, NULL, NULL,
+        COUNT(*)::BIGINT,
+        SUM(metric_value),
+        AVG(metric_value),
+        MIN(metric_value),
+        MAX(metric_value),
+        PERCENTILE_CONT(0.50) WITHIN GROUP (ORDER BY metric_value),
+        PERCENTILE_CONT(0.90) WITHIN GROUP (ORDER BY metric_value),
+        PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY metric_value),
+        PERCENTILE_CONT(0.99) WITHIN GROUP (ORDER BY metric_value),
+        TRUE
+    FROM k6_metrics
+    WHERE execution_id = p_execution_id
+    GROUP BY metric_name;
+
+    -- 5. Insert per-endpoint summary rows (for HTTP tables)
+    INSERT INTO k6_metrics_aggregated (
+        execution_id, test_id, bucket_time, metric_name, metric_type,
+        url, method, status, scenario,
+        count, sum_value, avg_value, min_value, max_value,
+        p50, p90, p95, p99, is_summary
+    )
+    SELECT
+        p_execution_id,
+        v_test_id,
+        NULL,
+        metric_name,
+        MAX(metric_type),
+        url, method, status, NULL,
+        COUNT(*)::BIGINT,
+        SUM(metric_value),
+        AVG(metric_value),
+        MIN(metric_value),
+        MAX(metric_value),
+        PERCENTILE_CONT(0.50) WITHIN GROUP (ORDER BY metric_value),
+        PERCENTILE_CONT(0.90) WITHIN GROUP (ORDER BY metric_value),
+        PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY metric_value),
+        PERCENTILE_CONT(0.99) WITHIN GROUP (ORDER BY metric_value),
+        TRUE
+    FROM k6_metrics
+    WHERE execution_id = p_execution_id
+      AND url IS NOT NULL
+    GROUP BY metric_name, url, method, status;
+
+    -- 6. Insert per-scenario rows: request counts by status and durations
+    INSERT INTO k6_metrics_scenarios (
+        execution_id, test_id, scenario, metric_name, status,
+        count, sum_value, avg_value, p95
+    )
+    SELECT
+        p_execution_id,
+        v_test_id,
+        COALESCE(scenario, 'default'),
+        metric_name,
+        CASE WHEN metric_name = 'http_reqs' THEN status END,
+        COUNT(*)::BIGINT,
+        SUM(metric_value),
+        AVG(metric_value),
+        PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY metric_value)
+    FROM k6_metrics
+    WHERE execution_id = p_execution_id
+      AND metric_name IN ('http_reqs', 'http_req_duration')
+    GROUP BY COALESCE(scenario, 'default'), metric_name, CASE WHEN metric_name = 'http_reqs' THEN status END;
+
+    -- 7. Cleanup raw metrics
+    PERFORM sp_cleanup_raw_metrics(p_execution_id);
+END;
+$$ LANGUAGE plpgsql;
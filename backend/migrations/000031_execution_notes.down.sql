@@ -0,0 +1 @@
+ALTER TABLE test_executions DROP COLUMN IF EXISTS notes;
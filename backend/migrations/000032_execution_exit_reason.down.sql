@@ -0,0 +1,3 @@
+ALTER TABLE test_executions DROP COLUMN IF EXISTS exit_reason;
+
+DROP TYPE IF EXISTS exit_reason;
@@ -0,0 +1,3 @@
+CREATE TYPE exit_reason AS ENUM ('threshold_failed', 'script_error', 'timeout', 'oom', 'unknown');
+
+ALTER TABLE test_executions ADD COLUMN IF NOT EXISTS exit_reason exit_reason;
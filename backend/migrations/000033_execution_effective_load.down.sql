@@ -0,0 +1,3 @@
+ALTER TABLE test_executions DROP COLUMN IF EXISTS capped;
+ALTER TABLE test_executions DROP COLUMN IF EXISTS effective_duration;
+ALTER TABLE test_executions DROP COLUMN IF EXISTS effective_vus;
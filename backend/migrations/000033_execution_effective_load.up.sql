@@ -0,0 +1,3 @@
+ALTER TABLE test_executions ADD COLUMN IF NOT EXISTS effective_vus INTEGER;
+ALTER TABLE test_executions ADD COLUMN IF NOT EXISTS effective_duration VARCHAR(20);
+ALTER TABLE test_executions ADD COLUMN IF NOT EXISTS capped BOOLEAN NOT NULL DEFAULT FALSE;
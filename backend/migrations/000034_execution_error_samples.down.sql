@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS error_samples;
+ALTER TABLE test_executions DROP COLUMN IF EXISTS capture_errors;
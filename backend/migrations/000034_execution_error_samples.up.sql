@@ -0,0 +1,15 @@
+ALTER TABLE test_executions ADD COLUMN IF NOT EXISTS capture_errors BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS error_samples (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    execution_id UUID NOT NULL REFERENCES test_executions(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    method VARCHAR(16) NOT NULL DEFAULT '',
+    url TEXT NOT NULL DEFAULT '',
+    status INT NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    truncated BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_error_samples_execution ON error_samples(execution_id, position);
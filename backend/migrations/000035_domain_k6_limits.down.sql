@@ -0,0 +1,3 @@
+ALTER TABLE domains DROP COLUMN IF EXISTS max_concurrent;
+ALTER TABLE domains DROP COLUMN IF EXISTS max_duration;
+ALTER TABLE domains DROP COLUMN IF EXISTS max_vus;
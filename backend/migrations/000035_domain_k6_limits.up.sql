@@ -0,0 +1,3 @@
+ALTER TABLE domains ADD COLUMN IF NOT EXISTS max_vus INTEGER;
+ALTER TABLE domains ADD COLUMN IF NOT EXISTS max_duration VARCHAR(20);
+ALTER TABLE domains ADD COLUMN IF NOT EXISTS max_concurrent INTEGER;
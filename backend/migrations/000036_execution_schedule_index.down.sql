@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_test_executions_schedule_id;
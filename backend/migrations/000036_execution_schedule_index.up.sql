@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_test_executions_schedule_id ON test_executions(schedule_id, created_at DESC) WHERE schedule_id IS NOT NULL;
@@ -0,0 +1,12 @@
+-- Refresh token rotation chains: every session issued by a refresh keeps the
+-- family of the login that started the chain and the hash of the token it
+-- replaced. Rotated sessions are kept until they expire, so a replayed token
+-- is recognised as reuse instead of simply being unknown.
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS family_id UUID;
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS parent_token_hash VARCHAR(255);
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS rotated_at TIMESTAMPTZ;
+
+UPDATE sessions SET family_id = id WHERE family_id IS NULL;
+ALTER TABLE sessions ALTER COLUMN family_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_sessions_family_id ON sessions(family_id);
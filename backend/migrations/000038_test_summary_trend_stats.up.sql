@@ -0,0 +1 @@
+ALTER TABLE tests ADD COLUMN IF NOT EXISTS summary_trend_stats TEXT[];
@@ -0,0 +1,12 @@
+-- One row per dispatch attempt of a schedule: the execution it started, or
+-- why none was started.
+CREATE TABLE IF NOT EXISTS schedule_runs (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    schedule_id   UUID NOT NULL REFERENCES schedules(id) ON DELETE CASCADE,
+    execution_id  UUID REFERENCES test_executions(id) ON DELETE SET NULL,
+    outcome       VARCHAR(20) NOT NULL,
+    error         TEXT,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_schedule_runs_schedule ON schedule_runs(schedule_id, created_at DESC);
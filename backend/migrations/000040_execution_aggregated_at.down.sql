@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_test_executions_aggregated_at;
+ALTER TABLE test_executions DROP COLUMN IF EXISTS aggregated_at;
@@ -0,0 +1,6 @@
+-- When the metrics of an execution were aggregated; aggregated_at - completed_at
+-- is the aggregation lag.
+ALTER TABLE test_executions ADD COLUMN IF NOT EXISTS aggregated_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_test_executions_aggregated_at ON test_executions(aggregated_at DESC)
+  WHERE aggregated_at IS NOT NULL;
@@ -0,0 +1,3 @@
+-- When set, query strings and numeric/UUID path segments are stripped from
+-- metric URLs before aggregation, so /users/1 and /users/2 share one row.
+ALTER TABLE tests ADD COLUMN IF NOT EXISTS normalize_urls BOOLEAN NOT NULL DEFAULT FALSE;
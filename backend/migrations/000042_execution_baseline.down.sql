@@ -0,0 +1,3 @@
+ALTER TABLE test_executions DROP COLUMN IF EXISTS baseline_comparison;
+ALTER TABLE test_executions DROP COLUMN IF EXISTS regressed;
+ALTER TABLE tests DROP COLUMN IF EXISTS baseline_execution_id;
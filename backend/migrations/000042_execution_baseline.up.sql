@@ -0,0 +1,6 @@
+-- The execution later runs of a test are compared against, and the outcome
+-- of that comparison on each run.
+ALTER TABLE tests ADD COLUMN IF NOT EXISTS baseline_execution_id UUID
+  REFERENCES test_executions(id) ON DELETE SET NULL;
+ALTER TABLE test_executions ADD COLUMN IF NOT EXISTS regressed BOOLEAN;
+ALTER TABLE test_executions ADD COLUMN IF NOT EXISTS baseline_comparison JSONB;
@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN IF EXISTS totp_last_step;
+ALTER TABLE users DROP COLUMN IF EXISTS totp_challenge_attempts;
+ALTER TABLE users DROP COLUMN IF EXISTS totp_challenge_id;
@@ -0,0 +1,6 @@
+-- The outstanding 2FA login challenge, its failed attempts, and the last
+-- accepted TOTP time step, so a challenge dies after a few wrong codes and a
+-- code cannot be replayed within its window.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_challenge_id UUID;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_challenge_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_last_step BIGINT;
@@ -81,8 +81,14 @@ export default function ExecutionDetailPage() {
             {isActive && <span className="ml-1 animate-pulse">...</span>}
           </span>
         </InfoCard>
-        <InfoCard label="VUs" value={String(exec.vus)} />
-        <InfoCard label="Duration" value={exec.duration} />
+        <InfoCard
+          label="VUs"
+          value={exec.capped && exec.effective_vus !== undefined ? `${exec.effective_vus} (requested ${exec.vus})` : String(exec.vus)}
+        />
+        <InfoCard
+          label="Duration"
+          value={exec.capped && exec.effective_duration ? `${exec.effective_duration} (requested ${exec.duration})` : exec.duration}
+        />
         <InfoCard label="Exit Code" value={exec.exit_code !== undefined ? String(exec.exit_code) : '-'} />
       </div>
 
@@ -99,6 +105,41 @@ export default function ExecutionDetailPage() {
         </div>
       )}
 
+      {/* Thresholds */}
+      {exec.threshold_results && exec.threshold_results.length > 0 && (
+        <div
+          className={cn(
+            'rounded-xl border p-4 mb-6',
+            exec.thresholds_passed ? 'bg-green-50 border-green-200' : 'bg-red-50 border-red-200'
+          )}
+        >
+          <h3 className={cn('text-sm font-semibold mb-2', exec.thresholds_passed ? 'text-green-800' : 'text-red-800')}>
+            Thresholds {exec.thresholds_passed ? 'passed' : 'failed'}
+          </h3>
+          <ul className="space-y-1">
+            {exec.threshold_results.map((t, i) => (
+              <li key={i} className={cn('text-sm font-mono', t.passed ? 'text-green-700' : 'text-red-700')}>
+                {t.passed ? '✓' : '✗'} {t.metric}: {t.expression} (actual: {t.actual !== null ? t.actual.toFixed(2) : 'n/a'})
+              </li>
+            ))}
+          </ul>
+        </div>
+      )}
+
+      {/* Checks */}
+      {exec.check_results && exec.check_results.length > 0 && (
+        <div className="bg-white rounded-xl shadow-sm border border-gray-200 p-6 mb-6">
+          <h2 className="text-lg font-semibold text-gray-900 mb-4">Checks</h2>
+          <ul className="space-y-1">
+            {exec.check_results.map((c, i) => (
+              <li key={i} className={cn('text-sm font-mono', c.fails === 0 ? 'text-green-700' : 'text-red-700')}>
+                {c.fails === 0 ? '✓' : '✗'} {c.group ? `${c.group} › ` : ''}{c.name}: {(c.rate * 100).toFixed(2)}% ({c.passes} ✓ / {c.fails} ✗)
+              </li>
+            ))}
+          </ul>
+        </div>
+      )}
+
       {/* Metrics Summary */}
       {metrics && Object.keys(metrics).length > 0 && (
         <div className="bg-white rounded-xl shadow-sm border border-gray-200 p-6 mb-6">
@@ -84,6 +84,21 @@ export default function ExecutionsPage() {
                       <span className={cn('px-2 py-1 text-xs font-medium rounded-full', statusColors[exec.status])}>
                         {exec.status}
                       </span>
+                      {exec.thresholds_passed !== undefined && (
+                        <span
+                          className={cn(
+                            'ml-2 px-2 py-1 text-xs font-medium rounded-full',
+                            exec.thresholds_passed ? 'bg-green-100 text-green-800' : 'bg-red-100 text-red-800'
+                          )}
+                        >
+                          {exec.thresholds_passed ? 'SLA OK' : 'SLA FAIL'}
+                        </span>
+                      )}
+                      {exec.exit_reason && (
+                        <span className="ml-2 px-2 py-1 text-xs font-medium rounded-full bg-gray-100 text-gray-700">
+                          {exec.exit_reason.replace('_', ' ')}
+                        </span>
+                      )}
                     </td>
                     <td className="px-6 py-4 text-sm font-medium text-gray-900">{exec.test_name || '-'}</td>
                     <td className="px-6 py-4 text-sm text-gray-500">{exec.vus}</td>
@@ -121,7 +121,9 @@ export default function TestDetailPage() {
 
   if (!test) return <div className="text-gray-400">Loading...</div>
 
-  const grafanaUrl = `/grafana/d/k6-metrics/k6-stress-test-dashboard?orgId=1&from=now-1h&to=now&timezone=browser&var-domain=${encodeURIComponent(test.domain_name || '')}&var-test=${encodeURIComponent(test.name)}&var-interval_value=5&refresh=10s`
+  const grafanaUrl = test.grafana_dashboard_url
+    ? `${test.grafana_dashboard_url}?orgId=1&from=now-1h&to=now&refresh=10s`
+    : `/grafana/d/k6-metrics/k6-stress-test-dashboard?orgId=1&from=now-1h&to=now&timezone=browser&var-domain=${encodeURIComponent(test.domain_name || '')}&var-test=${encodeURIComponent(test.name)}&var-interval_value=5&refresh=10s`
 
   return (
     <div>
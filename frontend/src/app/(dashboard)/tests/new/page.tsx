@@ -15,6 +15,7 @@ export default function NewTestPage() {
   const [defaultVus, setDefaultVus] = useState('1')
   const [defaultDuration, setDefaultDuration] = useState('30s')
   const [script, setScript] = useState<File | null>(null)
+  const [scriptEntry, setScriptEntry] = useState('main.js')
   const [error, setError] = useState('')
   const [loading, setLoading] = useState(false)
 
@@ -24,6 +25,8 @@ export default function NewTestPage() {
     })
   }, [])
 
+  const isBundle = !!script && /\.(zip|tar|tgz|tar\.gz)$/i.test(script.name)
+
   const handleSubmit = async (e: React.FormEvent) => {
     e.preventDefault()
     if (!script) { setError('Script is required'); return }
@@ -37,6 +40,7 @@ export default function NewTestPage() {
     formData.append('default_vus', defaultVus)
     formData.append('default_duration', defaultDuration)
     formData.append('script', script)
+    if (isBundle) formData.append('script_entry', scriptEntry)
 
     const res = await api.post<Test>('/tests', formData)
 
@@ -90,11 +94,19 @@ export default function NewTestPage() {
         </div>
 
         <div>
-          <label className="block text-sm font-medium text-gray-700 mb-1">Script K6 (.js)</label>
-          <input type="file" accept=".js" onChange={(e) => setScript(e.target.files?.[0] || null)}
+          <label className="block text-sm font-medium text-gray-700 mb-1">Script K6 (.js or .zip/.tar.gz bundle)</label>
+          <input type="file" accept=".js,.zip,.tar,.tgz,.tar.gz" onChange={(e) => setScript(e.target.files?.[0] || null)}
             className="w-full text-sm text-gray-500 file:mr-4 file:py-2 file:px-4 file:rounded-lg file:border-0 file:text-sm file:font-medium file:bg-primary-50 file:text-primary-700 hover:file:bg-primary-100" required />
         </div>
 
+        {isBundle && (
+          <div>
+            <label className="block text-sm font-medium text-gray-700 mb-1">Entry script</label>
+            <input type="text" value={scriptEntry} onChange={(e) => setScriptEntry(e.target.value)}
+              className="w-full px-3 py-2 border border-gray-300 rounded-lg focus:ring-2 focus:ring-primary-500 focus:border-transparent" placeholder="main.js" />
+          </div>
+        )}
+
         <div className="flex space-x-3">
           <button type="submit" disabled={loading}
             className="px-4 py-2 bg-primary-600 text-white text-sm font-medium rounded-lg hover:bg-primary-700 disabled:opacity-50">
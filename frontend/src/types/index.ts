@@ -6,6 +6,8 @@ export interface User {
   status: 'ACTIVE' | 'INACTIVE' | 'SUSPENDED'
   grafana_user_id?: number
   grafana_username?: string
+  grafana_folder_uid?: string
+  totp_enabled?: boolean
   last_login_at?: string
   created_at: string
   updated_at: string
@@ -23,6 +25,10 @@ export interface Domain {
   user_id: string
   name: string
   description?: string
+  tags: string[]
+  max_vus?: number
+  max_duration?: string
+  max_concurrent?: number
   created_at: string
   updated_at: string
 }
@@ -35,8 +41,23 @@ export interface Test {
   description?: string
   script_filename: string
   script_size_bytes: number
+  tags: string[]
   default_vus: number
   default_duration: string
+  default_stages?: Stage[]
+  cooldown?: string
+  success_status_codes: number[]
+  webhook_url?: string
+  thresholds?: Record<string, string[]>
+  max_retries: number
+  retry_backoff?: string
+  max_memory_mb?: number
+  max_cpus?: number
+  summary_trend_stats?: string[]
+  normalize_urls: boolean
+  baseline_execution_id?: string
+  grafana_dashboard_uid?: string
+  grafana_dashboard_url?: string
   created_at: string
   updated_at: string
   domain_name?: string
@@ -44,6 +65,48 @@ export interface Test {
   user_email?: string
 }
 
+export interface Stage {
+  duration: string
+  target: number
+}
+
+export interface ScriptVersion {
+  id: string
+  test_id: string
+  version: number
+  filename: string
+  content?: string
+  size_bytes: number
+  created_by?: string
+  restored_from?: number
+  created_at: string
+  created_by_name?: string
+}
+
+export interface BaselineComparison {
+  baseline_execution_id: string
+  p95_ms?: number
+  baseline_p95_ms?: number
+  error_rate?: number
+  baseline_error_rate?: number
+  regressions?: string[]
+}
+
+export interface ThresholdResult {
+  metric: string
+  expression: string
+  actual: number | null
+  passed: boolean
+}
+
+export interface CheckResult {
+  name: string
+  group?: string
+  passes: number
+  fails: number
+  rate: number
+}
+
 export interface TestExecution {
   id: string
   test_id: string
@@ -51,14 +114,30 @@ export interface TestExecution {
   schedule_id?: string
   vus: number
   duration: string
+  effective_vus?: number
+  effective_duration?: string
+  capped: boolean
   status: 'PENDING' | 'RUNNING' | 'COMPLETED' | 'FAILED' | 'CANCELLED' | 'TIMEOUT'
   started_at?: string
   completed_at?: string
   exit_code?: number
+  exit_reason?: 'threshold_failed' | 'script_error' | 'timeout' | 'oom' | 'unknown'
   stdout?: string
   stderr?: string
   metrics_summary?: Record<string, unknown>
+  summary_export?: Record<string, unknown>
+  targets?: { url: string; weight?: number }[]
+  env?: Record<string, string>
+  stages?: Stage[]
   error_message?: string
+  thresholds_passed?: boolean
+  threshold_results?: ThresholdResult[]
+  regressed?: boolean
+  baseline_comparison?: BaselineComparison
+  check_results?: CheckResult[]
+  max_retries?: number
+  retry_backoff?: string
+  attempts: number
   created_at: string
   updated_at: string
   test_name?: string
@@ -71,8 +150,12 @@ export interface Schedule {
   id: string
   test_id: string
   user_id: string
-  schedule_type: 'ONCE' | 'RECURRING'
+  schedule_type: 'ONCE' | 'RECURRING' | 'INTERVAL'
   cron_expression?: string
+  interval_seconds?: number
+  timezone: string
+  end_at?: string
+  max_runs?: number
   next_run_at?: string
   vus: number
   duration: string
@@ -83,6 +166,16 @@ export interface Schedule {
   updated_at: string
   test_name?: string
   domain_name?: string
+  last_run?: ScheduleLastRun
+}
+
+export interface ScheduleLastRun {
+  execution_id: string
+  status: TestExecution['status']
+  started_at?: string
+  completed_at?: string
+  error_rate?: number
+  p95_ms?: number
 }
 
 export interface ApiResponse<T> {
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireToken(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name   string
+		token  string
+		header string
+		want   int
+	}{
+		{name: "missing header", token: "secret", header: "", want: 401},
+		{name: "wrong token", token: "secret", header: "Bearer wrong", want: 401},
+		{name: "token prefix only", token: "secret", header: "Bearer secre", want: 401},
+		{name: "not a bearer token", token: "secret", header: "secret", want: 401},
+		{name: "basic auth", token: "secret", header: "Basic c2VjcmV0", want: 401},
+		{name: "valid token", token: "secret", header: "Bearer secret", want: 204},
+		{name: "check disabled", token: "", header: "", want: 204},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/grafana/stats", nil)
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			requireToken(tt.token)(ok).ServeHTTP(w, r)
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+			if challenge := w.Header().Get("WWW-Authenticate"); (tt.want == 401) != (challenge != "") {
+				t.Errorf("WWW-Authenticate = %q on a %d", challenge, w.Code)
+			}
+		})
+	}
+}
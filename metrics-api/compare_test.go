@@ -0,0 +1,71 @@
+package main
+
+import "testing"
+
+func TestNewStatsDelta(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      float64
+		wantDelta float64
+		wantPct   *float64
+	}{
+		{name: "increase", a: 200, b: 250, wantDelta: 50, wantPct: ptr(25.0)},
+		{name: "decrease", a: 80, b: 60, wantDelta: -20, wantPct: ptr(-25.0)},
+		{name: "unchanged", a: 10, b: 10, wantDelta: 0, wantPct: ptr(0.0)},
+		{name: "rounded to 2 decimals", a: 3, b: 4, wantDelta: 1, wantPct: ptr(33.33)},
+		{name: "small delta rounded", a: 1.234, b: 1.2391, wantDelta: 0.01, wantPct: ptr(0.41)},
+		{name: "no percentage from zero", a: 0, b: 5, wantDelta: 5, wantPct: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newStatsDelta(tt.a, tt.b)
+			if d.A != tt.a || d.B != tt.b {
+				t.Errorf("a, b = %v, %v, want %v, %v", d.A, d.B, tt.a, tt.b)
+			}
+			if d.Delta != tt.wantDelta {
+				t.Errorf("delta = %v, want %v", d.Delta, tt.wantDelta)
+			}
+			switch {
+			case tt.wantPct == nil && d.DeltaPct != nil:
+				t.Errorf("delta_pct = %v, want nil", *d.DeltaPct)
+			case tt.wantPct != nil && (d.DeltaPct == nil || *d.DeltaPct != *tt.wantPct):
+				t.Errorf("delta_pct = %v, want %v", d.DeltaPct, *tt.wantPct)
+			}
+		})
+	}
+}
+
+func TestCompareStats(t *testing.T) {
+	a := statsRow{Requests: 1000, ErrorRate: 2, AvgResponse: 100, P90: 180, P95: 200, PeakRPS: 50, MaxResponse: 900}
+	b := statsRow{Requests: 1500, ErrorRate: 1, AvgResponse: 120, P90: 180, P95: 260, PeakRPS: 0, MaxResponse: 100}
+
+	got := compareStats(a, b)
+	if got.A != a || got.B != b {
+		t.Errorf("compareStats did not keep both stats rows")
+	}
+
+	want := map[string]float64{
+		"requests":     50,
+		"error_rate":   -50,
+		"avg_response": 20,
+		"p90":          0,
+		"p95":          30,
+		"peak_rps":     -100,
+	}
+	if len(got.Deltas) != len(want) {
+		t.Errorf("deltas = %v, want keys of %v", got.Deltas, want)
+	}
+	for key, pct := range want {
+		d, ok := got.Deltas[key]
+		if !ok {
+			t.Errorf("missing delta %q", key)
+			continue
+		}
+		if d.DeltaPct == nil || *d.DeltaPct != pct {
+			t.Errorf("%s delta_pct = %v, want %v", key, d.DeltaPct, pct)
+		}
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
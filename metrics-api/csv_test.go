@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// jsonFields returns the keys and values of v's JSON object in field order,
+// with numbers kept as they were encoded.
+func jsonFields(t *testing.T, v any) (keys, values []string) {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if _, err := dec.Token(); err != nil {
+		t.Fatal(err)
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			t.Fatal(err)
+		}
+		value, err := dec.Token()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key.(string))
+		switch value := value.(type) {
+		case string:
+			values = append(values, value)
+		case json.Number:
+			values = append(values, value.String())
+		default:
+			t.Fatalf("%s: unexpected JSON value %v", key, value)
+		}
+	}
+	return keys, values
+}
+
+func TestCSVMatchesJSONTable(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []string
+		row    csvRecorder
+	}{
+		{
+			name:   "http-requests",
+			header: httpRequestsCSVHeader,
+			row: httpRequestsRow{URL: "https://example.com/api?q=1,2", Method: "GET", Status: "200", Count: 1234,
+				AvgMs: 12.5, MinMs: 0.25, MaxMs: 980, P90Ms: 40.12, P95Ms: 55.5, P99Ms: 120.75},
+		},
+		{
+			name:   "errors",
+			header: errorsCSVHeader,
+			row:    errorsRow{URL: "https://example.com/login", Method: "POST", Status: "503", Count: 17},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, values := jsonFields(t, tt.row)
+			if !reflect.DeepEqual(tt.header, keys) {
+				t.Errorf("CSV header = %v, JSON keys = %v", tt.header, keys)
+			}
+			if got := tt.row.csvRecord(); !reflect.DeepEqual(got, values) {
+				t.Errorf("CSV record = %q, JSON values = %q", got, values)
+			}
+		})
+	}
+}
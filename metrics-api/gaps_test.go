@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var gapsStart = time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+
+// gapsSeries builds timeseries JSON with one row at each offset (in seconds
+// from gapsStart), carrying an int-like count and a fractional avg.
+func gapsSeries(offsets ...int) []byte {
+	rows := make([]map[string]any, 0, len(offsets))
+	for i, off := range offsets {
+		rows = append(rows, map[string]any{
+			"time":  gapsStart.Add(time.Duration(off) * time.Second).Format(time.RFC3339),
+			"count": i + 1,
+			"avg":   float64(i) + 0.5,
+		})
+	}
+	return marshal(rows)
+}
+
+func TestFillTimeGaps(t *testing.T) {
+	tests := []struct {
+		name    string
+		offsets []int
+		want    []int
+		zeros   []int // offsets of the inserted rows
+	}{
+		{name: "single row", offsets: []int{0}, want: []int{0}},
+		{name: "no gap", offsets: []int{0, 60, 120, 180}, want: []int{0, 60, 120, 180}},
+		{name: "gap of exactly 2x interval", offsets: []int{0, 120, 180}, want: []int{0, 120, 180}},
+		{
+			name:    "leading gap",
+			offsets: []int{0, 600, 660, 720},
+			want:    []int{0, 60, 540, 600, 660, 720},
+			zeros:   []int{60, 540},
+		},
+		{
+			name:    "mid-series gap",
+			offsets: []int{0, 60, 600, 660},
+			want:    []int{0, 60, 120, 540, 600, 660},
+			zeros:   []int{120, 540},
+		},
+		{
+			name:    "trailing gap",
+			offsets: []int{0, 60, 120, 900},
+			want:    []int{0, 60, 120, 180, 840, 900},
+			zeros:   []int{180, 840},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rows []map[string]any
+			if err := json.Unmarshal(fillTimeGaps(gapsSeries(tt.offsets...), 60, 0), &rows); err != nil {
+				t.Fatal(err)
+			}
+
+			var got []int
+			inserted := map[int]bool{}
+			for _, z := range tt.zeros {
+				inserted[z] = true
+			}
+			for _, row := range rows {
+				ts, ok := parseJSONTime(row["time"])
+				if !ok {
+					t.Fatalf("row time %v does not parse", row["time"])
+				}
+				off := int(ts.Sub(gapsStart) / time.Second)
+				got = append(got, off)
+				if !inserted[off] {
+					continue
+				}
+				if len(row) != 3 {
+					t.Errorf("gap row at +%ds has keys %v, want time, count and avg", off, row)
+				}
+				for _, k := range []string{"count", "avg"} {
+					if v, ok := row[k].(float64); !ok || v != 0 {
+						t.Errorf("gap row at +%ds: %s = %#v, want float64 0", off, k, row[k])
+					}
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("row offsets = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFillTimeGapsUnchanged(t *testing.T) {
+	for _, raw := range []string{`[]`, `null`, `not json`, `{"time": "2026-01-02T15:00:00Z"}`} {
+		if got := fillTimeGaps([]byte(raw), 60, 0); string(got) != raw {
+			t.Errorf("fillTimeGaps(%s) = %s, want it unchanged", raw, got)
+		}
+	}
+}
+
+func TestFillTimeGapsDownsamples(t *testing.T) {
+	offsets := make([]int, 100)
+	for i := range offsets {
+		offsets[i] = i * 60
+	}
+	var rows []map[string]any
+	if err := json.Unmarshal(fillTimeGaps(gapsSeries(offsets...), 60, 10), &rows); err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 10 {
+		t.Errorf("got %d rows, want 10", len(rows))
+	}
+	if first := fmt.Sprint(rows[0]["time"]); first != gapsStart.Format(time.RFC3339) {
+		t.Errorf("first row at %s, want the series start", first)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// lttbRows builds a per-second series with the given values.
+func lttbRows(values ...float64) []map[string]any {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	rows := make([]map[string]any, len(values))
+	for i, v := range values {
+		rows[i] = map[string]any{
+			"time":  start.Add(time.Duration(i) * time.Second).Format(time.RFC3339Nano),
+			"value": v,
+		}
+	}
+	return rows
+}
+
+func TestDownsampleLTTB(t *testing.T) {
+	flatWithSpike := make([]float64, 100)
+	flatWithSpike[42] = 500
+
+	tests := []struct {
+		name       string
+		values     []float64
+		maxPoints  int
+		wantLen    int
+		wantValues []float64 // values that must survive, in order
+	}{
+		{name: "shorter than max", values: []float64{1, 2, 3}, maxPoints: 10, wantLen: 3},
+		{name: "equal to max", values: []float64{1, 2, 3, 4}, maxPoints: 4, wantLen: 4},
+		{name: "max below 3 leaves rows", values: flatWithSpike, maxPoints: 2, wantLen: 100},
+		{name: "reduced to max", values: flatWithSpike, maxPoints: 10, wantLen: 10},
+		{name: "keeps the spike", values: flatWithSpike, maxPoints: 5, wantLen: 5, wantValues: []float64{500}},
+		{name: "minimum of 3", values: []float64{1, 9, 2, 3, 4, 5}, maxPoints: 3, wantLen: 3, wantValues: []float64{1, 9, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := lttbRows(tt.values...)
+			got := downsampleLTTB(rows, tt.maxPoints)
+			if len(got) != tt.wantLen {
+				t.Fatalf("len = %d, want %d", len(got), tt.wantLen)
+			}
+			if got[0]["time"] != rows[0]["time"] || got[len(got)-1]["time"] != rows[len(rows)-1]["time"] {
+				t.Errorf("first and last rows were not preserved")
+			}
+			prev := ""
+			kept := map[float64]bool{}
+			for _, row := range got {
+				ts := row["time"].(string)
+				if ts <= prev {
+					t.Fatalf("rows out of order: %s after %s", ts, prev)
+				}
+				prev = ts
+				kept[row["value"].(float64)] = true
+			}
+			for _, v := range tt.wantValues {
+				if !kept[v] {
+					t.Errorf("value %v was dropped", v)
+				}
+			}
+		})
+	}
+}
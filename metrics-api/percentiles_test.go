@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestParsePercentiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    []float64
+		wantErr bool
+	}{
+		{name: "default", raw: "", want: []float64{50, 90, 95}},
+		{name: "blank", raw: "  ", want: []float64{50, 90, 95}},
+		{name: "all stored", raw: "50,90,95,99", want: []float64{50, 90, 95, 99}},
+		{name: "spaces and order kept", raw: " 99 , 50", want: []float64{99, 50}},
+		{name: "duplicates dropped", raw: "95,95.0,90", want: []float64{95, 90}},
+		{name: "not pre-aggregated", raw: "99.9", wantErr: true},
+		{name: "one bad value", raw: "50,75", wantErr: true},
+		{name: "not a number", raw: "p95", wantErr: true},
+		{name: "empty element", raw: "50,", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePercentiles(tt.raw)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parsePercentiles(%q) = %v, want an error", tt.raw, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePercentiles(%q): %v", tt.raw, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePercentiles(%q) = %v, want %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleTSPercentilesRejectsUnsupported(t *testing.T) {
+	w := httptest.NewRecorder()
+	handleTSPercentiles(nil, nil)(w, httptest.NewRequest("GET", "/grafana/ts/percentiles?percentiles=50,99.9", nil))
+
+	if w.Code != 400 {
+		t.Fatalf("status = %d, want 400", w.Code)
+	}
+	var body struct {
+		Error     string    `json:"error"`
+		Supported []float64 `json:"supported"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("body %q: %v", w.Body, err)
+	}
+	if body.Error == "" {
+		t.Error("no error message")
+	}
+	if want := []float64{50, 90, 95, 99}; !reflect.DeepEqual(body.Supported, want) {
+		t.Errorf("supported = %v, want %v", body.Supported, want)
+	}
+}
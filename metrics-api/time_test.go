@@ -0,0 +1,76 @@
+package main
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestParseFlexibleTime(t *testing.T) {
+	now := time.Date(2026, 3, 4, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "RFC 3339", in: "2026-03-04T10:00:00Z", want: time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)},
+		{name: "RFC 3339 with offset", in: "2026-03-04T10:00:00-03:00", want: time.Date(2026, 3, 4, 13, 0, 0, 0, time.UTC)},
+		{name: "RFC 3339 nano", in: "2026-03-04T10:00:00.123456789Z", want: time.Date(2026, 3, 4, 10, 0, 0, 123456789, time.UTC)},
+		{name: "millis layout", in: "2026-03-04T10:00:00.250Z", want: time.Date(2026, 3, 4, 10, 0, 0, 250e6, time.UTC)},
+		{name: "date only", in: "2026-03-04", want: time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)},
+		{name: "epoch seconds", in: "1772620200", want: time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC)},
+		{name: "epoch millis", in: "1772620200500", want: time.Date(2026, 3, 4, 10, 30, 0, 500e6, time.UTC)},
+		{name: "epoch zero", in: "0", want: time.Unix(0, 0)},
+		{name: "now", in: "now", want: now},
+		{name: "now minus minutes", in: "now-5m", want: now.Add(-5 * time.Minute)},
+		{name: "now minus seconds", in: "now-90s", want: now.Add(-90 * time.Second)},
+		{name: "now minus hours", in: "now-6h", want: now.Add(-6 * time.Hour)},
+		{name: "now minus days", in: "now-7d", want: now.Add(-7 * 24 * time.Hour)},
+		{name: "now minus weeks", in: "now-2w", want: now.Add(-14 * 24 * time.Hour)},
+		{name: "now plus", in: "now+15m", want: now.Add(15 * time.Minute)},
+		{name: "empty", in: "", wantErr: true},
+		{name: "garbage", in: "yesterday", wantErr: true},
+		{name: "date with bad month", in: "2026-13-01", wantErr: true},
+		{name: "fractional epoch", in: "1772620200.5", wantErr: true},
+		{name: "relative unknown unit", in: "now-5y", wantErr: true},
+		{name: "relative without amount", in: "now-m", wantErr: true},
+		{name: "relative without sign", in: "now5m", wantErr: true},
+		{name: "relative negative amount", in: "now--5m", wantErr: true},
+		{name: "relative rounding", in: "now/d", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFlexibleTime(tt.in, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseFlexibleTime(%q) = %v, want an error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseFlexibleTime(%q): %v", tt.in, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseFlexibleTime(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeParam(t *testing.T) {
+	now := time.Date(2026, 3, 4, 12, 30, 0, 0, time.UTC)
+	q := url.Values{"from": {"now-1h"}, "to": {"not a time"}}
+
+	if got, ok := timeParam(q, "from", now); !ok || !got.Equal(now.Add(-time.Hour)) {
+		t.Errorf("from = %v, %v, want %v", got, ok, now.Add(-time.Hour))
+	}
+	if got, ok := timeParam(q, "to", now); ok {
+		t.Errorf("invalid to = %v, want it ignored", got)
+	}
+	if got, ok := timeParam(q, "missing", now); ok {
+		t.Errorf("missing param = %v, want it ignored", got)
+	}
+}
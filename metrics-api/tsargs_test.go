@@ -0,0 +1,109 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBuildTSArgs(t *testing.T) {
+	params := []any{"domain", "test", "exec", "from", "to", 60}
+
+	tests := []struct {
+		name    string
+		query   string
+		params  []any
+		want    []any
+		wantErr bool
+	}{
+		{
+			name:   "all placeholders",
+			query:  "WHERE a = $1 AND b = $2 AND c = $3 AND t >= $4 AND t <= $5 GROUP BY $6",
+			params: params,
+			want:   []any{"domain", "test", "exec", "from", "to", 60},
+		},
+		{
+			name:   "unused trailing params dropped",
+			query:  "WHERE a = $1 AND b = $2 AND c = $3 AND t >= $4 AND t <= $5",
+			params: params,
+			want:   []any{"domain", "test", "exec", "from", "to"},
+		},
+		{
+			name:   "repeated placeholder",
+			query:  "WHERE ($1 = '' OR a = $1) AND b = $2",
+			params: params,
+			want:   []any{"domain", "test"},
+		},
+		{
+			name:   "second pass reuses params",
+			query:  "WHERE a = $1 UNION ALL SELECT WHERE a = $7 AND b = $8",
+			params: params,
+			want:   []any{"domain", "test", "exec", "from", "to", 60, "domain", "test"},
+		},
+		{
+			name:   "two-digit placeholder",
+			query:  "WHERE a = $10",
+			params: params,
+			want:   []any{"domain", "test", "exec", "from", "to", 60, "domain", "test", "exec", "from"},
+		},
+		{
+			name:   "no placeholders",
+			query:  "SELECT 1",
+			params: params,
+			want:   []any{},
+		},
+		{
+			name:    "placeholder beyond two passes",
+			query:   "WHERE a = $13",
+			params:  params,
+			wantErr: true,
+		},
+		{
+			name:    "zero placeholder",
+			query:   "WHERE a = $0",
+			params:  params,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildTSArgs(tt.query, tt.params)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTSMethodFilter(t *testing.T) {
+	from := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	query := "WHERE d = $1 AND t = $2 AND ts >= $3 AND ts <= $4 GROUP BY $5 HAVING ($6 = '' OR m = $6)"
+
+	keys := map[string]string{}
+	for _, method := range []string{"", "GET", "POST"} {
+		key := tsCacheKey("requests", "example.com", "smoke", method, from, to, 60, 0)
+		if other, ok := keys[key]; ok {
+			t.Errorf("methods %q and %q share cache key %s", other, method, key)
+		}
+		keys[key] = method
+
+		args, err := buildTSArgs(query, []any{"example.com", "smoke", from, to, 60.0, method})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := args[len(args)-1]; got != method {
+			t.Errorf("method arg = %v, want %q", got, method)
+		}
+	}
+}
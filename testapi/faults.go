@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const maxTimeoutDelay = 5 * time.Minute
+
+// flaky fails ?rate= of the requests (0 to 1, default 0.5) with a 500, so
+// scripts can produce a known error rate.
+func flaky(w http.ResponseWriter, r *http.Request) {
+	rate := 0.5
+	if raw := r.URL.Query().Get("rate"); raw != "" {
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil || v < 0 || v > 1 {
+			http.Error(w, "rate must be a number between 0 and 1", http.StatusBadRequest)
+			return
+		}
+		rate = v
+	}
+
+	if rand.Float64() < rate {
+		writeJSONStatus(w, http.StatusInternalServerError, map[string]interface{}{
+			"error": "injected failure",
+			"rate":  rate,
+		})
+		return
+	}
+	writeJSON(w, map[string]interface{}{"ok": true, "rate": rate})
+}
+
+// statusCode answers with the status in the path (200-599).
+func statusCode(w http.ResponseWriter, r *http.Request) {
+	code, err := strconv.Atoi(chi.URLParam(r, "code"))
+	if err != nil || code < 200 || code > 599 {
+		http.Error(w, "code must be between 200 and 599", http.StatusBadRequest)
+		return
+	}
+	if code == http.StatusNoContent || code == http.StatusNotModified {
+		w.WriteHeader(code)
+		return
+	}
+	writeJSONStatus(w, code, map[string]interface{}{
+		"status": code,
+		"text":   http.StatusText(code),
+	})
+}
+
+// timeout waits ?ms= milliseconds (default 2000, up to 300000) before
+// answering. With ?hang=true it never answers and only returns when the
+// client gives up, to exercise client-side timeouts.
+func timeout(w http.ResponseWriter, r *http.Request) {
+	delay, err := queryDuration(r, "ms", time.Millisecond, 2*time.Second, maxTimeoutDelay)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	hang, _ := strconv.ParseBool(r.URL.Query().Get("hang"))
+
+	select {
+	case <-r.Context().Done():
+		return
+	case <-time.After(delay):
+	}
+	if hang {
+		<-r.Context().Done()
+		return
+	}
+	writeJSON(w, map[string]interface{}{"message": "delayed response", "delay_ms": delay.Milliseconds()})
+}
+
+func writeJSONStatus(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
@@ -81,6 +81,13 @@ func main() {
 		writeJSON(w, body)
 	})
 
+	r.HandleFunc("/api/flaky", flaky)
+	r.HandleFunc("/api/status/{code}", statusCode)
+	r.HandleFunc("/api/timeout", timeout)
+
+	r.Get("/ws/echo", wsEcho)
+	r.Get("/sse/stream", sseStream)
+
 	fmt.Println("Test API running on :8089")
 	http.ListenAndServe(":8089", r)
 }
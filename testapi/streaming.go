@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	maxEchoDelay      = 10 * time.Second
+	defaultSSEStream  = 10 * time.Second
+	maxSSEStream      = 5 * time.Minute
+	sseTickInterval   = time.Second
+	wsWriteTimeout    = 10 * time.Second
+	wsMaxMessageBytes = 1 << 20
+)
+
+var upgrader = websocket.Upgrader{
+	// Load test target: accept any origin
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// wsEcho sends every text or binary message back to the client, after
+// ?delay_ms= milliseconds (up to 10s) to simulate a slow server.
+func wsEcho(w http.ResponseWriter, r *http.Request) {
+	delay, err := queryDuration(r, "delay_ms", time.Millisecond, 0, maxEchoDelay)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // Upgrade already replied
+	}
+	defer conn.Close()
+	conn.SetReadLimit(wsMaxMessageBytes)
+
+	for {
+		msgType, msg, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if delay > 0 {
+			time.Sleep(delay)
+		}
+		conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+		if err := conn.WriteMessage(msgType, msg); err != nil {
+			return
+		}
+	}
+}
+
+// sseStream emits a "tick" event every second for ?duration= seconds (10 by
+// default, up to 300), then a final "done" event.
+func sseStream(w http.ResponseWriter, r *http.Request) {
+	duration, err := queryDuration(r, "duration", time.Second, defaultSSEStream, maxSSEStream)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(sseTickInterval)
+	defer ticker.Stop()
+	deadline := time.After(duration)
+
+	for seq := 1; ; seq++ {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-deadline:
+			writeEvent(w, "done", seq, map[string]interface{}{"ticks": seq - 1})
+			flusher.Flush()
+			return
+		case now := <-ticker.C:
+			writeEvent(w, "tick", seq, map[string]interface{}{
+				"seq":   seq,
+				"time":  now.UTC().Format(time.RFC3339Nano),
+				"value": float64(rand.Intn(10000)) / 100,
+			})
+			flusher.Flush()
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event string, id int, data interface{}) {
+	payload, _ := json.Marshal(data)
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", id, event, payload)
+}
+
+// queryDuration reads a non-negative integer query parameter in unit,
+// returning def when it is absent.
+func queryDuration(r *http.Request, key string, unit, def, max time.Duration) (time.Duration, error) {
+	raw := r.URL.Query().Get(key)
+	if raw == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 || n > int(max/unit) {
+		return 0, fmt.Errorf("%s must be an integer between 0 and %d", key, max/unit)
+	}
+	return time.Duration(n) * unit, nil
+}